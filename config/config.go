@@ -16,6 +16,20 @@ type Config struct {
 	JWTSecret         string `mapstructure:"JWT_SECRET"`
 	LogLevel          string `mapstructure:"LOG_LEVEL"`
 	MaxRequestsPerMin int    `mapstructure:"MAX_REQUESTS_PER_MIN"`
+	AdminAPIKey       string `mapstructure:"ADMIN_API_KEY"` // Empty disables the admin API
+	CursorSecret      string `mapstructure:"CURSOR_SECRET"` // Signs page cursors; defaults to JWT_SECRET
+
+	AuctionSchedulerMins     int `mapstructure:"AUCTION_SCHEDULER_MINS"`    // How often due auctions are started and expired ones settled
+	AutocompleteRefreshMins  int `mapstructure:"AUTOCOMPLETE_REFRESH_MINS"` // Resync of makes, models and dealers for search suggestions
+	SearchTrendsIntervalMins int `mapstructure:"SEARCH_TRENDS_INTERVAL_MINS"`
+	SavedSearchDigestMins    int `mapstructure:"SAVED_SEARCH_DIGEST_MINS"` // How often due daily digests are sent
+	ValuationRefreshMins     int `mapstructure:"VALUATION_REFRESH_MINS"`   // Reload of sale prices behind valuations and market badges
+
+	DealerListingLifetimeDays int `mapstructure:"DEALER_LISTING_LIFETIME_DAYS"` // 0 keeps dealer listings active indefinitely
+	UserListingLifetimeDays   int `mapstructure:"USER_LISTING_LIFETIME_DAYS"`   // Open-ended bid listings; timed auctions end on their own
+	ListingExpiryReminderDays int `mapstructure:"LISTING_EXPIRY_REMINDER_DAYS"` // Notice given to owners before a listing expires
+	DraftPurgeDays            int `mapstructure:"DRAFT_PURGE_DAYS"`             // Drafts untouched this long are deleted; 0 keeps them
+	ListingLifecycleMins      int `mapstructure:"LISTING_LIFECYCLE_MINS"`       // How often the expiry and draft cleanup jobs run
 
 	RedisAddr     string `mapstructure:"REDIS_ADDR"`
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
@@ -32,6 +46,50 @@ type Config struct {
 	SMTPPassword    string `mapstructure:"SMTP_PASSWORD"`
 	SMTPFrom        string `mapstructure:"SMTP_FROM"`
 	SMTPTimeoutSecs int    `mapstructure:"SMTP_TIMEOUT_SECS"`
+
+	StorageBackend       string `mapstructure:"STORAGE_BACKEND"` // cloudinary, local or s3
+	StorageLocalDir      string `mapstructure:"STORAGE_LOCAL_DIR"`
+	StoragePublicURL     string `mapstructure:"STORAGE_PUBLIC_URL"`
+	StorageSigningSecret string `mapstructure:"STORAGE_SIGNING_SECRET"`
+
+	S3Endpoint  string `mapstructure:"S3_ENDPOINT"`
+	S3Region    string `mapstructure:"S3_REGION"`
+	S3Bucket    string `mapstructure:"S3_BUCKET"`
+	S3AccessKey string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey string `mapstructure:"S3_SECRET_KEY"`
+	S3UseSSL    bool   `mapstructure:"S3_USE_SSL"`
+	S3PathStyle bool   `mapstructure:"S3_PATH_STYLE"`
+
+	TransactionFeePercent float64 `mapstructure:"TRANSACTION_FEE_PERCENT"` // Platform fee charged to the seller
+
+	PaymentGateway           string  `mapstructure:"PAYMENT_GATEWAY"` // fake or mpesa
+	PaymentCallbackURL       string  `mapstructure:"PAYMENT_CALLBACK_URL"`
+	PaymentCallbackSecret    string  `mapstructure:"PAYMENT_CALLBACK_SECRET"`
+	PaymentPendingExpiryMins int     `mapstructure:"PAYMENT_PENDING_EXPIRY_MINS"`
+	PaymentReconcileSecs     int     `mapstructure:"PAYMENT_RECONCILE_SECS"`
+	PaymentFakeCompleteSecs  int     `mapstructure:"PAYMENT_FAKE_COMPLETE_SECS"` // 0 leaves fake pushes pending
+	DepositPercent           float64 `mapstructure:"DEPOSIT_PERCENT"`
+	DepositMinAmount         float64 `mapstructure:"DEPOSIT_MIN_AMOUNT"`
+	PayoutMinAmount          float64 `mapstructure:"PAYOUT_MIN_AMOUNT"` // Dealers owed less wait for the next batch
+	PayoutIntervalHours      int     `mapstructure:"PAYOUT_INTERVAL_HOURS"`
+
+	VINDecoderProviders      string `mapstructure:"VIN_DECODER_PROVIDERS"` // Comma-separated, tried in order after the offline tables: nhtsa, http
+	VINDecoderTimeoutSecs    int    `mapstructure:"VIN_DECODER_TIMEOUT_SECS"`
+	VINDecoderHTTPURL        string `mapstructure:"VIN_DECODER_HTTP_URL"` // Must contain {vin}
+	VINDecoderHTTPAPIKey     string `mapstructure:"VIN_DECODER_HTTP_API_KEY"`
+	VINDecoderHTTPKeyHeader  string `mapstructure:"VIN_DECODER_HTTP_KEY_HEADER"`
+	VINDecoderHTTPMakeField  string `mapstructure:"VIN_DECODER_HTTP_MAKE_FIELD"`
+	VINDecoderHTTPModelField string `mapstructure:"VIN_DECODER_HTTP_MODEL_FIELD"`
+	VINDecoderHTTPYearField  string `mapstructure:"VIN_DECODER_HTTP_YEAR_FIELD"`
+	VINCacheTTLHours         int    `mapstructure:"VIN_CACHE_TTL_HOURS"`
+	VINCacheNegativeTTLHours int    `mapstructure:"VIN_CACHE_NEGATIVE_TTL_HOURS"`
+
+	MpesaBaseURL        string `mapstructure:"MPESA_BASE_URL"`
+	MpesaConsumerKey    string `mapstructure:"MPESA_CONSUMER_KEY"`
+	MpesaConsumerSecret string `mapstructure:"MPESA_CONSUMER_SECRET"`
+	MpesaShortCode      string `mapstructure:"MPESA_SHORTCODE"`
+	MpesaPasskey        string `mapstructure:"MPESA_PASSKEY"`
+	MpesaTillNumber     bool   `mapstructure:"MPESA_TILL_NUMBER"`
 }
 
 // AppConfig is the global configuration instance.
@@ -50,6 +108,18 @@ func LoadConfig() {
 	viper.SetDefault("ENV", "development")
 	viper.SetDefault("LOG_LEVEL", "info")
 	viper.SetDefault("MAX_REQUESTS_PER_MIN", 100)
+	viper.SetDefault("ADMIN_API_KEY", "")
+	viper.SetDefault("CURSOR_SECRET", "")
+	viper.SetDefault("AUCTION_SCHEDULER_MINS", 1)
+	viper.SetDefault("AUTOCOMPLETE_REFRESH_MINS", 15)
+	viper.SetDefault("SEARCH_TRENDS_INTERVAL_MINS", 60)
+	viper.SetDefault("SAVED_SEARCH_DIGEST_MINS", 60)
+	viper.SetDefault("VALUATION_REFRESH_MINS", 60)
+	viper.SetDefault("DEALER_LISTING_LIFETIME_DAYS", 60)
+	viper.SetDefault("USER_LISTING_LIFETIME_DAYS", 30)
+	viper.SetDefault("LISTING_EXPIRY_REMINDER_DAYS", 3)
+	viper.SetDefault("DRAFT_PURGE_DAYS", 30)
+	viper.SetDefault("LISTING_LIFECYCLE_MINS", 15)
 	viper.SetDefault("DATABASE_URL", "mongodb://localhost:27017")
 	viper.SetDefault("REDIS_ADDR", "localhost:6379")
 	viper.SetDefault("REDIS_PASSWORD", "")
@@ -66,6 +136,47 @@ func LoadConfig() {
 	viper.SetDefault("SMTP_FROM", "no-reply@carsawa.com")
 	viper.SetDefault("SMTP_TIMEOUT_SECS", 10)
 
+	viper.SetDefault("STORAGE_BACKEND", "cloudinary")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "./uploads")
+	viper.SetDefault("STORAGE_PUBLIC_URL", "http://localhost:8080")
+	viper.SetDefault("STORAGE_SIGNING_SECRET", "")
+	viper.SetDefault("S3_ENDPOINT", "localhost:9000")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_BUCKET", "carsawa")
+	viper.SetDefault("S3_ACCESS_KEY", "")
+	viper.SetDefault("S3_SECRET_KEY", "")
+	viper.SetDefault("S3_USE_SSL", false)
+	viper.SetDefault("S3_PATH_STYLE", true)
+
+	viper.SetDefault("TRANSACTION_FEE_PERCENT", 2.5)
+
+	viper.SetDefault("PAYMENT_GATEWAY", "fake")
+	viper.SetDefault("PAYMENT_CALLBACK_URL", "http://localhost:8080")
+	viper.SetDefault("PAYMENT_CALLBACK_SECRET", "")
+	viper.SetDefault("PAYMENT_PENDING_EXPIRY_MINS", 15)
+	viper.SetDefault("PAYMENT_RECONCILE_SECS", 60)
+	viper.SetDefault("PAYMENT_FAKE_COMPLETE_SECS", 5)
+	viper.SetDefault("DEPOSIT_PERCENT", 10)
+	viper.SetDefault("DEPOSIT_MIN_AMOUNT", 10000)
+	viper.SetDefault("PAYOUT_MIN_AMOUNT", 1000)
+	viper.SetDefault("PAYOUT_INTERVAL_HOURS", 24)
+	viper.SetDefault("VIN_DECODER_PROVIDERS", "nhtsa")
+	viper.SetDefault("VIN_DECODER_TIMEOUT_SECS", 4)
+	viper.SetDefault("VIN_DECODER_HTTP_URL", "")
+	viper.SetDefault("VIN_DECODER_HTTP_API_KEY", "")
+	viper.SetDefault("VIN_DECODER_HTTP_KEY_HEADER", "X-API-Key")
+	viper.SetDefault("VIN_DECODER_HTTP_MAKE_FIELD", "make")
+	viper.SetDefault("VIN_DECODER_HTTP_MODEL_FIELD", "model")
+	viper.SetDefault("VIN_DECODER_HTTP_YEAR_FIELD", "year")
+	viper.SetDefault("VIN_CACHE_TTL_HOURS", 720)
+	viper.SetDefault("VIN_CACHE_NEGATIVE_TTL_HOURS", 24)
+	viper.SetDefault("MPESA_BASE_URL", "https://sandbox.safaricom.co.ke")
+	viper.SetDefault("MPESA_CONSUMER_KEY", "")
+	viper.SetDefault("MPESA_CONSUMER_SECRET", "")
+	viper.SetDefault("MPESA_SHORTCODE", "174379")
+	viper.SetDefault("MPESA_PASSKEY", "")
+	viper.SetDefault("MPESA_TILL_NUMBER", false)
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Println("No config file found, using environment variables")
 	}
@@ -0,0 +1,97 @@
+// Package keyset pages through sorted Mongo results by position instead of
+// offset. A page is selected by the sort key and _id of the item at its edge,
+// so it stays cheap deep into a collection and does not repeat items when
+// new documents arrive.
+package keyset
+
+import (
+	"carsawa/models"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Sort directions.
+const (
+	Asc  = 1
+	Desc = -1
+)
+
+var ErrCursorMismatch = errors.New("cursor belongs to a different ordering")
+
+// Order is a result ordering on a single field, ties broken by _id in the
+// same direction.
+type Order struct {
+	Name  string // Recorded in cursors, e.g. "createdAt"
+	Field string
+	Dir   int
+}
+
+// Check rejects a cursor minted for a different ordering.
+func (o Order) Check(c *models.Cursor) error {
+	if c != nil && c.Sort != o.Name {
+		return ErrCursorMismatch
+	}
+	return nil
+}
+
+// Filter selects the documents past c in the direction it pages, or nil
+// without a cursor.
+func (o Order) Filter(c *models.Cursor) bson.M {
+	if c == nil {
+		return nil
+	}
+	op := "$gt"
+	if (o.Dir == Desc) != c.Before {
+		op = "$lt"
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{o.Field: bson.M{op: c.Key}},
+		bson.M{o.Field: c.Key, "_id": bson.M{op: c.ID}},
+	}}
+}
+
+// Sort is the sort for fetching a page, reversed when paging backwards.
+func (o Order) Sort(c *models.Cursor) bson.D {
+	dir := o.Dir
+	if c != nil && c.Before {
+		dir = -dir
+	}
+	return bson.D{{Key: o.Field, Value: dir}, {Key: "_id", Value: dir}}
+}
+
+// Page trims a fetch of up to limit+1 items to the page, puts a backward
+// page back into display order and works out the cursors either side.
+// offset is the offset the page was fetched at when no cursor was used.
+func Page[T any](o Order, items []T, limit, offset int, c *models.Cursor, key func(T) (interface{}, primitive.ObjectID)) ([]T, models.PageCursors) {
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+	backward := c != nil && c.Before
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	var cursors models.PageCursors
+	if len(items) == 0 {
+		return items, cursors
+	}
+	hasNext := more
+	hasPrev := offset > 0 || c != nil
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		k, id := key(items[len(items)-1])
+		cursors.Next = &models.Cursor{Sort: o.Name, Key: k, ID: id}
+	}
+	if hasPrev {
+		k, id := key(items[0])
+		cursors.Prev = &models.Cursor{Sort: o.Name, Key: k, ID: id, Before: true}
+	}
+	return items, cursors
+}
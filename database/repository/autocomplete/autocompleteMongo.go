@@ -0,0 +1,185 @@
+package autocompleteRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoAutocompleteRepository) GetTerms(ctx context.Context) ([]models.AutocompleteTerm, error) {
+	cursor, err := r.terms.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load autocomplete terms: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var terms []models.AutocompleteTerm
+	if err := cursor.All(ctx, &terms); err != nil {
+		return nil, fmt.Errorf("failed to decode autocomplete terms: %w", err)
+	}
+	return terms, nil
+}
+
+func (r *MongoAutocompleteRepository) AddListingTerms(ctx context.Context, carMake, model string) ([]models.AutocompleteTerm, error) {
+	carMake, model = strings.TrimSpace(carMake), strings.TrimSpace(model)
+	if carMake == "" {
+		return nil, nil
+	}
+	now := time.Now()
+	terms := []models.AutocompleteTerm{{
+		ID:        TermID(models.SuggestionMake, carMake),
+		Kind:      models.SuggestionMake,
+		Text:      carMake,
+		Listings:  1,
+		UpdatedAt: now,
+	}}
+	if model != "" {
+		terms = append(terms, models.AutocompleteTerm{
+			ID:        TermID(models.SuggestionModel, carMake, model),
+			Kind:      models.SuggestionModel,
+			Text:      model,
+			Make:      carMake,
+			Listings:  1,
+			UpdatedAt: now,
+		})
+	}
+
+	// Known terms are left alone; their counts belong to the sync.
+	writes := make([]mongo.WriteModel, len(terms))
+	for i, t := range terms {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": t.ID}).
+			SetUpdate(bson.M{"$setOnInsert": t}).
+			SetUpsert(true)
+	}
+	if _, err := r.terms.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return nil, fmt.Errorf("failed to add autocomplete terms: %w", err)
+	}
+	return terms, nil
+}
+
+func (r *MongoAutocompleteRepository) IncrementSearches(ctx context.Context, termIDs []string) error {
+	if len(termIDs) == 0 {
+		return nil
+	}
+	_, err := r.terms.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": termIDs}},
+		bson.M{"$inc": bson.M{"searches": 1}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to count autocomplete searches: %w", err)
+	}
+	return nil
+}
+
+// trimmed and lowered mirror TermID inside a pipeline.
+func trimmed(field string) bson.M {
+	return bson.M{"$trim": bson.M{"input": field}}
+}
+
+func lowered(field string) bson.M {
+	return bson.M{"$toLower": trimmed(field)}
+}
+
+// mergeTerms upserts a pipeline's output into the terms collection, keeping
+// each term's search count.
+func (r *MongoAutocompleteRepository) mergeTerms() bson.D {
+	return bson.D{{Key: "$merge", Value: bson.M{
+		"into":           r.terms.Name(),
+		"on":             "_id",
+		"whenMatched":    "merge",
+		"whenNotMatched": "insert",
+	}}}
+}
+
+// SyncListingTerms recounts the live listings behind every make and model.
+// Terms no live listing carries any more drop to zero rather than being
+// removed, so their search counts are kept if the car comes back.
+func (r *MongoAutocompleteRepository) SyncListingTerms(ctx context.Context) error {
+	now := time.Now()
+	live := bson.D{{Key: "$match", Value: bson.M{
+		"status":          bson.M{"$in": models.LiveListingStatuses},
+		"carDetails.make": bson.M{"$nin": bson.A{"", nil}},
+	}}}
+
+	makes := mongo.Pipeline{
+		live,
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"$concat": bson.A{models.SuggestionMake + ":", lowered("$carDetails.make")}},
+			"text":     bson.M{"$first": trimmed("$carDetails.make")},
+			"listings": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$set", Value: bson.M{"kind": models.SuggestionMake, "updatedAt": now}}},
+		r.mergeTerms(),
+	}
+	carModels := mongo.Pipeline{
+		live,
+		{{Key: "$match", Value: bson.M{"carDetails.model": bson.M{"$nin": bson.A{"", nil}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$concat": bson.A{
+				models.SuggestionModel + ":", lowered("$carDetails.make"), ":", lowered("$carDetails.model"),
+			}},
+			"text":     bson.M{"$first": trimmed("$carDetails.model")},
+			"make":     bson.M{"$first": trimmed("$carDetails.make")},
+			"listings": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$set", Value: bson.M{"kind": models.SuggestionModel, "updatedAt": now}}},
+		r.mergeTerms(),
+	}
+	for _, pipeline := range []mongo.Pipeline{makes, carModels} {
+		cursor, err := r.listings.Aggregate(ctx, pipeline)
+		if err != nil {
+			return fmt.Errorf("failed to sync listing terms: %w", err)
+		}
+		cursor.Close(ctx)
+	}
+
+	_, err := r.terms.UpdateMany(ctx,
+		bson.M{
+			"kind":      bson.M{"$in": bson.A{models.SuggestionMake, models.SuggestionModel}},
+			"updatedAt": bson.M{"$lt": now},
+		},
+		bson.M{"$set": bson.M{"listings": 0, "updatedAt": now}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear stale listing terms: %w", err)
+	}
+	return nil
+}
+
+// SyncDealerTerms copies every dealer's name and slug into the terms and
+// drops the terms of dealers that have gone.
+func (r *MongoAutocompleteRepository) SyncDealerTerms(ctx context.Context) error {
+	now := time.Now()
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"profile.dealerName": bson.M{"$nin": bson.A{"", nil}}}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       bson.M{"$concat": bson.A{models.SuggestionDealer + ":", bson.M{"$toString": "$_id"}}},
+			"kind":      models.SuggestionDealer,
+			"text":      trimmed("$profile.dealerName"),
+			"slug":      "$profile.slug",
+			"updatedAt": now,
+		}}},
+		r.mergeTerms(),
+	}
+	cursor, err := r.dealers.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to sync dealer terms: %w", err)
+	}
+	cursor.Close(ctx)
+
+	_, err = r.terms.DeleteMany(ctx, bson.M{
+		"kind":      models.SuggestionDealer,
+		"updatedAt": bson.M{"$lt": now},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to drop stale dealer terms: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package autocompleteRepo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoAutocompleteRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.terms.Indexes().CreateOne(ctx, mongo.IndexModel{
+		// Sweeps for terms a sync no longer saw.
+		Keys: bson.D{
+			{Key: "kind", Value: 1},
+			{Key: "updatedAt", Value: 1},
+		},
+		Options: options.Index().SetName("kind_updatedAt"),
+	})
+	return err
+}
@@ -0,0 +1,51 @@
+package autocompleteRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AutocompleteRepository stores the terms behind search autocomplete. Make
+// and model terms are rebuilt from live listings and dealer terms from
+// dealer profiles; search counts survive the rebuilds.
+type AutocompleteRepository interface {
+	GetTerms(ctx context.Context) ([]models.AutocompleteTerm, error)
+	// AddListingTerms makes a listing's make and model suggestible before the
+	// next sync counts it.
+	AddListingTerms(ctx context.Context, carMake, model string) ([]models.AutocompleteTerm, error)
+	IncrementSearches(ctx context.Context, termIDs []string) error
+	SyncListingTerms(ctx context.Context) error
+	SyncDealerTerms(ctx context.Context) error
+}
+
+type MongoAutocompleteRepository struct {
+	terms    *mongo.Collection
+	listings *mongo.Collection
+	dealers  *mongo.Collection
+}
+
+func NewMongoAutocompleteRepo(db *mongo.Database) *MongoAutocompleteRepository {
+	r := &MongoAutocompleteRepository{
+		terms:    db.Collection("autocomplete_terms"),
+		listings: db.Collection("listings"),
+		dealers:  db.Collection("dealers"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create autocomplete indexes: %v\n", err)
+	}
+	return r
+}
+
+// TermID keys a term by kind and its lower-cased parts, matching the keys
+// the sync pipelines build, so "Toyota" and "toyota " are one make.
+func TermID(kind string, parts ...string) string {
+	id := kind
+	for _, p := range parts {
+		id += ":" + strings.ToLower(strings.TrimSpace(p))
+	}
+	return id
+}
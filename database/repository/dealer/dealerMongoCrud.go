@@ -5,6 +5,7 @@ import (
 	"carsawa/models"
 	"context"
 	"errors"
+	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,10 +18,17 @@ type mongoDealerRepo struct {
 
 // NewMongoDealerRepo creates a new instance of mongoDealerRepo.
 func NewMongoDealerRepo(db *mongo.Database) DealerRepository {
-	return &mongoDealerRepo{
+	r := &mongoDealerRepo{
 		collection: db.Collection("dealers"),
 		ctx:        context.Background(),
 	}
+	if err := r.unsetEmptyGeoPoints(); err != nil {
+		fmt.Printf("failed to migrate dealer locations: %v\n", err)
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create dealer indexes: %v\n", err)
+	}
+	return r
 }
 
 // CreateDealer inserts a new dealer document.
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -52,6 +53,53 @@ func (r *mongoDealerRepo) GetAllDealersWithProjection(projection bson.M) ([]mode
 	return dealers, nil
 }
 
+// dealerGeoField is the 2dsphere-indexed location of the dealer's yard.
+const dealerGeoField = "profile.location.geoPoint"
+
+// GetDealersNear retrieves dealers nearest first around near using $geoNear.
+func (r *mongoDealerRepo) GetDealersNear(near models.GeoQuery, projection bson.M, limit int) ([]NearbyDealer, error) {
+	ctx, cancel := newContext(10 * time.Second)
+	defer cancel()
+
+	geoNear := bson.M{
+		"near":               near.Point(),
+		"key":                dealerGeoField,
+		"distanceField":      "distanceKm",
+		"distanceMultiplier": 0.001, // metres to kilometres
+		"spherical":          true,
+	}
+	if near.RadiusKm > 0 {
+		geoNear["maxDistance"] = near.RadiusKm * 1000
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: geoNear}},
+		{{Key: "$limit", Value: limit}},
+	}
+	if len(projection) > 0 {
+		// an inclusion projection would drop the computed distance
+		fields := bson.M{}
+		for k, v := range projection {
+			fields[k] = v
+			if k != "_id" && (v == 1 || v == true) {
+				fields["distanceKm"] = 1
+			}
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: fields}})
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dealers []NearbyDealer
+	if err := cursor.All(ctx, &dealers); err != nil {
+		return nil, err
+	}
+	return dealers, nil
+}
+
 // GetDealerBySlug retrieves a dealer by its public slug.
 func (r *mongoDealerRepo) GetDealerBySlug(slug string) (*models.Dealer, error) {
 	var dealer models.Dealer
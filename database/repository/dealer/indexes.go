@@ -22,6 +22,8 @@ func (r *mongoDealerRepo) ensureIndexes() error {
 		{Keys: bson.D{{Key: "profile.slug", Value: 1}}, Options: options.Index().SetUnique(true)},
 		// Unique index on the provider's email stored in "profile.dealerName".
 		{Keys: bson.D{{Key: "profile.dealerName", Value: 1}}, Options: options.Index().SetUnique(true)},
+		// Geo index for nearest-dealer search.
+		{Keys: bson.D{{Key: dealerGeoField, Value: "2dsphere"}}},
 	}
 
 	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
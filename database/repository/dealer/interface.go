@@ -32,6 +32,10 @@ type DealerRepository interface {
 	// GetAllDealersWithProjection retrieves all dealers with the given projection.
 	GetAllDealersWithProjection(projection bson.M) ([]models.Dealer, error)
 
+	// GetDealersNear retrieves dealers nearest first around near, within its
+	// radius when one is set.
+	GetDealersNear(near models.GeoQuery, projection bson.M, limit int) ([]NearbyDealer, error)
+
 	// GetDealerBySlug retrieves a dealer by its public slug.
 	GetDealerBySlug(slug string) (*models.Dealer, error)
 
@@ -39,6 +43,13 @@ type DealerRepository interface {
 	IsDealerAvailable(models.DealerBasicRegistrationData) (bool, error)
 }
 
+// NearbyDealer is a dealer found by a geo search with its distance from the
+// search centre.
+type NearbyDealer struct {
+	models.Dealer `bson:",inline"`
+	DistanceKm    float64 `bson:"distanceKm"`
+}
+
 // newContext creates a context with the given timeout.
 func newContext(timeout time.Duration) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), timeout)
@@ -0,0 +1,33 @@
+package dealerRepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// unsetEmptyGeoPoints removes the blank {type: "", coordinates: null} points
+// written for dealers saved before geoPoint was omitted when unset. The
+// 2dsphere index refuses to build while any remain.
+func (r *mongoDealerRepo) unsetEmptyGeoPoints() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			dealerGeoField: bson.M{"$exists": true},
+			"$or": bson.A{
+				bson.M{dealerGeoField + ".type": bson.M{"$ne": "Point"}},
+				bson.M{dealerGeoField + ".coordinates": bson.M{"$not": bson.M{"$size": 2}}},
+			},
+		},
+		bson.M{"$unset": bson.M{dealerGeoField: ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to unset empty geo points: %w", err)
+	}
+	return nil
+}
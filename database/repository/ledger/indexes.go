@@ -0,0 +1,82 @@
+package ledgerRepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoLedgerRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	entryIndexes := []mongo.IndexModel{
+		{
+			// One entry per business event; replays fail instead of double-posting.
+			Keys:    bson.D{{Key: "reference", Value: 1}},
+			Options: options.Index().SetName("reference").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "lines.account", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("account_createdAt"),
+		},
+		{
+			Keys: bson.D{{Key: "transactionId", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "dealerId", Value: 1}},
+		},
+	}
+	if _, err := r.entries.Indexes().CreateMany(ctx, entryIndexes); err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	accountIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "type", Value: 1},
+				{Key: "balance", Value: 1},
+			},
+			Options: options.Index().SetName("type_balance"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "dealerId", Value: 1},
+				{Key: "type", Value: 1},
+			},
+			Options: options.Index().SetName("dealerId_type"),
+		},
+	}
+	if _, err := r.accounts.Indexes().CreateMany(ctx, accountIndexes); err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	payoutIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "dealerId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("dealerId_createdAt"),
+		},
+		{
+			// A batch pays each dealer at most once.
+			Keys: bson.D{
+				{Key: "batchId", Value: 1},
+				{Key: "dealerId", Value: 1},
+			},
+			Options: options.Index().SetName("batchId_dealerId").SetUnique(true),
+		},
+	}
+	if _, err := r.payouts.Indexes().CreateMany(ctx, payoutIndexes); err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+	return nil
+}
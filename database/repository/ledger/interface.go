@@ -0,0 +1,72 @@
+package ledgerRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrDuplicateEntry  = errors.New("journal entry already posted")
+	ErrUnbalancedEntry = errors.New("journal entry debits and credits do not balance")
+	ErrAccountNotFound = errors.New("ledger account not found")
+	ErrPayoutNotFound  = errors.New("payout not found")
+	ErrPayoutConflict  = errors.New("payout has already been completed")
+	ErrBelowMinimum    = errors.New("balance is below the minimum payout")
+	ErrInvalidID       = errors.New("invalid ID")
+)
+
+// BalanceFunc reads an account balance inside the posting session; unknown
+// accounts have a zero balance.
+type BalanceFunc func(accountID string) (int64, error)
+
+// EntryBuilder builds an entry from balances read in the same session. A nil
+// entry posts nothing.
+type EntryBuilder func(balance BalanceFunc) (*models.JournalEntry, error)
+
+type LedgerRepository interface {
+	// PostEntry inserts a journal entry and applies it to account balances in
+	// one Mongo transaction.
+	PostEntry(ctx context.Context, build EntryBuilder) (*models.JournalEntry, error)
+	GetAccount(ctx context.Context, id string) (*models.LedgerAccount, error)
+	GetEntries(ctx context.Context, filter JournalFilter, pagination models.Pagination) ([]models.JournalEntry, error)
+
+	// Dealer balances
+	GetPayableAccounts(ctx context.Context, minAmount int64, limit int) ([]models.LedgerAccount, error)
+	SumEscrowForDealer(ctx context.Context, dealerID primitive.ObjectID) (int64, error)
+	SumPaidOut(ctx context.Context, dealerID primitive.ObjectID) (float64, error)
+
+	// Payouts
+	SchedulePayout(ctx context.Context, dealerID primitive.ObjectID, batchID string, minAmount int64) (*models.Payout, error)
+	CompletePayout(ctx context.Context, payoutID string, paid bool, reference string) (*models.Payout, error)
+	GetPayouts(ctx context.Context, dealerID primitive.ObjectID, pagination models.Pagination) ([]models.Payout, error)
+}
+
+// JournalFilter narrows GetEntries; zero fields are ignored.
+type JournalFilter struct {
+	Account       string
+	TransactionID *primitive.ObjectID
+	DealerID      *primitive.ObjectID
+}
+
+type MongoLedgerRepository struct {
+	accounts *mongo.Collection
+	entries  *mongo.Collection
+	payouts  *mongo.Collection
+}
+
+func NewMongoLedgerRepo(db *mongo.Database) *MongoLedgerRepository {
+	r := &MongoLedgerRepository{
+		accounts: db.Collection("ledger_accounts"),
+		entries:  db.Collection("journal_entries"),
+		payouts:  db.Collection("payouts"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create ledger indexes: %v\n", err)
+	}
+	return r
+}
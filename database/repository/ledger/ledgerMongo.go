@@ -0,0 +1,399 @@
+package ledgerRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoLedgerRepository) PostEntry(ctx context.Context, build EntryBuilder) (*models.JournalEntry, error) {
+	session, err := r.entries.Database().Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	res, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		entry, err := build(r.balanceReader(sessCtx))
+		if err != nil || entry == nil {
+			return nil, err
+		}
+		if err := r.post(sessCtx, entry); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	entry, _ := res.(*models.JournalEntry)
+	return entry, nil
+}
+
+func (r *MongoLedgerRepository) balanceReader(sessCtx mongo.SessionContext) BalanceFunc {
+	return func(accountID string) (int64, error) {
+		var acct models.LedgerAccount
+		err := r.accounts.FindOne(sessCtx, bson.M{"_id": accountID}).Decode(&acct)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read balance: %w", err)
+		}
+		return acct.Balance, nil
+	}
+}
+
+// ValidateEntry checks that an entry has at least two lines, each with exactly
+// one positive side, and that its debits and credits net to zero.
+func ValidateEntry(entry *models.JournalEntry) error {
+	if len(entry.Lines) < 2 {
+		return ErrUnbalancedEntry
+	}
+	var net int64
+	for _, l := range entry.Lines {
+		if l.Debit < 0 || l.Credit < 0 || (l.Debit == 0) == (l.Credit == 0) {
+			return fmt.Errorf("%w: line on %s must have one positive side", ErrUnbalancedEntry, l.Account)
+		}
+		net += l.Debit - l.Credit
+	}
+	if net != 0 {
+		return ErrUnbalancedEntry
+	}
+	return nil
+}
+
+// post writes an entry and its balance changes; it must run inside a session
+// transaction so a failure on any line rolls back the whole entry.
+func (r *MongoLedgerRepository) post(sessCtx mongo.SessionContext, entry *models.JournalEntry) error {
+	if err := ValidateEntry(entry); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	entry.CreatedAt = now
+	if _, err := r.entries.InsertOne(sessCtx, entry); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateEntry
+		}
+		return fmt.Errorf("failed to insert journal entry: %w", err)
+	}
+
+	for _, l := range entry.Lines {
+		_, err := r.accounts.UpdateOne(
+			sessCtx,
+			bson.M{"_id": l.Account},
+			bson.M{
+				"$inc":         bson.M{"balance": l.Debit - l.Credit},
+				"$set":         bson.M{"updatedAt": now},
+				"$setOnInsert": accountDefaults(l.Account, entry, now),
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update account %s: %w", l.Account, err)
+		}
+	}
+	return nil
+}
+
+// accountDefaults derives a new account's type and owner from its ID; escrow
+// accounts take their beneficiary dealer from the entry that opens them.
+func accountDefaults(accountID string, entry *models.JournalEntry, now time.Time) bson.M {
+	typ, ownerHex, _ := strings.Cut(accountID, ":")
+	doc := bson.M{
+		"type":      models.LedgerAccountType(typ),
+		"currency":  entry.Currency,
+		"createdAt": now,
+	}
+	if owner, err := primitive.ObjectIDFromHex(ownerHex); err == nil {
+		doc["ownerId"] = owner
+		switch models.LedgerAccountType(typ) {
+		case models.LedgerAccountDealerPayable:
+			doc["dealerId"] = owner
+		case models.LedgerAccountEscrow:
+			if entry.DealerID != nil {
+				doc["dealerId"] = *entry.DealerID
+			}
+		}
+	}
+	return doc
+}
+
+func (r *MongoLedgerRepository) GetAccount(ctx context.Context, id string) (*models.LedgerAccount, error) {
+	var acct models.LedgerAccount
+	if err := r.accounts.FindOne(ctx, bson.M{"_id": id}).Decode(&acct); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	return &acct, nil
+}
+
+func (r *MongoLedgerRepository) GetEntries(
+	ctx context.Context,
+	filter JournalFilter,
+	pagination models.Pagination,
+) ([]models.JournalEntry, error) {
+	query := bson.M{}
+	if filter.Account != "" {
+		query["lines.account"] = filter.Account
+	}
+	if filter.TransactionID != nil {
+		query["transactionId"] = *filter.TransactionID
+	}
+	if filter.DealerID != nil {
+		query["dealerId"] = *filter.DealerID
+	}
+
+	opts := options.Find().
+		SetLimit(int64(pagination.Limit)).
+		SetSkip(int64(pagination.Offset)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.entries.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+	var results []models.JournalEntry
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetPayableAccounts returns dealer payable accounts owing at least minAmount.
+// Payables are credit balances, so they are stored as negative numbers.
+func (r *MongoLedgerRepository) GetPayableAccounts(ctx context.Context, minAmount int64, limit int) ([]models.LedgerAccount, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "balance", Value: 1}})
+
+	cursor, err := r.accounts.Find(ctx, bson.M{
+		"type":    models.LedgerAccountDealerPayable,
+		"balance": bson.M{"$lte": -minAmount, "$lt": 0},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payable accounts: %w", err)
+	}
+	var results []models.LedgerAccount
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SumEscrowForDealer totals what escrow holds on the dealer's open sales.
+func (r *MongoLedgerRepository) SumEscrowForDealer(ctx context.Context, dealerID primitive.ObjectID) (int64, error) {
+	cursor, err := r.accounts.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"dealerId": dealerID, "type": models.LedgerAccountEscrow}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$balance"}}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum escrow: %w", err)
+	}
+	var out []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &out); err != nil {
+		return 0, err
+	}
+	if len(out) == 0 {
+		return 0, nil
+	}
+	return -out[0].Total, nil
+}
+
+// SumPaidOut totals payouts that have left (or are leaving) the dealer's payable.
+func (r *MongoLedgerRepository) SumPaidOut(ctx context.Context, dealerID primitive.ObjectID) (float64, error) {
+	cursor, err := r.payouts.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"dealerId": dealerID,
+			"status":   bson.M{"$in": []models.PayoutStatus{models.PayoutStatusScheduled, models.PayoutStatusPaid}},
+		}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$amount"}}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum payouts: %w", err)
+	}
+	var out []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &out); err != nil {
+		return 0, err
+	}
+	if len(out) == 0 {
+		return 0, nil
+	}
+	return out[0].Total, nil
+}
+
+// SchedulePayout moves the dealer's whole payable balance out in one session:
+// the payout record and its journal entry are written together or not at all.
+func (r *MongoLedgerRepository) SchedulePayout(
+	ctx context.Context,
+	dealerID primitive.ObjectID,
+	batchID string,
+	minAmount int64,
+) (*models.Payout, error) {
+	session, err := r.entries.Database().Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	payable := models.LedgerAccountID(models.LedgerAccountDealerPayable, dealerID)
+	res, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		balance, err := r.balanceReader(sessCtx)(payable)
+		if err != nil {
+			return nil, err
+		}
+		owed := -balance
+		if owed <= 0 || owed < minAmount {
+			return nil, ErrBelowMinimum
+		}
+
+		now := time.Now()
+		payout := &models.Payout{
+			ID:        primitive.NewObjectID(),
+			DealerID:  dealerID,
+			BatchID:   batchID,
+			Amount:    float64(owed) / 100,
+			Currency:  models.CurrencyKES,
+			Status:    models.PayoutStatusScheduled,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if _, err := r.payouts.InsertOne(sessCtx, payout); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return nil, ErrDuplicateEntry
+			}
+			return nil, fmt.Errorf("failed to create payout: %w", err)
+		}
+
+		err = r.post(sessCtx, &models.JournalEntry{
+			Kind:      models.JournalPayout,
+			Reference: "payout:" + payout.ID.Hex(),
+			PayoutID:  &payout.ID,
+			DealerID:  &dealerID,
+			Currency:  models.CurrencyKES,
+			Memo:      "Payout batch " + batchID,
+			Lines: []models.JournalLine{
+				{Account: payable, Debit: owed},
+				{Account: models.GatewayAccount, Credit: owed},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return payout, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*models.Payout), nil
+}
+
+// CompletePayout records the transfer's outcome; a failed transfer credits
+// the amount back to the dealer's payable in the same session.
+func (r *MongoLedgerRepository) CompletePayout(ctx context.Context, payoutID string, paid bool, reference string) (*models.Payout, error) {
+	objID, err := primitive.ObjectIDFromHex(payoutID)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	session, err := r.entries.Database().Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	res, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		status := models.PayoutStatusPaid
+		if !paid {
+			status = models.PayoutStatusFailed
+		}
+		now := time.Now()
+
+		var payout models.Payout
+		err := r.payouts.FindOneAndUpdate(
+			sessCtx,
+			bson.M{"_id": objID, "status": models.PayoutStatusScheduled},
+			bson.M{"$set": bson.M{
+				"status":      status,
+				"reference":   reference,
+				"completedAt": now,
+				"updatedAt":   now,
+			}},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&payout)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			n, cerr := r.payouts.CountDocuments(sessCtx, bson.M{"_id": objID})
+			if cerr == nil && n > 0 {
+				return nil, ErrPayoutConflict
+			}
+			return nil, ErrPayoutNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete payout: %w", err)
+		}
+
+		if !paid {
+			amount := int64(math.Round(payout.Amount * 100))
+			err = r.post(sessCtx, &models.JournalEntry{
+				Kind:      models.JournalPayoutReverse,
+				Reference: "payout_reverse:" + payout.ID.Hex(),
+				PayoutID:  &payout.ID,
+				DealerID:  &payout.DealerID,
+				Currency:  payout.Currency,
+				Memo:      "Payout failed: " + reference,
+				Lines: []models.JournalLine{
+					{Account: models.GatewayAccount, Debit: amount},
+					{Account: models.LedgerAccountID(models.LedgerAccountDealerPayable, payout.DealerID), Credit: amount},
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &payout, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*models.Payout), nil
+}
+
+func (r *MongoLedgerRepository) GetPayouts(
+	ctx context.Context,
+	dealerID primitive.ObjectID,
+	pagination models.Pagination,
+) ([]models.Payout, error) {
+	opts := options.Find().
+		SetLimit(int64(pagination.Limit)).
+		SetSkip(int64(pagination.Offset)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.payouts.Find(ctx, bson.M{"dealerId": dealerID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payouts: %w", err)
+	}
+	var results []models.Payout
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,144 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ActivateDueAuctions flips scheduled auctions whose start time has passed to live.
+func (r *MongoListingsRepository) ActivateDueAuctions(ctx context.Context, now time.Time) (int64, error) {
+	res, err := r.listings.UpdateMany(
+		ctx,
+		bson.M{
+			"type":                        models.ListingTypeUserBid,
+			"status":                      models.ListingStatusOpen,
+			"userListing.auction.status":  models.AuctionStatusScheduled,
+			"userListing.auction.startAt": bson.M{"$lte": now},
+		},
+		bson.M{"$set": bson.M{
+			"userListing.auction.status": models.AuctionStatusLive,
+			"updatedAt":                  now,
+		}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to activate auctions: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+// biddingOpenFilter matches listings taking bids at now: open-ended listings
+// always, auctions only between their start and end.
+func biddingOpenFilter(now time.Time) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"userListing.auction": bson.M{"$exists": false}},
+		{
+			"userListing.auction.startAt": bson.M{"$lte": now},
+			"userListing.auction.endAt":   bson.M{"$gt": now},
+		},
+	}}
+}
+
+// outbidsFilter matches listings on which offer may stand as a bid:
+// open-ended listings take any offer, auctions only one that clears every
+// standing bid by the auction's minimum increment. Being part of the write's
+// filter, two bids racing against the same high bid cannot both land.
+func outbidsFilter(offer float64) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"userListing.auction": bson.M{"$exists": false}},
+		{"$expr": bson.M{"$lte": bson.A{
+			bson.M{"$max": "$userListing.bids.offer"},
+			bson.M{"$subtract": bson.A{offer, bson.M{"$ifNull": bson.A{"$userListing.auction.minIncrement", 0}}}},
+		}}},
+	}}
+}
+
+// GetExpiredAuctions returns open auction listings whose end time has passed.
+func (r *MongoListingsRepository) GetExpiredAuctions(ctx context.Context, now time.Time, limit int) ([]models.Listing, error) {
+	filter := bson.M{
+		"type":                      models.ListingTypeUserBid,
+		"status":                    models.ListingStatusOpen,
+		"userListing.auction.endAt": bson.M{"$lte": now},
+		"userListing.auction.status": bson.M{"$in": []models.AuctionStatus{
+			models.AuctionStatusScheduled,
+			models.AuctionStatusLive,
+		}},
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "userListing.auction.endAt", Value: 1}})
+
+	cursor, err := r.listings.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired auctions: %w", err)
+	}
+
+	var results []models.Listing
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CloseAuction settles an expired auction. A nil winningBid records a no-sale and
+// closes the listing; otherwise the winning bid is accepted. The update only applies
+// while the listing is still open, so concurrent schedulers cannot settle it twice.
+func (r *MongoListingsRepository) CloseAuction(ctx context.Context, listingID string, winningBid *primitive.ObjectID, closedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	set := bson.M{
+		"userListing.auction.closedAt": closedAt,
+		"updatedAt":                    time.Now(),
+	}
+	if winningBid != nil {
+		set["status"] = models.ListingStatusAccepted
+		set["userListing.acceptedBid"] = *winningBid
+		set["userListing.auction.winningBid"] = *winningBid
+		set["userListing.auction.status"] = models.AuctionStatusSold
+	} else {
+		set["status"] = models.ListingStatusClosed
+		set["userListing.auction.status"] = models.AuctionStatusNoSale
+	}
+
+	// Settle the negotiation state of every live bid along with the listing.
+	var filters []interface{}
+	liveBid := bson.M{"$in": activeBidStatuses}
+	if winningBid != nil {
+		set["userListing.bids.$[win].status"] = models.BidStatusAccepted
+		set["userListing.bids.$[other].status"] = models.BidStatusRejected
+		filters = append(filters,
+			bson.M{"win._id": *winningBid},
+			bson.M{"other._id": bson.M{"$ne": *winningBid}, "other.status": liveBid},
+		)
+	} else {
+		set["userListing.bids.$[other].status"] = models.BidStatusRejected
+		filters = append(filters, bson.M{"other.status": liveBid})
+	}
+
+	res, err := r.listings.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":    objID,
+			"type":   models.ListingTypeUserBid,
+			"status": models.ListingStatusOpen,
+		},
+		bson.M{"$set": set},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: filters}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to close auction: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
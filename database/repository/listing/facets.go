@@ -0,0 +1,241 @@
+package listingRepo
+
+import (
+	"carsawa/database/keyset"
+	"carsawa/models"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	makeFacetLimit  = 20
+	modelFacetLimit = 30
+	cityFacetLimit  = 20
+)
+
+// Bucket boundaries for the year and price facets. The last boundary only
+// closes the top bucket, which is shown as open-ended.
+var (
+	yearBuckets  = []float64{1900, 2000, 2005, 2010, 2015, 2020, 3000}
+	priceBuckets = []float64{1, 500_000, 1_000_000, 2_000_000, 3_000_000, 5_000_000, 10_000_000, 1e15}
+)
+
+// FacetedSearch returns a page of live listings matching query and filter
+// together with facet counts over every match, in a single aggregation.
+// Results are ranked by text relevance when query is set, nearest first when
+// only filter.Near is, and newest first otherwise.
+func (r *MongoListingsRepository) FacetedSearch(ctx context.Context, query string, filter models.ListingFilter, pagination models.Pagination) (*models.SearchResult, error) {
+	match := buildListingQuery(filter)
+	match["status"] = bson.M{"$in": []models.ListingStatus{
+		models.ListingStatusActive,
+		models.ListingStatusOpen,
+	}}
+	query = strings.TrimSpace(query)
+
+	var (
+		pipeline mongo.Pipeline
+		order    keyset.Order
+		key      func(models.Listing) (interface{}, primitive.ObjectID)
+	)
+	switch {
+	case query != "":
+		match["$text"] = bson.M{"$search": query}
+		if filter.Near != nil && filter.Near.RadiusKm > 0 {
+			match[geoField] = geoWithin(*filter.Near)
+		}
+		// copy the score into the document so the results facet can sort on it
+		pipeline = mongo.Pipeline{
+			{{Key: "$match", Value: match}},
+			{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+		}
+		order, key = bestMatch, scoreKey
+	case filter.Near != nil:
+		geoNear := bson.M{
+			"near":               filter.Near.Point(),
+			"key":                geoField,
+			"distanceField":      "distanceKm",
+			"distanceMultiplier": 0.001, // metres to kilometres
+			"spherical":          true,
+			"query":              match,
+		}
+		if filter.Near.RadiusKm > 0 {
+			geoNear["maxDistance"] = filter.Near.RadiusKm * 1000
+		}
+		pipeline = mongo.Pipeline{{{Key: "$geoNear", Value: geoNear}}}
+		order, key = nearestFirst, distanceKey
+	default:
+		pipeline = mongo.Pipeline{{{Key: "$match", Value: match}}}
+		order, key = newestFirst, createdKey
+	}
+	if err := order.Check(pagination.Cursor); err != nil {
+		return nil, err
+	}
+
+	// the cursor only narrows the page; facets count every match
+	results := bson.A{}
+	if f := order.Filter(pagination.Cursor); f != nil {
+		results = append(results, bson.M{"$match": f})
+	}
+	skip, limit := pageWindow(pagination)
+	results = append(results,
+		bson.M{"$sort": order.Sort(pagination.Cursor)},
+		bson.M{"$skip": skip},
+		bson.M{"$limit": limit},
+		bson.M{"$project": listingCardProjection},
+	)
+
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"results":   results,
+		"total":     bson.A{bson.M{"$count": "n"}},
+		"makes":     countBy("$carDetails.make", makeFacetLimit),
+		"models":    countBy("$carDetails.model", modelFacetLimit),
+		"bodyTypes": countBy("$carDetails.bodyType", 0),
+		"cities":    countBy("$carDetails.location.city", cityFacetLimit),
+		"years":     bucketBy("$carDetails.year", yearBuckets),
+		"prices":    bucketBy("$carDetails.price", priceBuckets),
+	}}})
+
+	cursor, err := r.listings.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search listings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var out struct {
+		Results []models.Listing `bson:"results"`
+		Total   []struct {
+			N int `bson:"n"`
+		} `bson:"total"`
+		Makes     []facetRow `bson:"makes"`
+		Models    []facetRow `bson:"models"`
+		BodyTypes []facetRow `bson:"bodyTypes"`
+		Cities    []facetRow `bson:"cities"`
+		Years     []facetRow `bson:"years"`
+		Prices    []facetRow `bson:"prices"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&out); err != nil {
+			return nil, fmt.Errorf("failed to decode search results: %w", err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search listings: %w", err)
+	}
+
+	listings, cursors := keyset.Page(order, out.Results, pagination.Limit, pagination.Offset, pagination.Cursor, key)
+	if query != "" && filter.Near != nil {
+		setDistances(listings, *filter.Near)
+	}
+	res := &models.SearchResult{
+		Listings: listings,
+		Cursors:  cursors,
+		Facets: &models.SearchFacets{
+			Makes:     valueCounts(out.Makes),
+			Models:    valueCounts(out.Models),
+			BodyTypes: valueCounts(out.BodyTypes),
+			Cities:    valueCounts(out.Cities),
+			Years:     bucketCounts(out.Years, yearBuckets, "%.0f"),
+			Prices:    bucketCounts(out.Prices, priceBuckets, "%.0f"),
+		},
+	}
+	if len(out.Total) > 0 {
+		res.Total = out.Total[0].N
+	}
+	return res, nil
+}
+
+// facetRow is one group from a $facet sub-pipeline: a value for countBy, a
+// lower bound for bucketBy.
+type facetRow struct {
+	ID    interface{} `bson:"_id"`
+	Count int         `bson:"count"`
+}
+
+// countBy counts matches per distinct value of field, most common first.
+// A limit of zero keeps every value.
+func countBy(field string, limit int) bson.A {
+	stages := bson.A{
+		bson.M{"$match": bson.M{strings.TrimPrefix(field, "$"): bson.M{"$nin": bson.A{"", nil}}}},
+		bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+	if limit > 0 {
+		stages = append(stages, bson.M{"$limit": limit})
+	}
+	return stages
+}
+
+// bucketBy counts matches per range of field. Values outside the boundaries,
+// such as user bid listings without a price, are left out.
+func bucketBy(field string, boundaries []float64) bson.A {
+	bounds := bson.A{}
+	for _, b := range boundaries {
+		bounds = append(bounds, b)
+	}
+	key := strings.TrimPrefix(field, "$")
+	return bson.A{
+		bson.M{"$match": bson.M{key: bson.M{
+			"$gte": boundaries[0],
+			"$lt":  boundaries[len(boundaries)-1],
+		}}},
+		bson.M{"$bucket": bson.M{
+			"groupBy":    field,
+			"boundaries": bounds,
+			"output":     bson.M{"count": bson.M{"$sum": 1}},
+		}},
+	}
+}
+
+func valueCounts(rows []facetRow) []models.FacetCount {
+	counts := make([]models.FacetCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, models.FacetCount{Value: fmt.Sprint(row.ID), Count: row.Count})
+	}
+	return counts
+}
+
+// bucketCounts labels each bucket by its range, "2010-2015" style, with the
+// top bucket shown as "2020+".
+func bucketCounts(rows []facetRow, boundaries []float64, format string) []models.FacetCount {
+	counts := make([]models.FacetCount, 0, len(rows))
+	for _, row := range rows {
+		lower, ok := toFloat(row.ID)
+		if !ok {
+			continue
+		}
+		for i := 0; i < len(boundaries)-1; i++ {
+			if boundaries[i] != lower {
+				continue
+			}
+			min := boundaries[i]
+			fc := models.FacetCount{Min: &min, Count: row.Count}
+			if i == len(boundaries)-2 {
+				fc.Value = fmt.Sprintf(format+"+", min)
+			} else {
+				max := boundaries[i+1]
+				fc.Max = &max
+				fc.Value = fmt.Sprintf(format+"-"+format, min, max)
+			}
+			counts = append(counts, fc)
+			break
+		}
+	}
+	return counts
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -1,59 +1,162 @@
 package listingRepo
 
 import (
+	"carsawa/database/keyset"
 	"carsawa/models"
 	"context"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func (r *MongoListingsRepository) GetActiveListings(ctx context.Context, filter models.ListingFilter, pagination models.Pagination) ([]models.Listing, error) {
-	query := bson.M{
-		"status": bson.M{"$in": []models.ListingStatus{
-			models.ListingStatusActive,
-			models.ListingStatusOpen,
-		}},
+// listingCardProjection trims the full photo gallery from feed and search
+// results; cards only need the denormalised coverThumbUrl.
+var listingCardProjection = bson.M{"media": 0}
+
+// GetActiveListings returns a page of live listings, newest first or nearest
+// first when filter.Near is set, with the cursors either side of it.
+func (r *MongoListingsRepository) GetActiveListings(ctx context.Context, filter models.ListingFilter, pagination models.Pagination) ([]models.Listing, models.PageCursors, error) {
+	query := buildListingQuery(filter)
+	query["status"] = bson.M{"$in": []models.ListingStatus{
+		models.ListingStatusActive,
+		models.ListingStatusOpen,
+	}}
+	if filter.Near != nil {
+		return r.geoNearListings(ctx, *filter.Near, query, pagination)
 	}
 
-	// Apply filters
-	if filter.Make != "" {
-		query["carDetails.make"] = filter.Make
+	if err := newestFirst.Check(pagination.Cursor); err != nil {
+		return nil, models.PageCursors{}, err
 	}
-	if filter.Model != "" {
-		query["carDetails.model"] = filter.Model
+	andKeyset(query, newestFirst, pagination.Cursor)
+	skip, limit := pageWindow(pagination)
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64(skip)).
+		SetSort(newestFirst.Sort(pagination.Cursor)).
+		SetProjection(listingCardProjection)
+
+	cursor, err := r.listings.Find(ctx, query, opts)
+	if err != nil {
+		return nil, models.PageCursors{}, err
 	}
-	if filter.MinYear > 0 {
-		query["carDetails.year"] = bson.M{"$gte": filter.MinYear}
+
+	var results []models.Listing
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, models.PageCursors{}, err
 	}
-	if filter.MaxYear > 0 {
-		if _, ok := query["carDetails.year"]; ok {
-			query["carDetails.year"] = bson.M{"$gte": filter.MinYear, "$lte": filter.MaxYear}
-		} else {
-			query["carDetails.year"] = bson.M{"$lte": filter.MaxYear}
-		}
+
+	results, cursors := keyset.Page(newestFirst, results, pagination.Limit, pagination.Offset, pagination.Cursor, createdKey)
+	return results, cursors, nil
+}
+
+// GetListingsByDealer returns a page of the dealer's own listings in every
+// status, or only status when it is set, newest first.
+func (r *MongoListingsRepository) GetListingsByDealer(ctx context.Context, dealerID primitive.ObjectID, status models.ListingStatus, pagination models.Pagination) ([]models.Listing, models.PageCursors, error) {
+	if err := newestFirst.Check(pagination.Cursor); err != nil {
+		return nil, models.PageCursors{}, err
 	}
+	query := bson.M{"type": models.ListingTypeDealer, "dealerListing.dealerId": dealerID}
+	if status != "" {
+		query["status"] = status
+	}
+	andKeyset(query, newestFirst, pagination.Cursor)
+	skip, limit := pageWindow(pagination)
 
 	opts := options.Find().
-		SetLimit(int64(pagination.Limit)).
-		SetSkip(int64(pagination.Offset)).
-		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+		SetLimit(int64(limit)).
+		SetSkip(int64(skip)).
+		SetSort(newestFirst.Sort(pagination.Cursor)).
+		SetProjection(listingCardProjection)
 
 	cursor, err := r.listings.Find(ctx, query, opts)
 	if err != nil {
-		return nil, err
+		return nil, models.PageCursors{}, fmt.Errorf("failed to list dealer listings: %w", err)
 	}
-
 	var results []models.Listing
 	if err := cursor.All(ctx, &results); err != nil {
-		return nil, err
+		return nil, models.PageCursors{}, fmt.Errorf("failed to decode dealer listings: %w", err)
 	}
 
-	return results, nil
+	results, cursors := keyset.Page(newestFirst, results, pagination.Limit, pagination.Offset, pagination.Cursor, createdKey)
+	return results, cursors, nil
+}
+
+// buildListingQuery translates a ListingFilter into a Mongo query on carDetails.
+func buildListingQuery(filter models.ListingFilter) bson.M {
+	query := bson.M{}
+
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Make != "" {
+		query["carDetails.make"] = filter.Make
+	}
+	if filter.Model != "" {
+		query["carDetails.model"] = filter.Model
+	}
+	if filter.FuelType != "" {
+		query["carDetails.fuelType"] = filter.FuelType
+	}
+	if filter.Transmission != "" {
+		query["carDetails.transmission"] = filter.Transmission
+	}
+	if filter.BodyType != "" {
+		query["carDetails.bodyType"] = filter.BodyType
+	}
+	if filter.DriveType != "" {
+		query["carDetails.driveType"] = filter.DriveType
+	}
+	if filter.ConditionGrade != "" {
+		query["carDetails.conditionGrade"] = filter.ConditionGrade
+	}
+	if filter.Colour != "" {
+		query["carDetails.colour"] = strings.ToLower(strings.TrimSpace(filter.Colour))
+	}
+	if filter.RegistrationPlate != "" {
+		query["carDetails.registrationPlate"] = strings.ToUpper(strings.ReplaceAll(filter.RegistrationPlate, " ", ""))
+	}
+	if filter.City != "" {
+		query["carDetails.location.city"] = bson.M{
+			"$regex":   "^" + regexp.QuoteMeta(strings.TrimSpace(filter.City)) + "$",
+			"$options": "i",
+		}
+	}
+	if filter.MaxOwners > 0 {
+		query["carDetails.numberOfOwners"] = bson.M{"$lte": filter.MaxOwners}
+	}
+
+	addRange(query, "carDetails.year", float64(filter.MinYear), float64(filter.MaxYear))
+	addRange(query, "carDetails.price", filter.MinPrice, filter.MaxPrice)
+	addRange(query, "carDetails.mileage", float64(filter.MinMileage), float64(filter.MaxMileage))
+	addRange(query, "carDetails.engineSize", float64(filter.MinEngineSize), float64(filter.MaxEngineSize))
+
+	return query
 }
 
-func (r *MongoListingsRepository) TextSearch(ctx context.Context, query string, pagination models.Pagination) ([]models.Listing, error) {
+// addRange adds a $gte/$lte condition on field for whichever bounds are set.
+func addRange(query bson.M, field string, min, max float64) {
+	cond := bson.M{}
+	if min > 0 {
+		cond["$gte"] = min
+	}
+	if max > 0 {
+		cond["$lte"] = max
+	}
+	if len(cond) > 0 {
+		query[field] = cond
+	}
+}
+
+// TextSearch ranks listings by relevance to query. $text cannot be combined
+// with $geoNear, so a geo search here only bounds results to the radius and
+// reports each distance.
+func (r *MongoListingsRepository) TextSearch(ctx context.Context, query string, near *models.GeoQuery, pagination models.Pagination) ([]models.Listing, error) {
 	filter := bson.M{
 		"$text": bson.M{"$search": query},
 		"status": bson.M{"$in": []models.ListingStatus{
@@ -61,11 +164,15 @@ func (r *MongoListingsRepository) TextSearch(ctx context.Context, query string,
 			models.ListingStatusOpen,
 		}},
 	}
+	if near != nil && near.RadiusKm > 0 {
+		filter[geoField] = geoWithin(*near)
+	}
 
 	opts := options.Find().
 		SetLimit(int64(pagination.Limit)).
 		SetSkip(int64(pagination.Offset)).
-		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}})
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}).
+		SetProjection(listingCardProjection)
 
 	cursor, err := r.listings.Find(ctx, filter, opts)
 	if err != nil {
@@ -76,62 +183,9 @@ func (r *MongoListingsRepository) TextSearch(ctx context.Context, query string,
 	if err := cursor.All(ctx, &results); err != nil {
 		return nil, err
 	}
-
-	return results, nil
-}
-
-func (r *MongoListingsRepository) GetSearchSuggestions(ctx context.Context, query string) ([]models.SearchSuggestion, error) {
-	pipeline := mongo.Pipeline{
-		{
-			{Key: "$match", Value: bson.M{
-				"$text": bson.M{"$search": query},
-			}},
-		},
-		{
-			{Key: "$project", Value: bson.M{
-				"make":  "$carDetails.make",
-				"model": "$carDetails.model",
-			}},
-		},
-		{
-			{Key: "$group", Value: bson.M{
-				"_id":    nil,
-				"makes":  bson.M{"$addToSet": "$make"},
-				"models": bson.M{"$addToSet": "$model"},
-			}},
-		},
-	}
-
-	cursor, err := r.listings.Aggregate(ctx, pipeline)
-	if err != nil {
-		return nil, err
+	if near != nil {
+		setDistances(results, *near)
 	}
 
-	var result struct {
-		Makes  []string `bson:"makes"`
-		Models []string `bson:"models"`
-	}
-	if cursor.Next(ctx) {
-		if err := cursor.Decode(&result); err != nil {
-			return nil, err
-		}
-	}
-
-	var suggestions []models.SearchSuggestion
-	for _, make := range result.Makes {
-		suggestions = append(suggestions, models.SearchSuggestion{
-			Text:  make,
-			Type:  "make",
-			Score: 1.0,
-		})
-	}
-	for _, model := range result.Models {
-		suggestions = append(suggestions, models.SearchSuggestion{
-			Text:  model,
-			Type:  "model",
-			Score: 0.8,
-		})
-	}
-
-	return suggestions, nil
+	return results, nil
 }
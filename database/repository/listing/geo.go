@@ -0,0 +1,102 @@
+package listingRepo
+
+import (
+	"carsawa/database/keyset"
+	"carsawa/models"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// geoField is the 2dsphere-indexed location of the car.
+const geoField = "carDetails.location.geoPoint"
+
+// geoNearListings runs query nearest-first around near, recording each
+// listing's distance in DistanceKm. A cursor becomes a distance bound on
+// $geoNear, with ties at the bound settled by _id.
+func (r *MongoListingsRepository) geoNearListings(ctx context.Context, near models.GeoQuery, query bson.M, pagination models.Pagination) ([]models.Listing, models.PageCursors, error) {
+	c := pagination.Cursor
+	if err := nearestFirst.Check(c); err != nil {
+		return nil, models.PageCursors{}, err
+	}
+
+	geoNear := bson.M{
+		"near":               near.Point(),
+		"key":                geoField,
+		"distanceField":      "distanceKm",
+		"distanceMultiplier": 0.001, // metres to kilometres
+		"spherical":          true,
+		"query":              query,
+	}
+	if near.RadiusKm > 0 {
+		geoNear["maxDistance"] = near.RadiusKm * 1000
+	}
+
+	pipeline := mongo.Pipeline{}
+	if c != nil {
+		km, err := cursorKm(c)
+		if err != nil {
+			return nil, models.PageCursors{}, err
+		}
+		if c.Before {
+			geoNear["maxDistance"] = km * 1000
+		} else {
+			geoNear["minDistance"] = km * 1000
+		}
+		pipeline = append(pipeline,
+			bson.D{{Key: "$geoNear", Value: geoNear}},
+			bson.D{{Key: "$match", Value: nearestFirst.Filter(c)}},
+		)
+	} else {
+		pipeline = append(pipeline, bson.D{{Key: "$geoNear", Value: geoNear}})
+	}
+	// listings sharing a seller's point tie on distance; _id keeps them stable
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: nearestFirst.Sort(c)}})
+
+	skip, limit := pageWindow(pagination)
+	pipeline = append(pipeline,
+		bson.D{{Key: "$skip", Value: skip}},
+		bson.D{{Key: "$limit", Value: limit}},
+		bson.D{{Key: "$project", Value: listingCardProjection}},
+	)
+
+	cursor, err := r.listings.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, models.PageCursors{}, fmt.Errorf("failed to search near location: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []models.Listing
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, models.PageCursors{}, err
+	}
+
+	results, cursors := keyset.Page(nearestFirst, results, pagination.Limit, pagination.Offset, c, distanceKey)
+	return results, cursors, nil
+}
+
+// geoWithin limits a query that cannot use $geoNear, such as a text search,
+// to RadiusKm around near.
+func geoWithin(near models.GeoQuery) bson.M {
+	return bson.M{"$geoWithin": bson.M{
+		"$centerSphere": bson.A{
+			bson.A{near.Lng, near.Lat},
+			near.RadiusKm / models.EarthRadiusKm,
+		},
+	}}
+}
+
+// setDistances fills in DistanceKm for results that were not sorted by
+// $geoNear. Listings without coordinates are left without a distance.
+func setDistances(listings []models.Listing, near models.GeoQuery) {
+	for i := range listings {
+		loc := listings[i].CarDetails.Location
+		if loc == nil || !loc.GeoPoint.Valid() {
+			continue
+		}
+		d := near.DistanceKm(loc.GeoPoint)
+		listings[i].DistanceKm = &d
+	}
+}
@@ -1,6 +1,7 @@
 package listingRepo
 
 import (
+	"carsawa/models"
 	"context"
 	"fmt"
 	"time"
@@ -22,6 +23,17 @@ func (r *MongoListingsRepository) ensureIndexes() error {
 		{
 			Keys: bson.D{{Key: "carDetails.make", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "carDetails.vin", Value: 1}},
+		},
+		{
+			// One live listing per VIN; see withVINClaim.
+			Keys: bson.D{{Key: "vinClaim", Value: 1}},
+			Options: options.Index().
+				SetName(vinClaimIndex).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"vinClaim": bson.M{"$gt": ""}}),
+		},
 		{
 			Keys: bson.D{{Key: "carDetails.mileage", Value: 1}},
 		},
@@ -31,6 +43,22 @@ func (r *MongoListingsRepository) ensureIndexes() error {
 		{
 			Keys: bson.D{{Key: "carDetails.year", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "carDetails.price", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "carDetails.bodyType", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "carDetails.fuelType", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "carDetails.location.city", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: geoField, Value: "2dsphere"}},
+			Options: options.Index().SetName("carDetails_geoPoint_2dsphere"),
+		},
 		{
 			Keys: bson.D{{Key: "status", Value: 1}},
 		},
@@ -41,6 +69,38 @@ func (r *MongoListingsRepository) ensureIndexes() error {
 			},
 			Options: options.Index().SetName("status_createdAt"),
 		},
+		{
+			// Dealer inventory pages; see GetListingsByDealer.
+			Keys: bson.D{
+				{Key: "dealerListing.dealerId", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("dealerId_createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "userListing.auction.endAt", Value: 1},
+			},
+			Options: options.Index().SetName("status_auctionEndAt").SetSparse(true),
+		},
+		{
+			// Expiry sweeps; see GetExpiringListings and GetExpiredListings.
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "expiresAt", Value: 1},
+			},
+			Options: options.Index().SetName("status_expiresAt").SetSparse(true),
+		},
+		{
+			// Stale draft cleanup; see GetStaleDrafts.
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "updatedAt", Value: 1},
+			},
+			Options: options.Index().SetName("status_updatedAt"),
+		},
 		{
 			Keys: bson.D{
 				{Key: "carDetails.make", Value: "text"},
@@ -56,3 +116,55 @@ func (r *MongoListingsRepository) ensureIndexes() error {
 	}
 	return nil
 }
+
+func (r *MongoListingsRepository) ensureSearchIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.searchEvents.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Mongo drops each event once its own expiresAt has passed.
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("createdAt"),
+		},
+		{
+			// One click per search and listing; see RecordListingView.
+			Keys: bson.D{
+				{Key: "searchId", Value: 1},
+				{Key: "listingId", Value: 1},
+			},
+			Options: options.Index().
+				SetName("click_searchId_listingId").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"kind": models.SearchEventClick}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create search event indexes: %w", err)
+	}
+
+	_, err = r.searchTrends.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "city", Value: 1},
+				{Key: "searches", Value: -1},
+			},
+			Options: options.Index().SetName("city_searches"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "city", Value: 1},
+				{Key: "zeroResults", Value: -1},
+			},
+			Options: options.Index().SetName("city_zeroResults"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create search trend indexes: %w", err)
+	}
+	return nil
+}
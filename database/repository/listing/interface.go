@@ -4,7 +4,10 @@ import (
 	"carsawa/models"
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -17,42 +20,123 @@ const (
 )
 
 var (
-	ErrNotFound           = errors.New("listing not found")
-	ErrInvalidID          = errors.New("invalid listing ID")
-	ErrInvalidType        = errors.New("invalid listing type")
-	ErrBidConflict        = errors.New("bid conflict occurred")
-	ErrInvalidTransition  = errors.New("invalid status transition")
-	ErrUnauthorizedAction = errors.New("unauthorized listing action")
+	ErrNotFound            = errors.New("listing not found")
+	ErrInvalidID           = errors.New("invalid listing ID")
+	ErrInvalidType         = errors.New("invalid listing type")
+	ErrBidConflict         = errors.New("bid conflict occurred")
+	ErrDuplicateBid        = errors.New("dealer already has an active bid on this listing")
+	ErrInvalidTransition   = errors.New("invalid status transition")
+	ErrTransitionForbidden = errors.New("status transition not allowed for this role")
+	ErrUnauthorizedAction  = errors.New("unauthorized listing action")
+	ErrMediaLimit          = errors.New("listing photo limit reached")
+	ErrMediaConflict       = errors.New("listing photos were changed concurrently")
 )
 
 type ListingRepository interface {
 	CreateListing(ctx context.Context, listing *models.Listing) (string, error)
+	// PublishListing moves a draft to active until expiresAt; see
+	// CreateListing for how a VIN that is already live elsewhere is handled.
+	PublishListing(ctx context.Context, id string, expiresAt *time.Time) (*models.Listing, error)
 	GetListingByID(ctx context.Context, id string) (*models.Listing, error)
-	UpdateListing(ctx context.Context, id string, updates map[string]interface{}) error
+	// UpdateListing sets fields on a listing that is still in status from,
+	// failing with ErrInvalidTransition if it has moved on. A status among
+	// the updates must have passed CheckTransition.
+	UpdateListing(ctx context.Context, id string, from models.ListingStatus, updates map[string]interface{}) error
+	// TransitionStatus moves a listing from one status to another, failing with
+	// ErrInvalidTransition if the listing is no longer in from. Callers check
+	// the move with CheckTransition first.
+	TransitionStatus(ctx context.Context, id string, from, to models.ListingStatus) error
 	DeleteListing(ctx context.Context, id string) error
 	AddBid(ctx context.Context, listingID string, bid models.Bid) error
 	AcceptBid(ctx context.Context, listingID, bidID string) error
+	ApplyBidRound(ctx context.Context, listingID, bidID string, from models.BidStatus, version int, to models.BidStatus, round models.BidRound) error
 	SearchListings(ctx context.Context, filter models.ListingFilter, pagination models.Pagination) ([]models.Listing, error)
 	IncrementViews(ctx context.Context, listingID string) error
 	DeleteListingsByDealerID(ctx context.Context, dealerID string) error
 
-	// Feed operations
-	GetActiveListings(ctx context.Context, filter models.ListingFilter, pagination models.Pagination) ([]models.Listing, error)
+	// Moderation
+	GetSuspiciousVINClusters(ctx context.Context, t VINSuspicion, pagination models.Pagination) ([]models.VINCluster, error)
+
+	// Media operations
+	AddMedia(ctx context.Context, listingID string, media []models.ListingMedia, coverThumbURL string) error
+	// SetMedia replaces the gallery of a listing whose media version is
+	// still version, failing with ErrMediaConflict if it has moved on.
+	SetMedia(ctx context.Context, listingID string, version int, media []models.ListingMedia, coverThumbURL string) error
+
+	// Feed operations; a filter with Near set is sorted nearest first
+	GetActiveListings(ctx context.Context, filter models.ListingFilter, pagination models.Pagination) ([]models.Listing, models.PageCursors, error)
+	GetListingsByDealer(ctx context.Context, dealerID primitive.ObjectID, status models.ListingStatus, pagination models.Pagination) ([]models.Listing, models.PageCursors, error)
 	GetFeaturedListings(ctx context.Context, limit int) ([]models.Listing, error)
 	GetPromotions(ctx context.Context) ([]models.Promotion, error)
 	GetBanners(ctx context.Context) ([]models.Banner, error)
-	TextSearch(ctx context.Context, query string, pagination models.Pagination) ([]models.Listing, error)
-	GetSearchSuggestions(ctx context.Context, query string) ([]models.SearchSuggestion, error)
-	RecordListingView(ctx context.Context, listingID string) error
-	RecordSearchQuery(ctx context.Context, query string, filters models.ListingFilter) error
+	TextSearch(ctx context.Context, query string, near *models.GeoQuery, pagination models.Pagination) ([]models.Listing, error)
+	// FacetedSearch returns a result page and facet counts in one round trip.
+	FacetedSearch(ctx context.Context, query string, filter models.ListingFilter, pagination models.Pagination) (*models.SearchResult, error)
+
+	// Watchlist counters; the entries themselves live in the watchlist repo.
+	IncrementWatchers(ctx context.Context, listingID string, delta int) error
+	GetListingsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Listing, error)
+	GetDealerInterest(ctx context.Context, dealerID primitive.ObjectID, limit int) ([]models.ListingInterest, error)
+
+	// Valuation
+	GetPricePoints(ctx context.Context, since time.Time) ([]models.PricePoint, error)
+
+	// Search analytics
+	RecordSearchQuery(ctx context.Context, event *models.SearchEvent) error
+	// RecordListingView counts a click from a search's results, once per
+	// search and listing. Clicks on searches that have expired are dropped.
+	RecordListingView(ctx context.Context, listingID, searchID string) error
+	// ComputeSearchTrends rebuilds the per-query trends from the events since.
+	ComputeSearchTrends(ctx context.Context, since time.Time) error
+	GetSearchTrends(ctx context.Context, city string, limit int) (*models.SearchTrends, error)
+
+	// Auction operations
+	ActivateDueAuctions(ctx context.Context, now time.Time) (int64, error)
+	GetExpiredAuctions(ctx context.Context, now time.Time, limit int) ([]models.Listing, error)
+	CloseAuction(ctx context.Context, listingID string, winningBid *primitive.ObjectID, closedAt time.Time) error
+
+	// Lifecycle operations
+	// StampExpiry gives live listings of type lt that have no expiry one at
+	// expiresAt. Listings running a timed auction are left alone.
+	StampExpiry(ctx context.Context, lt models.ListingType, expiresAt time.Time) (int64, error)
+	// GetExpiringListings returns live listings of type lt expiring by before
+	// whose owner has not been reminded.
+	GetExpiringListings(ctx context.Context, lt models.ListingType, before time.Time, limit int) ([]models.Listing, error)
+	// MarkReminded records the expiry reminder, reporting false if another
+	// run got there first.
+	MarkReminded(ctx context.Context, id string, at time.Time) (bool, error)
+	GetExpiredListings(ctx context.Context, lt models.ListingType, now time.Time, limit int) ([]models.Listing, error)
+	// RenewListing extends an active dealer listing, or puts an expired one
+	// back on the market, until expiresAt. It fails with ErrInvalidTransition
+	// if the listing is no longer in from.
+	RenewListing(ctx context.Context, id string, from models.ListingStatus, expiresAt *time.Time) (*models.Listing, error)
+	// GetStaleDrafts returns drafts last updated before the cutoff, oldest
+	// first; DeleteStaleDraft deletes one, reporting false if it has been
+	// edited or published meanwhile.
+	GetStaleDrafts(ctx context.Context, before time.Time, limit int) ([]models.Listing, error)
+	DeleteStaleDraft(ctx context.Context, id string, before time.Time) (bool, error)
 }
 
 type MongoListingsRepository struct {
-	listings *mongo.Collection
+	listings     *mongo.Collection
+	searchEvents *mongo.Collection
+	searchTrends *mongo.Collection
 }
 
 func NewMongoListingsRepository(db *mongo.Database) *MongoListingsRepository {
-	return &MongoListingsRepository{
-		listings: db.Collection("listings"),
+	r := &MongoListingsRepository{
+		listings:     db.Collection("listings"),
+		searchEvents: db.Collection("search_events"),
+		searchTrends: db.Collection("search_trends"),
+	}
+	if err := r.backfillBidStatus(); err != nil {
+		fmt.Printf("failed to migrate listing bids: %v\n", err)
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create listing indexes: %v\n", err)
+	}
+	if err := r.ensureSearchIndexes(); err != nil {
+		fmt.Printf("failed to create search analytics indexes: %v\n", err)
 	}
+	return r
 }
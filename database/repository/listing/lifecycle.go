@@ -0,0 +1,161 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// liveStatus is the status in which a listing of type lt is on the market
+// and counting down to expiry.
+func liveStatus(lt models.ListingType) models.ListingStatus {
+	if lt == models.ListingTypeUserBid {
+		return models.ListingStatusOpen
+	}
+	return models.ListingStatusActive
+}
+
+// expiryFilter matches the live listings of type lt that expire; timed
+// auctions end on their own schedule instead.
+func expiryFilter(lt models.ListingType) bson.M {
+	filter := bson.M{"type": lt, "status": liveStatus(lt)}
+	if lt == models.ListingTypeUserBid {
+		filter["userListing.auction"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+func (r *MongoListingsRepository) StampExpiry(ctx context.Context, lt models.ListingType, expiresAt time.Time) (int64, error) {
+	filter := expiryFilter(lt)
+	filter["expiresAt"] = bson.M{"$exists": false}
+	res, err := r.listings.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"expiresAt": expiresAt}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to stamp listing expiry: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *MongoListingsRepository) GetExpiringListings(ctx context.Context, lt models.ListingType, before time.Time, limit int) ([]models.Listing, error) {
+	filter := expiryFilter(lt)
+	filter["expiresAt"] = bson.M{"$lte": before}
+	filter["expiryRemindedAt"] = bson.M{"$exists": false}
+	return r.findByExpiry(ctx, filter, limit, "failed to query expiring listings")
+}
+
+func (r *MongoListingsRepository) MarkReminded(ctx context.Context, id string, at time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, ErrInvalidID
+	}
+	res, err := r.listings.UpdateOne(ctx,
+		bson.M{"_id": objID, "expiryRemindedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"expiryRemindedAt": at}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark expiry reminder: %w", err)
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *MongoListingsRepository) GetExpiredListings(ctx context.Context, lt models.ListingType, now time.Time, limit int) ([]models.Listing, error) {
+	filter := expiryFilter(lt)
+	filter["expiresAt"] = bson.M{"$lte": now}
+	return r.findByExpiry(ctx, filter, limit, "failed to query expired listings")
+}
+
+func (r *MongoListingsRepository) findByExpiry(ctx context.Context, filter bson.M, limit int, failure string) ([]models.Listing, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "expiresAt", Value: 1}})
+
+	cursor, err := r.listings.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", failure, err)
+	}
+	var results []models.Listing
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *MongoListingsRepository) RenewListing(
+	ctx context.Context,
+	id string,
+	from models.ListingStatus,
+	expiresAt *time.Time,
+) (*models.Listing, error) {
+	if from != models.ListingStatusActive {
+		// off the market, so the VIN has to be claimed again
+		lst, err := r.goLive(ctx, id, from, expiresAt)
+		if err != nil && !errors.Is(err, ErrInvalidTransition) {
+			return nil, fmt.Errorf("failed to renew listing: %w", err)
+		}
+		return lst, err
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+	set := bson.M{"updatedAt": time.Now()}
+	unset := bson.M{"expiryRemindedAt": ""}
+	if expiresAt != nil {
+		set["expiresAt"] = *expiresAt
+	} else {
+		unset["expiresAt"] = ""
+	}
+	res, err := r.listings.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": from},
+		bson.M{"$set": set, "$unset": unset},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew listing: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return nil, ErrInvalidTransition
+	}
+	return r.GetListingByID(ctx, id)
+}
+
+func (r *MongoListingsRepository) GetStaleDrafts(ctx context.Context, before time.Time, limit int) ([]models.Listing, error) {
+	filter := bson.M{
+		"status":    models.ListingStatusDraft,
+		"updatedAt": bson.M{"$lt": before},
+	}
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "updatedAt", Value: 1}})
+
+	cursor, err := r.listings.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale drafts: %w", err)
+	}
+	var results []models.Listing
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *MongoListingsRepository) DeleteStaleDraft(ctx context.Context, id string, before time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, ErrInvalidID
+	}
+	res, err := r.listings.DeleteOne(ctx, bson.M{
+		"_id":       objID,
+		"status":    models.ListingStatusDraft,
+		"updatedAt": bson.M{"$lt": before},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete draft: %w", err)
+	}
+	return res.DeletedCount == 1, nil
+}
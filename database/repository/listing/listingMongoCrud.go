@@ -14,7 +14,9 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-// CreateListing inserts a new listing into the database.
+// CreateListing inserts a new listing into the database. A listing whose VIN
+// is already live elsewhere is still created, with a moderation flag set on
+// the listing passed in.
 func (r *MongoListingsRepository) CreateListing(ctx context.Context, listing *models.Listing) (string, error) {
 	if listing.ID.IsZero() {
 		listing.ID = primitive.NewObjectID()
@@ -40,7 +42,22 @@ func (r *MongoListingsRepository) CreateListing(ctx context.Context, listing *mo
 		return "", ErrInvalidType
 	}
 
-	_, err := r.listings.InsertOne(ctx, listing)
+	vin := listing.CarDetails.VIN
+	err := r.withVINClaim(ctx, vin, listing.ID, func(related []primitive.ObjectID) error {
+		listing.VINClaim = ""
+		if len(related) > 0 {
+			listing.Moderation = &models.ListingModeration{
+				Status: models.ModerationStatusFlagged,
+				Flags:  []models.ModerationFlag{duplicateVINFlag(related)},
+			}
+		} else if isLive(listing.Status) && vin != "" {
+			// Drafts claim their VIN when they are published.
+			listing.Moderation = nil
+			listing.VINClaim = vin
+		}
+		_, err := r.listings.InsertOne(ctx, listing)
+		return err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create listing: %w", err)
 	}
@@ -65,8 +82,8 @@ func (r *MongoListingsRepository) GetListingByID(ctx context.Context, id string)
 	return &listing, nil
 }
 
-// UpdateListing updates specific fields of a listing.
-func (r *MongoListingsRepository) UpdateListing(ctx context.Context, id string, updates map[string]interface{}) error {
+// UpdateListing updates specific fields of a listing, guarded by its status.
+func (r *MongoListingsRepository) UpdateListing(ctx context.Context, id string, from models.ListingStatus, updates map[string]interface{}) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return ErrInvalidID
@@ -80,14 +97,44 @@ func (r *MongoListingsRepository) UpdateListing(ctx context.Context, id string,
 
 	res, err := r.listings.UpdateOne(
 		ctx,
-		bson.M{"_id": objID},
+		bson.M{"_id": objID, "status": from},
 		bson.M{"$set": updates},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update listing: %w", err)
 	}
 	if res.MatchedCount == 0 {
-		return ErrNotFound
+		return r.missOrMoved(ctx, objID)
+	}
+	return nil
+}
+
+// missOrMoved explains a guarded update that matched nothing: the listing
+// is gone, or its status changed underneath the caller.
+func (r *MongoListingsRepository) missOrMoved(ctx context.Context, objID primitive.ObjectID) error {
+	n, err := r.listings.CountDocuments(ctx, bson.M{"_id": objID})
+	if err == nil && n > 0 {
+		return ErrInvalidTransition
+	}
+	return ErrNotFound
+}
+
+func (r *MongoListingsRepository) TransitionStatus(ctx context.Context, id string, from, to models.ListingStatus) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	res, err := r.listings.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": from},
+		bson.M{"$set": bson.M{"status": to, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update listing status: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return r.missOrMoved(ctx, objID)
 	}
 	return nil
 }
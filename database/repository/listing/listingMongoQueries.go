@@ -3,14 +3,19 @@ package listingRepo
 import (
 	"carsawa/models"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// activeBidStatuses are the bid states that are still under negotiation.
+var activeBidStatuses = []models.BidStatus{models.BidStatusPending, models.BidStatusCountered}
+
 // AddBid adds a new bid to a user bid listing.
 func (r *MongoListingsRepository) AddBid(ctx context.Context, listingID string, bid models.Bid) error {
 	objID, err := primitive.ObjectIDFromHex(listingID)
@@ -18,21 +23,46 @@ func (r *MongoListingsRepository) AddBid(ctx context.Context, listingID string,
 		return ErrInvalidID
 	}
 
+	now := time.Now()
 	bid.ID = primitive.NewObjectID()
-	bid.CreatedAt = time.Now()
-	bid.UpdatedAt = time.Now()
+	bid.CounterOffer = 0
+	bid.Status = models.BidStatusPending
+	bid.Version = 1
+	bid.History = []models.BidRound{{
+		Version:   1,
+		Actor:     models.BidActorDealer,
+		Action:    models.BidActionOffer,
+		Amount:    bid.Offer,
+		Message:   bid.Message,
+		CreatedAt: now,
+	}}
+	bid.CreatedAt = now
+	bid.UpdatedAt = now
 
 	update := bson.M{
-		"$push": bson.M{"bids": bid},
-		"$set":  bson.M{"updatedAt": time.Now()},
+		"$push": bson.M{"userListing.bids": bid},
+		"$set":  bson.M{"updatedAt": now},
 	}
 
+	// A dealer may hold only one live bid per listing, and auction listings
+	// only accept bids inside their bidding window that clear the high bid
+	// by the minimum increment. All are checked in the update filter so
+	// concurrent requests cannot slip a second or undercutting bid in.
+	activeBid := bson.M{
+		"dealerId": bid.DealerID,
+		"status":   bson.M{"$in": activeBidStatuses},
+	}
 	res, err := r.listings.UpdateOne(
 		ctx,
 		bson.M{
-			"_id":    objID,
-			"type":   models.ListingTypeUserBid,
-			"status": models.ListingStatusOpen,
+			"_id":              objID,
+			"type":             models.ListingTypeUserBid,
+			"status":           models.ListingStatusOpen,
+			"userListing.bids": bson.M{"$not": bson.M{"$elemMatch": activeBid}},
+			"$and": []bson.M{
+				biddingOpenFilter(now),
+				outbidsFilter(bid.Offer),
+			},
 		},
 		update,
 	)
@@ -41,12 +71,19 @@ func (r *MongoListingsRepository) AddBid(ctx context.Context, listingID string,
 		return fmt.Errorf("failed to add bid: %w", err)
 	}
 	if res.MatchedCount == 0 {
+		n, err := r.listings.CountDocuments(ctx, bson.M{
+			"_id":              objID,
+			"userListing.bids": bson.M{"$elemMatch": activeBid},
+		})
+		if err == nil && n > 0 {
+			return ErrDuplicateBid
+		}
 		return ErrNotFound
 	}
 	return nil
 }
 
-// AcceptBid sets a specific bid as accepted and updates the listing status.
+// AcceptBid accepts a pending bid, rejects the other live bids and updates the listing status.
 func (r *MongoListingsRepository) AcceptBid(ctx context.Context, listingID, bidID string) error {
 	listingObjID, err := primitive.ObjectIDFromHex(listingID)
 	if err != nil {
@@ -67,24 +104,64 @@ func (r *MongoListingsRepository) AcceptBid(ctx context.Context, listingID, bidI
 	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
 		var listing models.Listing
 		err := r.listings.FindOne(sessCtx, bson.M{
-			"_id":      listingObjID,
-			"bids._id": bidObjID,
-			"status":   models.ListingStatusOpen,
+			"_id":    listingObjID,
+			"status": models.ListingStatusOpen,
+			"userListing.bids": bson.M{"$elemMatch": bson.M{
+				"_id":    bidObjID,
+				"status": models.BidStatusPending,
+			}},
 		}).Decode(&listing)
 
 		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, fmt.Errorf("failed to load listing: %w", err)
+			}
+			// Distinguish a missing bid from one that is not awaiting the owner.
+			n, cerr := r.listings.CountDocuments(sessCtx, bson.M{"_id": listingObjID, "userListing.bids._id": bidObjID})
+			if cerr == nil && n > 0 {
+				return nil, ErrBidConflict
+			}
 			return nil, ErrNotFound
 		}
 
+		var offer float64
+		var version int
+		for _, b := range listing.UserListing.Bids {
+			if b.ID == bidObjID {
+				offer, version = b.Offer, b.Version
+				break
+			}
+		}
+
+		now := time.Now()
 		update := bson.M{
 			"$set": bson.M{
-				"status":      models.ListingStatusAccepted,
-				"acceptedBid": bidObjID,
-				"updatedAt":   time.Now(),
+				"status":                              models.ListingStatusAccepted,
+				"userListing.acceptedBid":             bidObjID,
+				"userListing.bids.$[win].status":      models.BidStatusAccepted,
+				"userListing.bids.$[win].updatedAt":   now,
+				"userListing.bids.$[other].status":    models.BidStatusRejected,
+				"userListing.bids.$[other].updatedAt": now,
+				"updatedAt":                           now,
 			},
+			"$inc": bson.M{"userListing.bids.$[win].version": 1},
+			"$push": bson.M{"userListing.bids.$[win].history": models.BidRound{
+				Version:   version + 1,
+				Actor:     models.BidActorOwner,
+				Action:    models.BidActionAccept,
+				Amount:    offer,
+				CreatedAt: now,
+			}},
 		}
+		opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"win._id": bidObjID},
+			bson.M{
+				"other._id":    bson.M{"$ne": bidObjID},
+				"other.status": bson.M{"$in": activeBidStatuses},
+			},
+		}})
 
-		res, err := r.listings.UpdateByID(sessCtx, listingObjID, update)
+		res, err := r.listings.UpdateByID(sessCtx, listingObjID, update, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to accept bid: %w", err)
 		}
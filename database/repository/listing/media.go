@@ -0,0 +1,99 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxListingMedia caps the number of photos stored on a single listing.
+const MaxListingMedia = 20
+
+// AddMedia appends photos to a listing's gallery. The size guard lives in the
+// update filter so concurrent uploads cannot push the gallery past the cap.
+func (r *MongoListingsRepository) AddMedia(ctx context.Context, listingID string, media []models.ListingMedia, coverThumbURL string) error {
+	objID, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return ErrInvalidID
+	}
+	if len(media) == 0 {
+		return nil
+	}
+	if len(media) > MaxListingMedia {
+		return ErrMediaLimit
+	}
+
+	set := bson.M{"updatedAt": time.Now()}
+	if coverThumbURL != "" {
+		set["coverThumbUrl"] = coverThumbURL
+	}
+
+	res, err := r.listings.UpdateOne(
+		ctx,
+		bson.M{
+			"_id": objID,
+			fmt.Sprintf("media.%d", MaxListingMedia-len(media)): bson.M{"$exists": false},
+		},
+		bson.M{
+			"$push": bson.M{"media": bson.M{"$each": media}},
+			"$set":  set,
+			"$inc":  bson.M{"mediaVersion": 1},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add media: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		if _, err := r.GetListingByID(ctx, listingID); err != nil {
+			return err
+		}
+		return ErrMediaLimit
+	}
+	return nil
+}
+
+// SetMedia replaces a listing's gallery after a reorder, caption, cover or
+// delete change. The write is a compare-and-set on the listing's media
+// version, so a gallery read before a concurrent upload or edit cannot
+// overwrite it.
+func (r *MongoListingsRepository) SetMedia(ctx context.Context, listingID string, version int, media []models.ListingMedia, coverThumbURL string) error {
+	objID, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return ErrInvalidID
+	}
+	if media == nil {
+		media = []models.ListingMedia{}
+	}
+
+	// Listings whose gallery was never written have no version stored.
+	var current interface{} = version
+	if version == 0 {
+		current = bson.M{"$exists": false}
+	}
+	res, err := r.listings.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "mediaVersion": current},
+		bson.M{
+			"$set": bson.M{
+				"media":         media,
+				"coverThumbUrl": coverThumbURL,
+				"updatedAt":     time.Now(),
+			},
+			"$inc": bson.M{"mediaVersion": 1},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update media: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		if _, err := r.GetListingByID(ctx, listingID); err != nil {
+			return err
+		}
+		return ErrMediaConflict
+	}
+	return nil
+}
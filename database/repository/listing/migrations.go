@@ -0,0 +1,47 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// backfillBidStatus gives bids stored before negotiation existed a status
+// and a version, which every negotiation write matches on. Bids on open
+// listings become pending; on settled ones the accepted bid is marked
+// accepted and the rest rejected.
+func (r *MongoListingsRepository) backfillBidStatus() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	status := bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{"case": bson.M{"$eq": bson.A{"$status", models.ListingStatusOpen}}, "then": models.BidStatusPending},
+			bson.M{"case": bson.M{"$eq": bson.A{"$$b._id", "$userListing.acceptedBid"}}, "then": models.BidStatusAccepted},
+		},
+		"default": models.BidStatusRejected,
+	}}
+	_, err := r.listings.UpdateMany(
+		ctx,
+		bson.M{
+			"type":             models.ListingTypeUserBid,
+			"userListing.bids": bson.M{"$elemMatch": bson.M{"status": bson.M{"$exists": false}}},
+		},
+		bson.A{bson.M{"$set": bson.M{"userListing.bids": bson.M{"$map": bson.M{
+			"input": "$userListing.bids",
+			"as":    "b",
+			"in": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{bson.M{"$type": "$$b.status"}, "missing"}},
+				bson.M{"$mergeObjects": bson.A{"$$b", bson.M{"status": status, "version": 1}}},
+				"$$b",
+			}},
+		}}}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to backfill bid status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApplyBidRound appends a negotiation round to a bid and moves it to the next status.
+// The write is a compare-and-set on the bid's current status and version, so a
+// stale round (for example, a counter racing a withdrawal) fails with ErrBidConflict.
+func (r *MongoListingsRepository) ApplyBidRound(
+	ctx context.Context,
+	listingID, bidID string,
+	from models.BidStatus,
+	version int,
+	to models.BidStatus,
+	round models.BidRound,
+) error {
+	listingObjID, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return ErrInvalidID
+	}
+	bidObjID, err := primitive.ObjectIDFromHex(bidID)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	round.Version = version + 1
+	round.CreatedAt = now
+
+	set := bson.M{
+		"userListing.bids.$.status":    to,
+		"userListing.bids.$.version":   round.Version,
+		"userListing.bids.$.updatedAt": now,
+		"updatedAt":                    now,
+	}
+	switch {
+	case round.Action == models.BidActionCounter && round.Actor == models.BidActorOwner:
+		set["userListing.bids.$.counterOffer"] = round.Amount
+	case round.Action == models.BidActionCounter, round.Action == models.BidActionUpdate:
+		// Dealer rounds replace the live offer; the old one stays in the history.
+		set["userListing.bids.$.offer"] = round.Amount
+		set["userListing.bids.$.message"] = round.Message
+	}
+
+	filter := bson.M{
+		"_id":    listingObjID,
+		"type":   models.ListingTypeUserBid,
+		"status": models.ListingStatusOpen,
+		"userListing.bids": bson.M{"$elemMatch": bson.M{
+			"_id":     bidObjID,
+			"status":  from,
+			"version": version,
+		}},
+	}
+	if round.Actor == models.BidActorDealer && round.Action == models.BidActionUpdate {
+		// A raised auction bid must land inside the bidding window and still
+		// clear the high bid, as a new bid must.
+		filter = bson.M{"$and": []bson.M{filter, biddingOpenFilter(now), outbidsFilter(round.Amount)}}
+	}
+
+	res, err := r.listings.UpdateOne(
+		ctx,
+		filter,
+		bson.M{
+			"$set":  set,
+			"$push": bson.M{"userListing.bids.$.history": round},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to apply bid round: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrBidConflict
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package listingRepo
+
+import (
+	"carsawa/database/keyset"
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Orderings listing pages can be cursored through.
+var (
+	newestFirst  = keyset.Order{Name: "createdAt", Field: "createdAt", Dir: keyset.Desc}
+	nearestFirst = keyset.Order{Name: "distance", Field: "distanceKm", Dir: keyset.Asc}
+	bestMatch    = keyset.Order{Name: "score", Field: "score", Dir: keyset.Desc}
+)
+
+func createdKey(l models.Listing) (interface{}, primitive.ObjectID) {
+	return l.CreatedAt, l.ID
+}
+
+func distanceKey(l models.Listing) (interface{}, primitive.ObjectID) {
+	if l.DistanceKm == nil {
+		return 0.0, l.ID
+	}
+	return *l.DistanceKm, l.ID
+}
+
+func scoreKey(l models.Listing) (interface{}, primitive.ObjectID) {
+	return l.SearchScore, l.ID
+}
+
+// pageWindow is the skip and limit for fetching a page plus one item, which
+// tells whether a further page exists. Cursored pages never skip.
+func pageWindow(p models.Pagination) (skip, limit int) {
+	if p.Cursor != nil {
+		return 0, p.Limit + 1
+	}
+	return p.Offset, p.Limit + 1
+}
+
+// andKeyset narrows query to the documents past the cursor.
+func andKeyset(query bson.M, o keyset.Order, c *models.Cursor) {
+	if f := o.Filter(c); f != nil {
+		query["$and"] = append(asArray(query["$and"]), f)
+	}
+}
+
+func asArray(v interface{}) bson.A {
+	if a, ok := v.(bson.A); ok {
+		return a
+	}
+	return bson.A{}
+}
+
+// cursorKm reads a distance cursor's key.
+func cursorKm(c *models.Cursor) (float64, error) {
+	switch k := c.Key.(type) {
+	case float64:
+		return k, nil
+	case int32:
+		return float64(k), nil
+	case int64:
+		return float64(k), nil
+	}
+	return 0, keyset.ErrCursorMismatch
+}
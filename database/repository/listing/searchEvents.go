@@ -0,0 +1,169 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoListingsRepository) RecordSearchQuery(ctx context.Context, event *models.SearchEvent) error {
+	if _, err := r.searchEvents.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to record search: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoListingsRepository) RecordListingView(ctx context.Context, listingID, searchID string) error {
+	lid, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return ErrInvalidID
+	}
+	sid, err := primitive.ObjectIDFromHex(searchID)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	var search models.SearchEvent
+	err = r.searchEvents.FindOne(ctx, bson.M{"_id": sid, "kind": models.SearchEventSearch}).Decode(&search)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find search: %w", err)
+	}
+
+	// Clicks carry their search's query and city so trends can group them
+	// without a lookup, and expire with it.
+	_, err = r.searchEvents.InsertOne(ctx, models.SearchEvent{
+		ID:        primitive.NewObjectID(),
+		Kind:      models.SearchEventClick,
+		SearchID:  sid,
+		Query:     search.Query,
+		Make:      search.Make,
+		Model:     search.Model,
+		City:      search.City,
+		Results:   search.Results,
+		ListingID: &lid,
+		CreatedAt: time.Now(),
+		ExpiresAt: search.ExpiresAt,
+	})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to record search click: %w", err)
+	}
+	return nil
+}
+
+func isSearch() bson.M {
+	return bson.M{"$eq": bson.A{"$kind", models.SearchEventSearch}}
+}
+
+// trendPipeline folds each search and its clicks together, then counts
+// them per query, per city when byCity is set and across all cities when not.
+func (r *MongoListingsRepository) trendPipeline(since, now time.Time, byCity bool) mongo.Pipeline {
+	city := interface{}("")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"createdAt": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$searchId",
+			"query":    bson.M{"$first": "$query"},
+			"city":     bson.M{"$first": "$city"},
+			"searched": bson.M{"$max": bson.M{"$cond": bson.A{isSearch(), 1, 0}}},
+			"results":  bson.M{"$max": "$results"},
+			"clicks":   bson.M{"$sum": bson.M{"$cond": bson.A{isSearch(), 0, 1}}},
+			"at":       bson.M{"$max": "$createdAt"},
+		}}},
+		{{Key: "$match", Value: bson.M{"searched": 1, "query": bson.M{"$ne": ""}}}},
+	}
+	if byCity {
+		city = "$city"
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"city": bson.M{"$nin": bson.A{"", nil}}}}})
+	}
+	return append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"city": city, "query": "$query"},
+			"searches":    bson.M{"$sum": 1},
+			"zeroResults": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$results", 0}}, 1, 0}}},
+			"clicks":      bson.M{"$sum": "$clicks"},
+			"clicked":     bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$clicks", 0}}, 1, 0}}},
+			"lastSeen":    bson.M{"$max": "$at"},
+		}}},
+		bson.D{{Key: "$project", Value: bson.M{
+			"city":         "$_id.city",
+			"query":        "$_id.query",
+			"searches":     1,
+			"zeroResults":  1,
+			"clicks":       1,
+			"clickThrough": bson.M{"$round": bson.A{bson.M{"$divide": bson.A{"$clicked", "$searches"}}, 3}},
+			"lastSeen":     1,
+			"windowStart":  bson.M{"$literal": since},
+			"computedAt":   bson.M{"$literal": now},
+		}}},
+		bson.D{{Key: "$merge", Value: bson.M{
+			"into":           r.searchTrends.Name(),
+			"on":             "_id",
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}}},
+	)
+}
+
+func (r *MongoListingsRepository) ComputeSearchTrends(ctx context.Context, since time.Time) error {
+	now := time.Now()
+	for _, byCity := range []bool{false, true} {
+		cursor, err := r.searchEvents.Aggregate(ctx, r.trendPipeline(since, now, byCity))
+		if err != nil {
+			return fmt.Errorf("failed to compute search trends: %w", err)
+		}
+		cursor.Close(ctx)
+	}
+
+	// Queries nobody searched for in this window.
+	if _, err := r.searchTrends.DeleteMany(ctx, bson.M{"computedAt": bson.M{"$lt": now}}); err != nil {
+		return fmt.Errorf("failed to drop stale search trends: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoListingsRepository) findTrends(ctx context.Context, filter bson.M, sortBy string, limit int) ([]models.SearchTrend, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortBy, Value: -1}, {Key: "lastSeen", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.searchTrends.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search trends: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	trends := []models.SearchTrend{}
+	if err := cursor.All(ctx, &trends); err != nil {
+		return nil, fmt.Errorf("failed to decode search trends: %w", err)
+	}
+	return trends, nil
+}
+
+// GetSearchTrends returns a city's most searched queries and those that
+// most often found nothing. An empty city covers all cities.
+func (r *MongoListingsRepository) GetSearchTrends(ctx context.Context, city string, limit int) (*models.SearchTrends, error) {
+	top, err := r.findTrends(ctx, bson.M{"city": city}, "searches", limit)
+	if err != nil {
+		return nil, err
+	}
+	unmet, err := r.findTrends(ctx, bson.M{"city": city, "zeroResults": bson.M{"$gt": 0}}, "zeroResults", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	trends := &models.SearchTrends{City: city, Top: top, Unmet: unmet}
+	if len(top) > 0 {
+		trends.WindowStart = top[0].WindowStart
+		trends.ComputedAt = top[0].ComputedAt
+	}
+	return trends, nil
+}
@@ -0,0 +1,50 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"fmt"
+)
+
+type statusEdge struct {
+	listingType models.ListingType
+	from, to    models.ListingStatus
+}
+
+// statusTransitions is the listing state machine: every status change a
+// listing of each type can make, and the roles allowed to make it. Moves
+// into reserved and back are made by deposits and sales, not by hand: a
+// reserved car only returns to the market when its sale is cancelled or
+// refunded, which also releases the escrow. Only the expiry job lets a
+// listing lapse.
+var statusTransitions = map[statusEdge][]models.ActorType{
+	{models.ListingTypeDealer, models.ListingStatusDraft, models.ListingStatusActive}:    {models.ActorDealer, models.ActorAdmin},
+	{models.ListingTypeDealer, models.ListingStatusDraft, models.ListingStatusClosed}:    {models.ActorDealer, models.ActorAdmin},
+	{models.ListingTypeDealer, models.ListingStatusActive, models.ListingStatusReserved}: {models.ActorSystem},
+	{models.ListingTypeDealer, models.ListingStatusActive, models.ListingStatusSold}:     {models.ActorDealer, models.ActorAdmin},
+	{models.ListingTypeDealer, models.ListingStatusActive, models.ListingStatusClosed}:   {models.ActorDealer, models.ActorAdmin},
+	{models.ListingTypeDealer, models.ListingStatusReserved, models.ListingStatusActive}: {models.ActorSystem, models.ActorAdmin},
+	{models.ListingTypeDealer, models.ListingStatusReserved, models.ListingStatusSold}:   {models.ActorDealer, models.ActorSystem, models.ActorAdmin},
+	{models.ListingTypeDealer, models.ListingStatusActive, models.ListingStatusExpired}:  {models.ActorSystem},
+	{models.ListingTypeDealer, models.ListingStatusExpired, models.ListingStatusActive}:  {models.ActorDealer, models.ActorAdmin},
+	{models.ListingTypeDealer, models.ListingStatusExpired, models.ListingStatusClosed}:  {models.ActorDealer, models.ActorAdmin},
+
+	{models.ListingTypeUserBid, models.ListingStatusOpen, models.ListingStatusAccepted}:   {models.ActorUser, models.ActorSystem},
+	{models.ListingTypeUserBid, models.ListingStatusOpen, models.ListingStatusClosed}:     {models.ActorUser, models.ActorSystem, models.ActorAdmin},
+	{models.ListingTypeUserBid, models.ListingStatusAccepted, models.ListingStatusClosed}: {models.ActorUser, models.ActorAdmin},
+}
+
+// CheckTransition reports whether role may move a listing of type lt from
+// one status to another, failing with ErrInvalidTransition when the move
+// does not exist and ErrTransitionForbidden when role may not make it.
+func CheckTransition(lt models.ListingType, from, to models.ListingStatus, role models.ActorType) error {
+	roles, ok := statusTransitions[statusEdge{lt, from, to}]
+	if !ok {
+		return fmt.Errorf("%w: a %s listing cannot go from %s to %s", ErrInvalidTransition, lt, from, to)
+	}
+	for _, r := range roles {
+		if r == role {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: a %s cannot move a listing from %s to %s", ErrTransitionForbidden, role, from, to)
+}
@@ -0,0 +1,46 @@
+package listingRepo
+
+import (
+	"errors"
+	"testing"
+
+	"carsawa/models"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		lt       models.ListingType
+		from, to models.ListingStatus
+		role     models.ActorType
+		err      error
+	}{
+		{name: "dealer publishes draft", lt: models.ListingTypeDealer, from: models.ListingStatusDraft, to: models.ListingStatusActive, role: models.ActorDealer},
+		{name: "user cannot publish dealer draft", lt: models.ListingTypeDealer, from: models.ListingStatusDraft, to: models.ListingStatusActive, role: models.ActorUser, err: ErrTransitionForbidden},
+		{name: "deposit reserves", lt: models.ListingTypeDealer, from: models.ListingStatusActive, to: models.ListingStatusReserved, role: models.ActorSystem},
+		{name: "dealer cannot reserve by hand", lt: models.ListingTypeDealer, from: models.ListingStatusActive, to: models.ListingStatusReserved, role: models.ActorDealer, err: ErrTransitionForbidden},
+		{name: "cancelled sale releases reservation", lt: models.ListingTypeDealer, from: models.ListingStatusReserved, to: models.ListingStatusActive, role: models.ActorSystem},
+		{name: "admin releases reservation", lt: models.ListingTypeDealer, from: models.ListingStatusReserved, to: models.ListingStatusActive, role: models.ActorAdmin},
+		{name: "dealer cannot release reservation", lt: models.ListingTypeDealer, from: models.ListingStatusReserved, to: models.ListingStatusActive, role: models.ActorDealer, err: ErrTransitionForbidden},
+		{name: "buyer cannot release reservation", lt: models.ListingTypeDealer, from: models.ListingStatusReserved, to: models.ListingStatusActive, role: models.ActorUser, err: ErrTransitionForbidden},
+		{name: "dealer sells reserved car", lt: models.ListingTypeDealer, from: models.ListingStatusReserved, to: models.ListingStatusSold, role: models.ActorDealer},
+		{name: "only expiry job expires", lt: models.ListingTypeDealer, from: models.ListingStatusActive, to: models.ListingStatusExpired, role: models.ActorAdmin, err: ErrTransitionForbidden},
+		{name: "dealer renews expired", lt: models.ListingTypeDealer, from: models.ListingStatusExpired, to: models.ListingStatusActive, role: models.ActorDealer},
+		{name: "sold is final", lt: models.ListingTypeDealer, from: models.ListingStatusSold, to: models.ListingStatusActive, role: models.ActorAdmin, err: ErrInvalidTransition},
+		{name: "draft cannot be sold", lt: models.ListingTypeDealer, from: models.ListingStatusDraft, to: models.ListingStatusSold, role: models.ActorDealer, err: ErrInvalidTransition},
+		{name: "user accepts bid", lt: models.ListingTypeUserBid, from: models.ListingStatusOpen, to: models.ListingStatusAccepted, role: models.ActorUser},
+		{name: "dealer cannot accept own bid", lt: models.ListingTypeUserBid, from: models.ListingStatusOpen, to: models.ListingStatusAccepted, role: models.ActorDealer, err: ErrTransitionForbidden},
+		{name: "bid listings are never reserved", lt: models.ListingTypeUserBid, from: models.ListingStatusOpen, to: models.ListingStatusReserved, role: models.ActorSystem, err: ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTransition(tt.lt, tt.from, tt.to, tt.role)
+			if tt.err == nil && err != nil {
+				t.Fatalf("CheckTransition(%s, %s, %s, %s) error = %v, want nil", tt.lt, tt.from, tt.to, tt.role, err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CheckTransition(%s, %s, %s, %s) error = %v, want %v", tt.lt, tt.from, tt.to, tt.role, err, tt.err)
+			}
+		})
+	}
+}
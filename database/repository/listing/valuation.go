@@ -0,0 +1,68 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetPricePoints returns a price for every listing that sold, closed or had
+// a bid accepted since the given time. An accepted bid is priced at the
+// dealer's offer, everything else at its asking price.
+func (r *MongoListingsRepository) GetPricePoints(ctx context.Context, since time.Time) ([]models.PricePoint, error) {
+	acceptedOffer := bson.M{"$let": bson.M{
+		"vars": bson.M{"b": bson.M{"$arrayElemAt": bson.A{
+			bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$userListing.bids", bson.A{}}},
+				"as":    "b",
+				"cond":  bson.M{"$eq": bson.A{"$$b._id", "$userListing.acceptedBid"}},
+			}},
+			0,
+		}}},
+		"in": "$$b.offer",
+	}}
+	hasAccepted := bson.M{"$gt": bson.A{"$userListing.acceptedBid", nil}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"updatedAt":       bson.M{"$gte": since},
+			"carDetails.make": bson.M{"$ne": ""},
+			"carDetails.year": bson.M{"$gt": 0},
+			"$or": bson.A{
+				bson.M{"status": bson.M{"$in": bson.A{models.ListingStatusSold, models.ListingStatusClosed}}},
+				bson.M{"userListing.acceptedBid": bson.M{"$exists": true}},
+			},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":     0,
+			"make":    "$carDetails.make",
+			"model":   "$carDetails.model",
+			"year":    "$carDetails.year",
+			"mileage": "$carDetails.mileage",
+			"at":      "$updatedAt",
+			"price":   bson.M{"$cond": bson.A{hasAccepted, acceptedOffer, "$carDetails.price"}},
+			"source": bson.M{"$switch": bson.M{
+				"branches": bson.A{
+					bson.M{"case": hasAccepted, "then": models.PriceSourceAcceptedBid},
+					bson.M{"case": bson.M{"$eq": bson.A{"$status", models.ListingStatusSold}}, "then": models.PriceSourceSale},
+				},
+				"default": models.PriceSourceClosed,
+			}},
+		}}},
+		{{Key: "$match", Value: bson.M{"price": bson.M{"$gt": 0}}}},
+	}
+
+	cursor, err := r.listings.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate price points: %w", err)
+	}
+	points := []models.PricePoint{}
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, fmt.Errorf("failed to decode price points: %w", err)
+	}
+	return points, nil
+}
@@ -0,0 +1,290 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// vinClaimIndex is the partial unique index that lets only one listing hold
+// a VIN. Listings that find the VIN already held are flagged for moderation
+// instead of being rejected.
+const vinClaimIndex = "vinClaim_unique"
+
+// claimAttempts bounds retries when a claim races another listing or is
+// held by a listing that has since left the market.
+const claimAttempts = 3
+
+const duplicateVINMessage = "This VIN is already on another live listing on Carsawa, so your listing " +
+	"has been flagged for review. It stays visible while we check. If this car is yours, " +
+	"reply to support with a copy of the logbook to clear the flag."
+
+func isLive(status models.ListingStatus) bool {
+	for _, s := range models.LiveListingStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func isVINClaimConflict(err error) bool {
+	return mongo.IsDuplicateKeyError(err) && strings.Contains(err.Error(), vinClaimIndex)
+}
+
+// findLiveByVIN returns the other live listings carrying vin.
+func (r *MongoListingsRepository) findLiveByVIN(ctx context.Context, vin string, self primitive.ObjectID) ([]primitive.ObjectID, error) {
+	cursor, err := r.listings.Find(ctx,
+		bson.M{
+			"carDetails.vin": vin,
+			"status":         bson.M{"$in": models.LiveListingStatuses},
+			"_id":            bson.M{"$ne": self},
+		},
+		options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(20),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check VIN: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to check VIN: %w", err)
+	}
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+	return ids, nil
+}
+
+// releaseStaleClaims drops the claim of a listing that is no longer live,
+// so the VIN can be listed again after a sale or close.
+func (r *MongoListingsRepository) releaseStaleClaims(ctx context.Context, vin string) error {
+	_, err := r.listings.UpdateMany(ctx,
+		bson.M{"vinClaim": vin, "status": bson.M{"$nin": models.LiveListingStatuses}},
+		bson.M{"$unset": bson.M{"vinClaim": ""}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release VIN claim: %w", err)
+	}
+	return nil
+}
+
+// withVINClaim runs write with the live listings already carrying vin. write
+// claims the VIN when there are none and flags the listing otherwise; it is
+// retried if the claim is lost to a concurrent listing.
+func (r *MongoListingsRepository) withVINClaim(
+	ctx context.Context,
+	vin string,
+	self primitive.ObjectID,
+	write func(related []primitive.ObjectID) error,
+) error {
+	if vin == "" {
+		return write(nil)
+	}
+	var err error
+	for i := 0; i < claimAttempts; i++ {
+		var related []primitive.ObjectID
+		if related, err = r.findLiveByVIN(ctx, vin, self); err != nil {
+			return err
+		}
+		if err = write(related); !isVINClaimConflict(err) {
+			return err
+		}
+		if err := r.releaseStaleClaims(ctx, vin); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func duplicateVINFlag(related []primitive.ObjectID) models.ModerationFlag {
+	return models.ModerationFlag{
+		Reason:          models.ModerationReasonDuplicateVIN,
+		RelatedListings: related,
+		Message:         duplicateVINMessage,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// PublishListing moves a draft dealer listing to active until expiresAt,
+// claiming its VIN or flagging it when the VIN is already live elsewhere.
+// A nil expiresAt lists it with no end.
+func (r *MongoListingsRepository) PublishListing(ctx context.Context, id string, expiresAt *time.Time) (*models.Listing, error) {
+	lst, err := r.goLive(ctx, id, models.ListingStatusDraft, expiresAt)
+	if err != nil && !errors.Is(err, ErrInvalidTransition) {
+		return nil, fmt.Errorf("failed to publish listing: %w", err)
+	}
+	return lst, err
+}
+
+// goLive moves a dealer listing from an off-market status to active, with
+// the VIN claim and expiry of a fresh listing.
+func (r *MongoListingsRepository) goLive(
+	ctx context.Context,
+	id string,
+	from models.ListingStatus,
+	expiresAt *time.Time,
+) (*models.Listing, error) {
+	lst, err := r.GetListingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lst.Status != from {
+		return nil, ErrInvalidTransition
+	}
+
+	err = r.withVINClaim(ctx, lst.CarDetails.VIN, lst.ID, func(related []primitive.ObjectID) error {
+		set := bson.M{
+			"status":    models.ListingStatusActive,
+			"updatedAt": time.Now(),
+		}
+		unset := bson.M{"expiryRemindedAt": ""}
+		if expiresAt != nil {
+			set["expiresAt"] = *expiresAt
+		} else {
+			unset["expiresAt"] = ""
+		}
+		update := bson.M{"$set": set, "$unset": unset}
+		if len(related) == 0 {
+			if lst.CarDetails.VIN != "" {
+				set["vinClaim"] = lst.CarDetails.VIN
+			}
+		} else {
+			set["moderation.status"] = models.ModerationStatusFlagged
+			update["$push"] = bson.M{"moderation.flags": duplicateVINFlag(related)}
+		}
+
+		res, err := r.listings.UpdateOne(ctx,
+			bson.M{"_id": lst.ID, "status": from},
+			update,
+		)
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount == 0 {
+			return ErrInvalidTransition
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetListingByID(ctx, id)
+}
+
+// VINSuspicion holds the thresholds past which a VIN cluster's prices or
+// odometer readings count as suspicious.
+type VINSuspicion struct {
+	PriceGapPct float64 // Gap between the lowest and highest asking price
+	RollbackKm  int     // Largest fall in mileage from an earlier listing
+}
+
+// GetSuspiciousVINClusters groups listings by VIN, across all statuses so
+// past sales are included, and returns the VINs carried by more than one
+// listing that show a sign of fraud: several live at once, both a user and a
+// dealer seller, a price gap or mileage rollback past the thresholds, or
+// conflicting model years. The checks run before paging, so every page is
+// full while suspicious clusters remain. Clusters with more than one live
+// listing come first.
+func (r *MongoListingsRepository) GetSuspiciousVINClusters(ctx context.Context, t VINSuspicion, pagination models.Pagination) ([]models.VINCluster, error) {
+	limit := pagination.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	// Asking prices of the cluster, leaving out listings without one.
+	prices := bson.M{"$filter": bson.M{
+		"input": "$listings.price",
+		"as":    "p",
+		"cond":  bson.M{"$gt": bson.A{"$$p", 0}},
+	}}
+	// Listings arrive oldest first, so the largest drop below the highest
+	// earlier reading is the worst rollback.
+	rollback := bson.M{"$reduce": bson.M{
+		"input":        "$listings",
+		"initialValue": bson.M{"peak": 0, "drop": 0},
+		"in": bson.M{
+			"peak": bson.M{"$max": bson.A{"$$value.peak", "$$this.mileage"}},
+			"drop": bson.M{"$max": bson.A{"$$value.drop", bson.M{"$subtract": bson.A{"$$value.peak", "$$this.mileage"}}}},
+		},
+	}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"carDetails.vin": bson.M{"$nin": bson.A{"", nil}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$carDetails.vin",
+			"listings": bson.M{"$push": bson.M{
+				"_id":       "$_id",
+				"type":      "$type",
+				"status":    "$status",
+				"dealerId":  "$dealerListing.dealerId",
+				"userId":    "$userListing.userId",
+				"make":      "$carDetails.make",
+				"model":     "$carDetails.model",
+				"year":      "$carDetails.year",
+				"price":     "$carDetails.price",
+				"mileage":   "$carDetails.mileage",
+				"createdAt": "$createdAt",
+			}},
+			"count": bson.M{"$sum": 1},
+			"liveCount": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$in": bson.A{"$status", models.LiveListingStatuses}}, 1, 0},
+			}},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gte": 2}}}},
+		{{Key: "$addFields", Value: bson.M{
+			"minPrice": bson.M{"$min": prices},
+			"maxPrice": bson.M{"$max": prices},
+			"rollback": rollback,
+		}}},
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$or": bson.A{
+			bson.M{"$gt": bson.A{"$liveCount", 1}},
+			bson.M{"$and": bson.A{
+				bson.M{"$in": bson.A{models.ListingTypeDealer, "$listings.type"}},
+				bson.M{"$in": bson.A{models.ListingTypeUserBid, "$listings.type"}},
+			}},
+			bson.M{"$and": bson.A{
+				bson.M{"$gt": bson.A{"$minPrice", 0}},
+				bson.M{"$gte": bson.A{
+					bson.M{"$multiply": bson.A{bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{"$maxPrice", "$minPrice"}}, "$minPrice"}}, 100}},
+					t.PriceGapPct,
+				}},
+			}},
+			bson.M{"$gt": bson.A{"$rollback.drop", t.RollbackKm}},
+			bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$setUnion": bson.A{"$listings.year", bson.A{}}}}, 1}},
+		}}}}},
+		{{Key: "$project", Value: bson.M{"minPrice": 0, "maxPrice": 0, "rollback": 0}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "liveCount", Value: -1},
+			{Key: "count", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$skip", Value: pagination.Offset}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.listings.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find suspicious VIN clusters: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var clusters []models.VINCluster
+	if err := cursor.All(ctx, &clusters); err != nil {
+		return nil, fmt.Errorf("failed to decode VIN clusters: %w", err)
+	}
+	return clusters, nil
+}
@@ -0,0 +1,86 @@
+package listingRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IncrementWatchers adjusts a listing's watcher count by delta. It does not
+// touch updatedAt, since being watched is not a change to the listing.
+func (r *MongoListingsRepository) IncrementWatchers(ctx context.Context, listingID string, delta int) error {
+	objID, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	res, err := r.listings.UpdateOne(ctx,
+		bson.M{"_id": objID},
+		bson.M{"$inc": bson.M{"watchers": delta}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update listing watchers: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetListingsByIDs returns the listing cards for ids that still exist, in no
+// particular order.
+func (r *MongoListingsRepository) GetListingsByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Listing, error) {
+	if len(ids) == 0 {
+		return []models.Listing{}, nil
+	}
+	opts := options.Find().SetProjection(listingCardProjection)
+	cursor, err := r.listings.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listings: %w", err)
+	}
+	results := []models.Listing{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode listings: %w", err)
+	}
+	return results, nil
+}
+
+// GetDealerInterest returns the watcher and view counts of the dealer's
+// listings that are not yet sold or closed, most watched first.
+func (r *MongoListingsRepository) GetDealerInterest(ctx context.Context, dealerID primitive.ObjectID, limit int) ([]models.ListingInterest, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	query := bson.M{
+		"type":                   models.ListingTypeDealer,
+		"dealerListing.dealerId": dealerID,
+		"status":                 bson.M{"$nin": []models.ListingStatus{models.ListingStatusSold, models.ListingStatusClosed}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "watchers", Value: -1}, {Key: "views", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{
+			"make":     "$carDetails.make",
+			"model":    "$carDetails.model",
+			"year":     "$carDetails.year",
+			"price":    "$carDetails.price",
+			"status":   1,
+			"views":    1,
+			"watchers": bson.M{"$ifNull": bson.A{"$watchers", 0}},
+		})
+
+	cursor, err := r.listings.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing interest: %w", err)
+	}
+	results := []models.ListingInterest{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode listing interest: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,34 @@
+package listingEventRepo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoListingEventRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.events.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "listingId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("listingId_createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "listingId", Value: 1},
+				{Key: "changes.field", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+			Options: options.Index().SetName("listingId_changedField"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,36 @@
+package listingEventRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PriceField is the path price changes are recorded under.
+const PriceField = "carDetails.price"
+
+type ListingEventRepository interface {
+	RecordEvent(ctx context.Context, event *models.ListingEvent) error
+	// GetListingEvents returns a page of a listing's events, newest first.
+	GetListingEvents(ctx context.Context, listingID primitive.ObjectID, pagination models.Pagination) ([]models.ListingEvent, error)
+	// GetPriceEvents returns the events that set a listing's price, oldest
+	// first, each carrying only its price change.
+	GetPriceEvents(ctx context.Context, listingID primitive.ObjectID) ([]models.ListingEvent, error)
+}
+
+type MongoListingEventRepository struct {
+	events *mongo.Collection
+}
+
+func NewMongoListingEventRepo(db *mongo.Database) *MongoListingEventRepository {
+	r := &MongoListingEventRepository{
+		events: db.Collection("listing_events"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create listing event indexes: %v\n", err)
+	}
+	return r
+}
@@ -0,0 +1,61 @@
+package listingEventRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoListingEventRepository) RecordEvent(ctx context.Context, event *models.ListingEvent) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+	if _, err := r.events.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to record listing event: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoListingEventRepository) GetListingEvents(ctx context.Context, listingID primitive.ObjectID, pagination models.Pagination) ([]models.ListingEvent, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(pagination.Offset)).
+		SetLimit(int64(pagination.Limit))
+	cursor, err := r.events.Find(ctx, bson.M{"listingId": listingID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listing events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.ListingEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode listing events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *MongoListingEventRepository) GetPriceEvents(ctx context.Context, listingID primitive.ObjectID) ([]models.ListingEvent, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{
+			"listingId": 1,
+			"type":      1,
+			"createdAt": 1,
+			"changes":   bson.M{"$elemMatch": bson.M{"field": PriceField}},
+		})
+	cursor, err := r.events.Find(ctx, bson.M{"listingId": listingID, "changes.field": PriceField}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.ListingEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode price history: %w", err)
+	}
+	return events, nil
+}
@@ -3,6 +3,7 @@ package notificationsRepo
 import (
 	"carsawa/models"
 	"context"
+	"fmt"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -14,13 +15,20 @@ type MongoNotificationRepository struct {
 
 type NotificationRepository interface {
 	CreateNotification(ctx context.Context, n *models.Notification) error
-	FindByRecipient(ctx context.Context, recipientID primitive.ObjectID, target models.NotificationTarget, page, limit int) ([]models.Notification, error)
-	MarkOneRead(ctx context.Context, notificationID primitive.ObjectID) error
+	// FindByRecipient pages newest first, by cursor when pagination has one.
+	FindByRecipient(ctx context.Context, recipientID primitive.ObjectID, target models.NotificationTarget, pagination models.Pagination) ([]models.Notification, models.PageCursors, error)
+	// MarkOneRead returns mongo.ErrNoDocuments unless the notification
+	// belongs to the recipient.
+	MarkOneRead(ctx context.Context, notificationID, recipientID primitive.ObjectID, target models.NotificationTarget) error
 	MarkAllRead(ctx context.Context, recipientID primitive.ObjectID, target models.NotificationTarget) error
 }
 
 func NewMongoNotificationRepository(db *mongo.Database) *MongoNotificationRepository {
-	return &MongoNotificationRepository{
+	r := &MongoNotificationRepository{
 		collection: db.Collection("notifications"),
 	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create notification indexes: %v\n", err)
+	}
+	return r
 }
@@ -1,6 +1,7 @@
 package notificationsRepo
 
 import (
+	"carsawa/database/keyset"
 	"carsawa/models"
 	"context"
 	"time"
@@ -21,30 +22,46 @@ func (r *MongoNotificationRepository) CreateNotification(ctx context.Context, n
 	return err
 }
 
-func (r *MongoNotificationRepository) FindByRecipient(ctx context.Context, recipientID primitive.ObjectID, target models.NotificationTarget, page, limit int) ([]models.Notification, error) {
+// newestFirst is the only ordering notifications are paged in.
+var newestFirst = keyset.Order{Name: "createdAt", Field: "createdAt", Dir: keyset.Desc}
+
+func (r *MongoNotificationRepository) FindByRecipient(ctx context.Context, recipientID primitive.ObjectID, target models.NotificationTarget, pagination models.Pagination) ([]models.Notification, models.PageCursors, error) {
+	c := pagination.Cursor
+	if err := newestFirst.Check(c); err != nil {
+		return nil, models.PageCursors{}, err
+	}
 	filter := bson.M{"recipient": recipientID, "target": target}
+	if f := newestFirst.Filter(c); f != nil {
+		filter["$and"] = bson.A{f}
+	}
+	skip := pagination.Offset
+	if c != nil {
+		skip = 0
+	}
 	opts := options.Find().
-		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
-		SetSkip(int64((page - 1) * limit)).
-		SetLimit(int64(limit))
+		SetSort(newestFirst.Sort(c)).
+		SetSkip(int64(skip)).
+		SetLimit(int64(pagination.Limit + 1))
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, models.PageCursors{}, err
 	}
 	defer cursor.Close(ctx)
 
 	var result []models.Notification
 	if err := cursor.All(ctx, &result); err != nil {
-		return nil, err
+		return nil, models.PageCursors{}, err
 	}
-	return result, nil
+	result, cursors := keyset.Page(newestFirst, result, pagination.Limit, pagination.Offset, c,
+		func(n models.Notification) (interface{}, primitive.ObjectID) { return n.CreatedAt, n.ID })
+	return result, cursors, nil
 }
 
-func (r *MongoNotificationRepository) MarkOneRead(ctx context.Context, id primitive.ObjectID) error {
+func (r *MongoNotificationRepository) MarkOneRead(ctx context.Context, id, recipientID primitive.ObjectID, target models.NotificationTarget) error {
 	res, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		bson.M{"_id": id, "recipient": recipientID, "target": target},
 		bson.M{"$set": bson.M{"read": true, "updatedAt": time.Now()}},
 	)
 	if err != nil {
@@ -0,0 +1,66 @@
+package paymentRepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoPaymentRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexModels := []mongo.IndexModel{
+		{
+			// A retried request with the same Idempotency-Key returns the
+			// original payment instead of sending a second STK push.
+			Keys: bson.D{
+				{Key: "payer.id", Value: 1},
+				{Key: "idempotencyKey", Value: 1},
+			},
+			Options: options.Index().
+				SetName("payer_idempotencyKey").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"idempotencyKey": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{{Key: "checkoutRequestId", Value: 1}},
+			Options: options.Index().
+				SetName("checkoutRequestId").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"checkoutRequestId": bson.M{"$type": "string"}}),
+		},
+		{
+			// The same M-Pesa receipt can never be applied twice.
+			Keys: bson.D{{Key: "receipt", Value: 1}},
+			Options: options.Index().
+				SetName("receipt").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"receipt": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+			Options: options.Index().SetName("status_createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "payer.id", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("payer_createdAt"),
+		},
+	}
+
+	_, err := r.payments.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+	return nil
+}
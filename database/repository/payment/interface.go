@@ -0,0 +1,62 @@
+package paymentRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrNotFound          = errors.New("payment not found")
+	ErrInvalidID         = errors.New("invalid payment ID")
+	ErrDuplicatePayment  = errors.New("payment already exists for this idempotency key")
+	ErrAlreadyProcessed  = errors.New("payment has already been processed")
+	ErrDuplicateCheckout = errors.New("checkout request already recorded")
+)
+
+// PaymentResult is the gateway's final word on a payment.
+type PaymentResult struct {
+	Status     models.PaymentStatus
+	Receipt    string
+	ResultCode int
+	ResultDesc string
+}
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, p *models.Payment) (string, error)
+	GetPaymentByID(ctx context.Context, id string) (*models.Payment, error)
+	GetPaymentByIdempotencyKey(ctx context.Context, payerID primitive.ObjectID, key string) (*models.Payment, error)
+	GetPaymentsByPayer(ctx context.Context, payerID primitive.ObjectID, pagination models.Pagination) ([]models.Payment, error)
+	SetCheckout(ctx context.Context, id, merchantRequestID, checkoutRequestID string) error
+
+	// Complete moves a pending payment to its final status, failing with
+	// ErrAlreadyProcessed if another callback or reconciliation got there first.
+	Complete(ctx context.Context, id string, result PaymentResult) error
+	MarkApplied(ctx context.Context, id string, transactionID primitive.ObjectID) error
+	MarkListingHeld(ctx context.Context, id string) error
+	MarkRefundDue(ctx context.Context, id, reason string) error
+
+	// Reconciliation
+	GetStalePending(ctx context.Context, before time.Time, limit int) ([]models.Payment, error)
+	GetUnapplied(ctx context.Context, before time.Time, limit int) ([]models.Payment, error)
+	IncrementAttempts(ctx context.Context, id string) error
+}
+
+type MongoPaymentRepository struct {
+	payments *mongo.Collection
+}
+
+func NewMongoPaymentRepo(db *mongo.Database) *MongoPaymentRepository {
+	r := &MongoPaymentRepository{
+		payments: db.Collection("payments"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create payment indexes: %v\n", err)
+	}
+	return r
+}
@@ -0,0 +1,270 @@
+package paymentRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoPaymentRepository) CreatePayment(ctx context.Context, p *models.Payment) (string, error) {
+	if p.ID.IsZero() {
+		p.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+
+	if _, err := r.payments.InsertOne(ctx, p); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return "", ErrDuplicatePayment
+		}
+		return "", fmt.Errorf("failed to create payment: %w", err)
+	}
+	return p.ID.Hex(), nil
+}
+
+func (r *MongoPaymentRepository) GetPaymentByID(ctx context.Context, id string) (*models.Payment, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+func (r *MongoPaymentRepository) GetPaymentByIdempotencyKey(ctx context.Context, payerID primitive.ObjectID, key string) (*models.Payment, error) {
+	return r.findOne(ctx, bson.M{"payer.id": payerID, "idempotencyKey": key})
+}
+
+func (r *MongoPaymentRepository) findOne(ctx context.Context, filter bson.M) (*models.Payment, error) {
+	var p models.Payment
+	if err := r.payments.FindOne(ctx, filter).Decode(&p); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *MongoPaymentRepository) GetPaymentsByPayer(
+	ctx context.Context,
+	payerID primitive.ObjectID,
+	pagination models.Pagination,
+) ([]models.Payment, error) {
+	opts := options.Find().
+		SetLimit(int64(pagination.Limit)).
+		SetSkip(int64(pagination.Offset)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.payments.Find(ctx, bson.M{"payer.id": payerID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	var results []models.Payment
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SetCheckout stores the gateway's identifiers once the STK push is accepted.
+func (r *MongoPaymentRepository) SetCheckout(ctx context.Context, id, merchantRequestID, checkoutRequestID string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+	res, err := r.payments.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "checkoutRequestId": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{
+			"merchantRequestId": merchantRequestID,
+			"checkoutRequestId": checkoutRequestID,
+			"updatedAt":         time.Now(),
+		}},
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateCheckout
+		}
+		return fmt.Errorf("failed to record checkout: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoPaymentRepository) Complete(ctx context.Context, id string, result PaymentResult) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"status":      result.Status,
+		"resultCode":  result.ResultCode,
+		"resultDesc":  result.ResultDesc,
+		"completedAt": now,
+		"updatedAt":   now,
+	}
+	if result.Receipt != "" {
+		set["receipt"] = result.Receipt
+	}
+
+	res, err := r.payments.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": models.PaymentStatusPending},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// The receipt was already applied to another payment.
+			return ErrAlreadyProcessed
+		}
+		return fmt.Errorf("failed to complete payment: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.payments.CountDocuments(ctx, bson.M{"_id": objID})
+		if err == nil && n > 0 {
+			return ErrAlreadyProcessed
+		}
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkApplied links a succeeded payment to the transaction it paid into.
+func (r *MongoPaymentRepository) MarkApplied(ctx context.Context, id string, transactionID primitive.ObjectID) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+	_, err = r.payments.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": models.PaymentStatusSucceeded},
+		bson.M{"$set": bson.M{"transactionId": transactionID, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to link payment: %w", err)
+	}
+	return nil
+}
+
+// MarkListingHeld records that a succeeded payment is about to reserve its
+// car, so a retried application can tell its own reservation from another
+// buyer's.
+func (r *MongoPaymentRepository) MarkListingHeld(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+	res, err := r.payments.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": models.PaymentStatusSucceeded},
+		bson.M{"$set": bson.M{"listingHeld": true, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark listing held: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrAlreadyProcessed
+	}
+	return nil
+}
+
+// MarkRefundDue flags a succeeded payment whose money must go back to the payer.
+func (r *MongoPaymentRepository) MarkRefundDue(ctx context.Context, id, reason string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+	res, err := r.payments.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": models.PaymentStatusSucceeded},
+		bson.M{"$set": bson.M{
+			"status":     models.PaymentStatusRefundDue,
+			"resultDesc": reason,
+			"updatedAt":  time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to flag refund: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrAlreadyProcessed
+	}
+	return nil
+}
+
+// GetStalePending returns payments still pending that were created before the
+// cutoff, oldest first.
+func (r *MongoPaymentRepository) GetStalePending(ctx context.Context, before time.Time, limit int) ([]models.Payment, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "createdAt", Value: 1}})
+
+	cursor, err := r.payments.Find(ctx, bson.M{
+		"status":    models.PaymentStatusPending,
+		"createdAt": bson.M{"$lt": before},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending payments: %w", err)
+	}
+
+	var results []models.Payment
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetUnapplied returns succeeded payments created before the cutoff that were
+// never linked to a transaction, oldest first.
+func (r *MongoPaymentRepository) GetUnapplied(ctx context.Context, before time.Time, limit int) ([]models.Payment, error) {
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "createdAt", Value: 1}})
+
+	cursor, err := r.payments.Find(ctx, bson.M{
+		"status":        models.PaymentStatusSucceeded,
+		"createdAt":     bson.M{"$lt": before},
+		"transactionId": bson.M{"$exists": false},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unapplied payments: %w", err)
+	}
+
+	var results []models.Payment
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *MongoPaymentRepository) IncrementAttempts(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+	_, err = r.payments.UpdateOne(
+		ctx,
+		bson.M{"_id": objID},
+		bson.M{
+			"$inc": bson.M{"attempts": 1},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record attempt: %w", err)
+	}
+	return nil
+}
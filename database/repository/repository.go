@@ -1,9 +1,18 @@
 package repository
 
 import (
+	autocompleteRepo "carsawa/database/repository/autocomplete"
 	dealerRepo "carsawa/database/repository/dealer"
+	ledgerRepo "carsawa/database/repository/ledger"
 	listingRepo "carsawa/database/repository/listing"
+	listingEventRepo "carsawa/database/repository/listingevent"
+	paymentRepo "carsawa/database/repository/payment"
+	savedSearchRepo "carsawa/database/repository/savedsearch"
+	tradeInRepo "carsawa/database/repository/tradein"
+	transactionRepo "carsawa/database/repository/transaction"
 	userRepo "carsawa/database/repository/user"
+	vinRepo "carsawa/database/repository/vin"
+	watchlistRepo "carsawa/database/repository/watchlist"
 )
 
 // Re-export the DealerRepository interface and constructors.
@@ -20,3 +29,48 @@ var NewMongoUserRepository = userRepo.NewMongoUserRepo
 type ListingsRepository = listingRepo.ListingRepository
 
 var NewMongoListingsRepo = listingRepo.NewMongoListingsRepository
+
+// Re-export the TradeInRepository interface and constructor.
+type TradeInRepository = tradeInRepo.TradeInRepository
+
+var NewMongoTradeInRepo = tradeInRepo.NewMongoTradeInRepository
+
+// Re-export the TransactionRepository interface and constructor.
+type TransactionRepository = transactionRepo.TransactionRepository
+
+var NewMongoTransactionRepo = transactionRepo.NewMongoTransactionRepo
+
+// Re-export the PaymentRepository interface and constructor.
+type PaymentRepository = paymentRepo.PaymentRepository
+
+var NewMongoPaymentRepo = paymentRepo.NewMongoPaymentRepo
+
+// Re-export the LedgerRepository interface and constructor.
+type LedgerRepository = ledgerRepo.LedgerRepository
+
+var NewMongoLedgerRepo = ledgerRepo.NewMongoLedgerRepo
+
+// Re-export the VINCacheRepository interface and constructor.
+type VINCacheRepository = vinRepo.VINCacheRepository
+
+var NewMongoVINCacheRepo = vinRepo.NewMongoVINCacheRepo
+
+// Re-export the AutocompleteRepository interface and constructor.
+type AutocompleteRepository = autocompleteRepo.AutocompleteRepository
+
+var NewMongoAutocompleteRepo = autocompleteRepo.NewMongoAutocompleteRepo
+
+// Re-export the SavedSearchRepository interface and constructor.
+type SavedSearchRepository = savedSearchRepo.SavedSearchRepository
+
+var NewMongoSavedSearchRepo = savedSearchRepo.NewMongoSavedSearchRepo
+
+// Re-export the WatchlistRepository interface and constructor.
+type WatchlistRepository = watchlistRepo.WatchlistRepository
+
+var NewMongoWatchlistRepo = watchlistRepo.NewMongoWatchlistRepo
+
+// Re-export the ListingEventRepository interface and constructor.
+type ListingEventRepository = listingEventRepo.ListingEventRepository
+
+var NewMongoListingEventRepo = listingEventRepo.NewMongoListingEventRepo
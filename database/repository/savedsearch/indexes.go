@@ -0,0 +1,44 @@
+package savedSearchRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoSavedSearchRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.searches.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("userId_createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "makeKey", Value: 1},
+				{Key: "frequency", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("makeKey_frequency"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "frequency", Value: 1},
+				{Key: "lastNotifiedAt", Value: 1},
+			},
+			Options: options.Index().
+				SetName("digest_due").
+				SetPartialFilterExpression(bson.M{"frequency": models.AlertDaily}),
+		},
+	})
+	return err
+}
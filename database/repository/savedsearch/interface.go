@@ -0,0 +1,54 @@
+package savedSearchRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrNotFound  = errors.New("saved search not found")
+	ErrInvalidID = errors.New("invalid saved search ID")
+)
+
+type SavedSearchRepository interface {
+	CreateSavedSearch(ctx context.Context, search *models.SavedSearch) error
+	GetSavedSearch(ctx context.Context, id string, userID primitive.ObjectID) (*models.SavedSearch, error)
+	GetUserSavedSearches(ctx context.Context, userID primitive.ObjectID) ([]models.SavedSearch, error)
+	CountUserSavedSearches(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	UpdateSavedSearch(ctx context.Context, id string, userID primitive.ObjectID, set bson.M) (*models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id string, userID primitive.ObjectID) error
+
+	// Matching
+	// GetAlertCandidates pages, by ID, through the saved searches with alerts
+	// on that could match a car of the given make.
+	GetAlertCandidates(ctx context.Context, makeKey string, after primitive.ObjectID, limit int) ([]models.SavedSearch, error)
+	MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// AddPending queues a match for the next digest, keeping the newest max.
+	AddPending(ctx context.Context, id, listingID primitive.ObjectID, max int) error
+	// GetDueDigests returns daily searches with matches queued whose last
+	// digest, or creation when there has been none, was no later than before.
+	GetDueDigests(ctx context.Context, before time.Time, limit int) ([]models.SavedSearch, error)
+	// CompleteDigest clears the sent matches, keeping any queued meanwhile.
+	CompleteDigest(ctx context.Context, id primitive.ObjectID, sent []primitive.ObjectID, at time.Time) error
+}
+
+type MongoSavedSearchRepository struct {
+	searches *mongo.Collection
+}
+
+func NewMongoSavedSearchRepo(db *mongo.Database) *MongoSavedSearchRepository {
+	r := &MongoSavedSearchRepository{
+		searches: db.Collection("saved_searches"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create saved search indexes: %v\n", err)
+	}
+	return r
+}
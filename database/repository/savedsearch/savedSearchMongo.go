@@ -0,0 +1,167 @@
+package savedSearchRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoSavedSearchRepository) CreateSavedSearch(ctx context.Context, search *models.SavedSearch) error {
+	if search.ID.IsZero() {
+		search.ID = primitive.NewObjectID()
+	}
+	if _, err := r.searches.InsertOne(ctx, search); err != nil {
+		return fmt.Errorf("failed to create saved search: %w", err)
+	}
+	return nil
+}
+
+// ownedBy matches the saved search id if it belongs to userID.
+func ownedBy(id string, userID primitive.ObjectID) (bson.M, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+	return bson.M{"_id": oid, "userId": userID}, nil
+}
+
+func (r *MongoSavedSearchRepository) GetSavedSearch(ctx context.Context, id string, userID primitive.ObjectID) (*models.SavedSearch, error) {
+	filter, err := ownedBy(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	var search models.SavedSearch
+	err = r.searches.FindOne(ctx, filter).Decode(&search)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+	return &search, nil
+}
+
+func (r *MongoSavedSearchRepository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.SavedSearch, error) {
+	cursor, err := r.searches.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find saved searches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	searches := []models.SavedSearch{}
+	if err := cursor.All(ctx, &searches); err != nil {
+		return nil, fmt.Errorf("failed to decode saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+func (r *MongoSavedSearchRepository) GetUserSavedSearches(ctx context.Context, userID primitive.ObjectID) ([]models.SavedSearch, error) {
+	return r.find(ctx, bson.M{"userId": userID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+}
+
+func (r *MongoSavedSearchRepository) CountUserSavedSearches(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	n, err := r.searches.CountDocuments(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count saved searches: %w", err)
+	}
+	return n, nil
+}
+
+func (r *MongoSavedSearchRepository) UpdateSavedSearch(ctx context.Context, id string, userID primitive.ObjectID, set bson.M) (*models.SavedSearch, error) {
+	filter, err := ownedBy(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	var search models.SavedSearch
+	err = r.searches.FindOneAndUpdate(ctx, filter, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&search)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+	return &search, nil
+}
+
+func (r *MongoSavedSearchRepository) DeleteSavedSearch(ctx context.Context, id string, userID primitive.ObjectID) error {
+	filter, err := ownedBy(id, userID)
+	if err != nil {
+		return err
+	}
+	res, err := r.searches.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoSavedSearchRepository) GetAlertCandidates(ctx context.Context, makeKey string, after primitive.ObjectID, limit int) ([]models.SavedSearch, error) {
+	filter := bson.M{
+		"makeKey":   bson.M{"$in": bson.A{"", makeKey}},
+		"frequency": bson.M{"$ne": models.AlertOff},
+	}
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+	return r.find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"pending": 0}))
+}
+
+func (r *MongoSavedSearchRepository) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.searches.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"lastNotifiedAt": at}})
+	if err != nil {
+		return fmt.Errorf("failed to mark saved search notified: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSavedSearchRepository) AddPending(ctx context.Context, id, listingID primitive.ObjectID, max int) error {
+	_, err := r.searches.UpdateOne(ctx,
+		bson.M{"_id": id, "pending": bson.M{"$ne": listingID}},
+		bson.M{"$push": bson.M{"pending": bson.M{"$each": bson.A{listingID}, "$slice": -max}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to queue saved search match: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoSavedSearchRepository) GetDueDigests(ctx context.Context, before time.Time, limit int) ([]models.SavedSearch, error) {
+	return r.find(ctx, bson.M{
+		"frequency": models.AlertDaily,
+		"pending.0": bson.M{"$exists": true},
+		"$or": bson.A{
+			bson.M{"lastNotifiedAt": bson.M{"$lte": before}},
+			bson.M{"lastNotifiedAt": bson.M{"$exists": false}, "createdAt": bson.M{"$lte": before}},
+		},
+	}, options.Find().SetLimit(int64(limit)))
+}
+
+func (r *MongoSavedSearchRepository) CompleteDigest(ctx context.Context, id primitive.ObjectID, sent []primitive.ObjectID, at time.Time) error {
+	_, err := r.searches.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$pullAll": bson.M{"pending": sent},
+			"$set":     bson.M{"lastNotifiedAt": at},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete saved search digest: %w", err)
+	}
+	return nil
+}
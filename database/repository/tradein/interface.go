@@ -0,0 +1,46 @@
+package tradeInRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrNotFound      = errors.New("trade-in not found")
+	ErrInvalidID     = errors.New("invalid trade-in ID")
+	ErrOfferConflict = errors.New("trade-in offer is no longer available")
+	ErrNotOpen       = errors.New("trade-in is no longer open")
+)
+
+type TradeInRepository interface {
+	CreateTradeIn(ctx context.Context, tradeIn *models.TradeIn) (string, error)
+	GetTradeInByID(ctx context.Context, id string) (*models.TradeIn, error)
+	GetTradeIns(ctx context.Context, filter models.TradeInFilter, pagination models.Pagination) ([]models.TradeIn, error)
+	CancelTradeIn(ctx context.Context, id string, userID primitive.ObjectID) error
+	// GetAcceptedTradeIn returns the user's accepted trade-in on a listing.
+	GetAcceptedTradeIn(ctx context.Context, userID, listingID primitive.ObjectID) (*models.TradeIn, error)
+
+	// Offer operations
+	AddOffer(ctx context.Context, id string, offer models.TradeInOffer) error
+	AcceptOffer(ctx context.Context, id, offerID string, deal models.TradeInDeal) error
+	AddContact(ctx context.Context, id string, contact models.TradeInContact) error
+}
+
+type MongoTradeInRepository struct {
+	tradeIns *mongo.Collection
+}
+
+func NewMongoTradeInRepository(db *mongo.Database) *MongoTradeInRepository {
+	r := &MongoTradeInRepository{
+		tradeIns: db.Collection("trade_ins"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create trade-in indexes: %v\n", err)
+	}
+	return r
+}
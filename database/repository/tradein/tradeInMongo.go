@@ -0,0 +1,276 @@
+package tradeInRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateTradeIn inserts a new open trade-in.
+func (r *MongoTradeInRepository) CreateTradeIn(ctx context.Context, tradeIn *models.TradeIn) (string, error) {
+	if tradeIn.ID.IsZero() {
+		tradeIn.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	tradeIn.Status = models.TradeInStatusOpen
+	tradeIn.Offers = []models.TradeInOffer{}
+	tradeIn.AcceptedOffer = nil
+	tradeIn.Deal = nil
+	tradeIn.CreatedAt = now
+	tradeIn.UpdatedAt = now
+
+	if _, err := r.tradeIns.InsertOne(ctx, tradeIn); err != nil {
+		return "", fmt.Errorf("failed to create trade-in: %w", err)
+	}
+	return tradeIn.ID.Hex(), nil
+}
+
+// GetTradeInByID retrieves a trade-in by ID.
+func (r *MongoTradeInRepository) GetTradeInByID(ctx context.Context, id string) (*models.TradeIn, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	var tradeIn models.TradeIn
+	if err := r.tradeIns.FindOne(ctx, bson.M{"_id": objID}).Decode(&tradeIn); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get trade-in: %w", err)
+	}
+	return &tradeIn, nil
+}
+
+func (r *MongoTradeInRepository) GetAcceptedTradeIn(ctx context.Context, userID, listingID primitive.ObjectID) (*models.TradeIn, error) {
+	var tradeIn models.TradeIn
+	err := r.tradeIns.FindOne(ctx, bson.M{
+		"userId":          userID,
+		"targetListingId": listingID,
+		"status":          models.TradeInStatusAccepted,
+	}).Decode(&tradeIn)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get accepted trade-in: %w", err)
+	}
+	return &tradeIn, nil
+}
+
+// GetTradeIns lists trade-ins matching the filter, newest first.
+func (r *MongoTradeInRepository) GetTradeIns(ctx context.Context, filter models.TradeInFilter, pagination models.Pagination) ([]models.TradeIn, error) {
+	query := bson.M{}
+	if filter.UserID != "" {
+		userID, err := primitive.ObjectIDFromHex(filter.UserID)
+		if err != nil {
+			return nil, ErrInvalidID
+		}
+		query["userId"] = userID
+	}
+	if filter.DealerID != "" {
+		dealerID, err := primitive.ObjectIDFromHex(filter.DealerID)
+		if err != nil {
+			return nil, ErrInvalidID
+		}
+		query["dealerId"] = dealerID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.MinYear > 0 {
+		query["carDetails.year"] = bson.M{"$gte": filter.MinYear}
+	}
+	if filter.MaxMileage > 0 {
+		query["carDetails.mileage"] = bson.M{"$lte": filter.MaxMileage}
+	}
+
+	opts := options.Find().
+		SetLimit(int64(pagination.Limit)).
+		SetSkip(int64(pagination.Offset)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.tradeIns.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trade-ins: %w", err)
+	}
+
+	var results []models.TradeIn
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CancelTradeIn withdraws an open trade-in on behalf of its owner.
+func (r *MongoTradeInRepository) CancelTradeIn(ctx context.Context, id string, userID primitive.ObjectID) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	res, err := r.tradeIns.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "userId": userID, "status": models.TradeInStatusOpen},
+		bson.M{"$set": bson.M{
+			"status":                      models.TradeInStatusCancelled,
+			"offers.$[pending].status":    models.TradeInOfferRejected,
+			"offers.$[pending].updatedAt": now,
+			"updatedAt":                   now,
+		}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"pending.status": models.TradeInOfferPending},
+		}}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to cancel trade-in: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.tradeIns.CountDocuments(ctx, bson.M{"_id": objID, "userId": userID})
+		if err == nil && n > 0 {
+			return ErrNotOpen
+		}
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddOffer records a dealer valuation, superseding that dealer's earlier
+// pending offers. Both writes run in one transaction so the user never sees
+// two live valuations from the same dealer.
+func (r *MongoTradeInRepository) AddOffer(ctx context.Context, id string, offer models.TradeInOffer) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	offer.ID = primitive.NewObjectID()
+	offer.Status = models.TradeInOfferPending
+	offer.CreatedAt = now
+	offer.UpdatedAt = now
+
+	session, err := r.tradeIns.Database().Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		open := bson.M{"_id": objID, "status": models.TradeInStatusOpen}
+
+		res, err := r.tradeIns.UpdateOne(
+			sessCtx,
+			open,
+			bson.M{"$set": bson.M{
+				"offers.$[prev].status":    models.TradeInOfferSuperseded,
+				"offers.$[prev].updatedAt": now,
+			}},
+			options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+				bson.M{"prev.dealerId": offer.DealerID, "prev.status": models.TradeInOfferPending},
+			}}),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to supersede offers: %w", err)
+		}
+		if res.MatchedCount == 0 {
+			n, cerr := r.tradeIns.CountDocuments(sessCtx, bson.M{"_id": objID})
+			if cerr == nil && n > 0 {
+				return nil, ErrNotOpen
+			}
+			return nil, ErrNotFound
+		}
+
+		if _, err := r.tradeIns.UpdateOne(sessCtx, open, bson.M{
+			"$push": bson.M{"offers": offer},
+			"$set":  bson.M{"updatedAt": now},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add offer: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// AcceptOffer accepts a pending offer, rejects the rest and records the deal.
+func (r *MongoTradeInRepository) AcceptOffer(ctx context.Context, id, offerID string, deal models.TradeInDeal) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+	offerObjID, err := primitive.ObjectIDFromHex(offerID)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	res, err := r.tradeIns.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":    objID,
+			"status": models.TradeInStatusOpen,
+			"offers": bson.M{"$elemMatch": bson.M{
+				"_id":    offerObjID,
+				"status": models.TradeInOfferPending,
+			}},
+		},
+		bson.M{"$set": bson.M{
+			"status":                    models.TradeInStatusAccepted,
+			"acceptedOffer":             offerObjID,
+			"deal":                      deal,
+			"offers.$[win].status":      models.TradeInOfferAccepted,
+			"offers.$[win].updatedAt":   now,
+			"offers.$[other].status":    models.TradeInOfferRejected,
+			"offers.$[other].updatedAt": now,
+			"updatedAt":                 now,
+		}},
+		options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"win._id": offerObjID},
+			bson.M{"other._id": bson.M{"$ne": offerObjID}, "other.status": models.TradeInOfferPending},
+		}}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to accept offer: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.tradeIns.CountDocuments(ctx, bson.M{"_id": objID})
+		if err == nil && n > 0 {
+			return ErrOfferConflict
+		}
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddContact logs a dealer reaching out about a trade-in that is still live.
+func (r *MongoTradeInRepository) AddContact(ctx context.Context, id string, contact models.TradeInContact) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+	contact.CreatedAt = time.Now()
+
+	res, err := r.tradeIns.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": bson.M{"$ne": models.TradeInStatusCancelled}},
+		bson.M{
+			"$push": bson.M{"contacts": contact},
+			"$set":  bson.M{"updatedAt": contact.CreatedAt},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record contact: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotOpen
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package transactionRepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoTransactionRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	indexModels := []mongo.IndexModel{
+		{
+			// A listing can only be sold once at a time; cancelled and refunded
+			// transactions drop out of the index so the car can be sold again.
+			Keys: bson.D{{Key: "listingId", Value: 1}},
+			Options: options.Index().
+				SetName("open_listingId").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"open": true}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "buyer.id", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("buyer_createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "seller.id", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("seller_createdAt"),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+	}
+
+	_, err := r.transactions.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return fmt.Errorf("failed to create indexes: %w", err)
+	}
+	return nil
+}
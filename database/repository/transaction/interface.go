@@ -0,0 +1,59 @@
+package transactionRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrNotFound             = errors.New("transaction not found")
+	ErrInvalidID            = errors.New("invalid transaction ID")
+	ErrDuplicateTransaction = errors.New("listing already has an open transaction")
+	ErrStatusConflict       = errors.New("transaction status changed concurrently")
+)
+
+// PartyRole selects which side of a transaction a party query matches.
+type PartyRole string
+
+const (
+	RoleBuyer  PartyRole = "buyer"
+	RoleSeller PartyRole = "seller"
+)
+
+type TransactionRepository interface {
+	CreateTransaction(ctx context.Context, tx *models.Transaction) (string, error)
+	GetTransactionByID(ctx context.Context, id string) (*models.Transaction, error)
+	GetOpenTransactionForListing(ctx context.Context, listingID primitive.ObjectID) (*models.Transaction, error)
+	GetTransactionsByParty(ctx context.Context, partyID primitive.ObjectID, role PartyRole, pagination models.Pagination) ([]models.Transaction, error)
+
+	// UpdateStatus moves a transaction from one status to another, failing with
+	// ErrStatusConflict if the status is no longer from.
+	UpdateStatus(ctx context.Context, id string, from, to models.TransactionStatus, event models.TransactionEvent) error
+	// SetBuyer records the buyer, and any trade-in credited to them, on a
+	// sale opened without one.
+	SetBuyer(ctx context.Context, id string, buyer models.TransactionParty, tradeIn *models.TransactionTradeIn) error
+	// MarkDepositPaid records a cleared deposit and moves a pending transaction to deposit_paid.
+	MarkDepositPaid(ctx context.Context, id string, amount float64, event models.TransactionEvent) error
+	// ConfirmHandover stamps one side's handover confirmation on a pending or
+	// deposit_paid transaction and returns the result; confirming twice is a no-op.
+	ConfirmHandover(ctx context.Context, id string, role PartyRole) (*models.Transaction, error)
+}
+
+type MongoTransactionRepository struct {
+	transactions *mongo.Collection
+}
+
+func NewMongoTransactionRepo(db *mongo.Database) *MongoTransactionRepository {
+	r := &MongoTransactionRepository{
+		transactions: db.Collection("transactions"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create transaction indexes: %v\n", err)
+	}
+	return r
+}
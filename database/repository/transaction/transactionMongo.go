@@ -0,0 +1,251 @@
+package transactionRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// isOpen reports whether a status still holds the listing.
+func isOpen(status models.TransactionStatus) bool {
+	return status != models.TransactionStatusCancelled && status != models.TransactionStatusRefunded
+}
+
+// CreateTransaction inserts a transaction; the unique open_listingId index
+// rejects a second open transaction on the same listing.
+func (r *MongoTransactionRepository) CreateTransaction(ctx context.Context, tx *models.Transaction) (string, error) {
+	if tx.ID.IsZero() {
+		tx.ID = primitive.NewObjectID()
+	}
+	now := time.Now()
+	tx.Open = isOpen(tx.Status)
+	tx.CreatedAt = now
+	tx.UpdatedAt = now
+	if tx.History == nil {
+		tx.History = []models.TransactionEvent{{To: tx.Status, CreatedAt: now}}
+	}
+
+	if _, err := r.transactions.InsertOne(ctx, tx); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return "", ErrDuplicateTransaction
+		}
+		return "", fmt.Errorf("failed to create transaction: %w", err)
+	}
+	return tx.ID.Hex(), nil
+}
+
+func (r *MongoTransactionRepository) GetTransactionByID(ctx context.Context, id string) (*models.Transaction, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+func (r *MongoTransactionRepository) GetOpenTransactionForListing(ctx context.Context, listingID primitive.ObjectID) (*models.Transaction, error) {
+	return r.findOne(ctx, bson.M{"listingId": listingID, "open": true})
+}
+
+func (r *MongoTransactionRepository) findOne(ctx context.Context, filter bson.M) (*models.Transaction, error) {
+	var tx models.Transaction
+	if err := r.transactions.FindOne(ctx, filter).Decode(&tx); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	return &tx, nil
+}
+
+// GetTransactionsByParty lists a party's purchases or sales, newest first.
+func (r *MongoTransactionRepository) GetTransactionsByParty(
+	ctx context.Context,
+	partyID primitive.ObjectID,
+	role PartyRole,
+	pagination models.Pagination,
+) ([]models.Transaction, error) {
+	field := "seller.id"
+	if role == RoleBuyer {
+		field = "buyer.id"
+	}
+
+	opts := options.Find().
+		SetLimit(int64(pagination.Limit)).
+		SetSkip(int64(pagination.Offset)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.transactions.Find(ctx, bson.M{field: partyID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	var results []models.Transaction
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *MongoTransactionRepository) UpdateStatus(
+	ctx context.Context,
+	id string,
+	from, to models.TransactionStatus,
+	event models.TransactionEvent,
+) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	event.From = from
+	event.To = to
+	event.CreatedAt = now
+
+	res, err := r.transactions.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": from},
+		bson.M{
+			"$set": bson.M{
+				"status":    to,
+				"open":      isOpen(to),
+				"updatedAt": now,
+			},
+			"$push": bson.M{"history": event},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.transactions.CountDocuments(ctx, bson.M{"_id": objID})
+		if err == nil && n > 0 {
+			return ErrStatusConflict
+		}
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetBuyer records the buyer on a dealer sale that was opened without one.
+func (r *MongoTransactionRepository) SetBuyer(ctx context.Context, id string, buyer models.TransactionParty, tradeIn *models.TransactionTradeIn) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	set := bson.M{"buyer": buyer, "updatedAt": time.Now()}
+	if tradeIn != nil {
+		set["tradeIn"] = tradeIn
+	}
+	res, err := r.transactions.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "open": true, "buyer": bson.M{"$exists": false}},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set buyer: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.transactions.CountDocuments(ctx, bson.M{"_id": objID})
+		if err == nil && n > 0 {
+			return ErrStatusConflict
+		}
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoTransactionRepository) MarkDepositPaid(
+	ctx context.Context,
+	id string,
+	amount float64,
+	event models.TransactionEvent,
+) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	now := time.Now()
+	event.From = models.TransactionStatusPending
+	event.To = models.TransactionStatusDepositPaid
+	event.CreatedAt = now
+
+	res, err := r.transactions.UpdateOne(
+		ctx,
+		bson.M{"_id": objID, "status": models.TransactionStatusPending},
+		bson.M{
+			"$set": bson.M{
+				"status":    models.TransactionStatusDepositPaid,
+				"deposit":   amount,
+				"updatedAt": now,
+			},
+			"$push": bson.M{"history": event},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record deposit: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		n, err := r.transactions.CountDocuments(ctx, bson.M{"_id": objID})
+		if err == nil && n > 0 {
+			return ErrStatusConflict
+		}
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *MongoTransactionRepository) ConfirmHandover(ctx context.Context, id string, role PartyRole) (*models.Transaction, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidID
+	}
+
+	field := "handover." + string(role) + "ConfirmedAt"
+	now := time.Now()
+	var tx models.Transaction
+	err = r.transactions.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id":    objID,
+			"status": bson.M{"$in": []models.TransactionStatus{models.TransactionStatusPending, models.TransactionStatusDepositPaid}},
+			field:    bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{field: now, "updatedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&tx)
+	if err == nil {
+		return &tx, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("failed to confirm handover: %w", err)
+	}
+
+	// Nothing matched: either this side already confirmed or the sale has moved on.
+	current, err := r.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Handover != nil && handoverConfirmed(current.Handover, role) &&
+		(current.Status == models.TransactionStatusPending || current.Status == models.TransactionStatusDepositPaid ||
+			current.Status == models.TransactionStatusCompleted) {
+		return current, nil
+	}
+	return nil, ErrStatusConflict
+}
+
+func handoverConfirmed(h *models.TransactionHandover, role PartyRole) bool {
+	if role == RoleBuyer {
+		return h.BuyerConfirmedAt != nil
+	}
+	return h.SellerConfirmedAt != nil
+}
@@ -0,0 +1,34 @@
+package vinRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrNotFound = errors.New("VIN not in cache")
+
+// VINCacheRepository is the durable tier of the VIN decode cache; entries
+// outlive Redis restarts and are reaped by a TTL index once they expire.
+type VINCacheRepository interface {
+	// GetEntry returns ErrNotFound for unknown or expired numbers.
+	GetEntry(ctx context.Context, number string) (*models.VINCacheEntry, error)
+	PutEntry(ctx context.Context, entry *models.VINCacheEntry) error
+}
+
+type MongoVINCacheRepository struct {
+	entries *mongo.Collection
+}
+
+func NewMongoVINCacheRepo(db *mongo.Database) *MongoVINCacheRepository {
+	r := &MongoVINCacheRepository{
+		entries: db.Collection("vin_decodes"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create VIN cache indexes: %v\n", err)
+	}
+	return r
+}
@@ -0,0 +1,38 @@
+package vinRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoVINCacheRepository) GetEntry(ctx context.Context, number string) (*models.VINCacheEntry, error) {
+	var entry models.VINCacheEntry
+	// The TTL monitor only runs once a minute, so expiry is checked here too.
+	err := r.entries.FindOne(ctx, bson.M{
+		"_id":       number,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VIN cache entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *MongoVINCacheRepository) PutEntry(ctx context.Context, entry *models.VINCacheEntry) error {
+	entry.CreatedAt = time.Now()
+	_, err := r.entries.ReplaceOne(ctx, bson.M{"_id": entry.Number}, entry, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to cache VIN decode: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package watchlistRepo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoWatchlistRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.entries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// One entry per user and listing; also serves the user's list.
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "listingId", Value: 1},
+			},
+			Options: options.Index().SetName("userId_listingId").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("userId_createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "listingId", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("listingId"),
+		},
+	})
+	return err
+}
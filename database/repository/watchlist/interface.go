@@ -0,0 +1,36 @@
+package watchlistRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type WatchlistRepository interface {
+	// AddEntry reports false when the user already watches the listing.
+	AddEntry(ctx context.Context, entry *models.WatchlistEntry) (bool, error)
+	// RemoveEntry returns the removed entry, or nil when the user was not
+	// watching the listing.
+	RemoveEntry(ctx context.Context, userID, listingID primitive.ObjectID) (*models.WatchlistEntry, error)
+	RemoveListing(ctx context.Context, listingID primitive.ObjectID) error
+	GetUserEntries(ctx context.Context, userID primitive.ObjectID, pagination models.Pagination) ([]models.WatchlistEntry, error)
+	// GetWatcherIDs pages, by entry ID, through the users watching a listing.
+	GetWatcherIDs(ctx context.Context, listingID primitive.ObjectID, after primitive.ObjectID, limit int) ([]primitive.ObjectID, primitive.ObjectID, error)
+}
+
+type MongoWatchlistRepository struct {
+	entries *mongo.Collection
+}
+
+func NewMongoWatchlistRepo(db *mongo.Database) *MongoWatchlistRepository {
+	r := &MongoWatchlistRepository{
+		entries: db.Collection("watchlist"),
+	}
+	if err := r.ensureIndexes(); err != nil {
+		fmt.Printf("failed to create watchlist indexes: %v\n", err)
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+package watchlistRepo
+
+import (
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *MongoWatchlistRepository) AddEntry(ctx context.Context, entry *models.WatchlistEntry) (bool, error) {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	res, err := r.entries.UpdateOne(ctx,
+		bson.M{"userId": entry.UserID, "listingId": entry.ListingID},
+		bson.M{"$setOnInsert": entry},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to add to watchlist: %w", err)
+	}
+	return res.UpsertedCount > 0, nil
+}
+
+func (r *MongoWatchlistRepository) RemoveEntry(ctx context.Context, userID, listingID primitive.ObjectID) (*models.WatchlistEntry, error) {
+	var entry models.WatchlistEntry
+	err := r.entries.FindOneAndDelete(ctx, bson.M{"userId": userID, "listingId": listingID}).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove from watchlist: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *MongoWatchlistRepository) RemoveListing(ctx context.Context, listingID primitive.ObjectID) error {
+	if _, err := r.entries.DeleteMany(ctx, bson.M{"listingId": listingID}); err != nil {
+		return fmt.Errorf("failed to clear listing watchers: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWatchlistRepository) GetUserEntries(ctx context.Context, userID primitive.ObjectID, pagination models.Pagination) ([]models.WatchlistEntry, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(int64(pagination.Offset)).
+		SetLimit(int64(pagination.Limit))
+	cursor, err := r.entries.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.WatchlistEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode watchlist: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *MongoWatchlistRepository) GetWatcherIDs(ctx context.Context, listingID, after primitive.ObjectID, limit int) ([]primitive.ObjectID, primitive.ObjectID, error) {
+	filter := bson.M{"listingId": listingID}
+	if !after.IsZero() {
+		filter["_id"] = bson.M{"$gt": after}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"userId": 1})
+	cursor, err := r.entries.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, after, fmt.Errorf("failed to get watchers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		UserID primitive.ObjectID `bson:"userId"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, after, fmt.Errorf("failed to decode watchers: %w", err)
+	}
+	users := make([]primitive.ObjectID, len(docs))
+	for i, d := range docs {
+		users[i] = d.UserID
+		after = d.ID
+	}
+	return users, after, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"time"
+
+	"carsawa/config"
+	vinRepo "carsawa/database/repository/vin"
+	"carsawa/services/vin"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// newVINDecoder builds the decoder the listing service checks VINs with: the
+// offline tables, then the registries named in VIN_DECODER_PROVIDERS, behind
+// the Redis and Mongo decode caches.
+func newVINDecoder(db *mongo.Database, rdb *redis.Client) (vin.VINDecoder, error) {
+	cfg := config.AppConfig
+	providers, err := vin.NewProviders(cfg.VINDecoderProviders, vin.HTTPProviderConfig{
+		URL:        cfg.VINDecoderHTTPURL,
+		APIKey:     cfg.VINDecoderHTTPAPIKey,
+		KeyHeader:  cfg.VINDecoderHTTPKeyHeader,
+		MakeField:  cfg.VINDecoderHTTPMakeField,
+		ModelField: cfg.VINDecoderHTTPModelField,
+		YearField:  cfg.VINDecoderHTTPYearField,
+	}, time.Duration(cfg.VINDecoderTimeoutSecs)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return vin.NewCachedDecoder(vin.NewChain(providers...), rdb, vinRepo.NewMongoVINCacheRepo(db), vin.CacheConfig{
+		TTL:         time.Duration(cfg.VINCacheTTLHours) * time.Hour,
+		NegativeTTL: time.Duration(cfg.VINCacheNegativeTTLHours) * time.Hour,
+	}), nil
+}
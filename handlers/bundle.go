@@ -26,9 +26,23 @@ type HandlerBundle struct {
 	UpdateListingHandler       func(c *gin.Context)
 	DeleteListingHandler       func(c *gin.Context)
 	GetDealerListingsHandler   func(c *gin.Context)
+	RenewListingHandler        func(c *gin.Context)
 	GetTradeInLeadsHandler     func(c *gin.Context)
 	ContactUserHandler         func(c *gin.Context)
+	MakeTradeInOfferHandler    func(c *gin.Context)
 	PlaceBidOnUserCarHandler   func(c *gin.Context)
+	UpdateBidHandler           func(c *gin.Context)
+	DealerCounterBidHandler    func(c *gin.Context)
+	WithdrawBidHandler         func(c *gin.Context)
+	UploadListingMediaHandler  func(c *gin.Context)
+	ReorderListingMediaHandler func(c *gin.Context)
+	SetListingCoverHandler     func(c *gin.Context)
+	UpdateMediaCaptionHandler  func(c *gin.Context)
+	DeleteListingMediaHandler  func(c *gin.Context)
+	GetLedgerBalanceHandler    func(c *gin.Context)
+	GetLedgerPayoutsHandler    func(c *gin.Context)
+	GetLedgerStatementHandler  func(c *gin.Context)
+	GetSearchTrendsHandler     func(c *gin.Context)
 
 	// User Handlers
 	RegisterUserHandler               func(c *gin.Context)
@@ -45,28 +59,58 @@ type HandlerBundle struct {
 	DeleteMyCarHandler                func(c *gin.Context)
 	GetMyCarBidsHandler               func(c *gin.Context)
 	AcceptDealerBidHandler            func(c *gin.Context)
+	CounterBidHandler                 func(c *gin.Context)
+	RejectBidHandler                  func(c *gin.Context)
 	CreateTradeInHandler              func(c *gin.Context)
 	GetUserTradeInsHandler            func(c *gin.Context)
 	DeleteTradeInHandler              func(c *gin.Context)
 	GetTradeInOffersHandler           func(c *gin.Context)
+	AcceptTradeInOfferHandler         func(c *gin.Context)
+	InitiateDepositHandler            func(c *gin.Context)
+	GetUserPaymentsHandler            func(c *gin.Context)
+	GetPaymentHandler                 func(c *gin.Context)
 	GetNotificationsHandler           func(c *gin.Context)
 	MarkNotificationsReadHandler      func(c *gin.Context)
 	MarkAllNotificationsReadHandler   func(c *gin.Context)
 	GetUnreadNotificationCountHandler func(c *gin.Context)
 	GetPublicTradeInsHandler          func(c *gin.Context)
+	CreateSavedSearchHandler          func(c *gin.Context)
+	GetSavedSearchesHandler           func(c *gin.Context)
+	UpdateSavedSearchHandler          func(c *gin.Context)
+	DeleteSavedSearchHandler          func(c *gin.Context)
+	GetWatchlistHandler               func(c *gin.Context)
+	WatchListingHandler               func(c *gin.Context)
+	UnwatchListingHandler             func(c *gin.Context)
+	GetListingInterestHandler         func(c *gin.Context)
 
 	// Public/Feed Handlers
 	GetListingsHandler         func(c *gin.Context)
 	SearchHandler              func(c *gin.Context)
+	SearchSuggestionsHandler   func(c *gin.Context)
+	RecordSearchClickHandler   func(c *gin.Context)
+	GetPriceHistoryHandler     func(c *gin.Context)
+	GetValuationHandler        func(c *gin.Context)
 	PublicDealerProfileHandler func(c *gin.Context)
 
 	// Miscellaneous
-	UploadFileHandler       func(c *gin.Context)
-	GetDownloadURLHandler   func(c *gin.Context)
-	GetUserPurchasesHandler func(c *gin.Context)
-	GetUserSalesHandler     func(c *gin.Context)
-	RecordPurchaseHandler   func(c *gin.Context)
-	RecordSaleHandler       func(c *gin.Context)
+	UploadFileHandler              func(c *gin.Context)
+	GetDownloadURLHandler          func(c *gin.Context)
+	ServePublicFileHandler         func(c *gin.Context)
+	ServeSecureFileHandler         func(c *gin.Context)
+	PaymentCallbackHandler         func(c *gin.Context)
+	GetUserPurchasesHandler        func(c *gin.Context)
+	GetUserSalesHandler            func(c *gin.Context)
+	RecordPurchaseHandler          func(c *gin.Context)
+	RecordSaleHandler              func(c *gin.Context)
+	GetTransactionHandler          func(c *gin.Context)
+	UpdateTransactionStatusHandler func(c *gin.Context)
+	ConfirmHandoverHandler         func(c *gin.Context)
+
+	// Admin Handlers
+	GetSuspiciousVINClustersHandler func(c *gin.Context)
+	GetListingEventsHandler         func(c *gin.Context)
+	CompletePayoutHandler           func(c *gin.Context)
+	FailPayoutHandler               func(c *gin.Context)
 }
 
 func NewHandlerBundle(
@@ -1,12 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"carsawa/models"
 	"carsawa/services/dealer"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.uber.org/zap"
 )
 
@@ -43,16 +44,20 @@ func (h *DealerHandler) GetDealer(c *gin.Context) {
 }
 
 func (h *DealerHandler) UpdateDealer(c *gin.Context) {
+	// dealers update their own profile; admins name the dealer in the path
 	id := c.Param("id")
-	var updates bson.M
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if id == "" {
+		id = c.GetString("dealerID")
+	}
+	var patch models.DealerPatch
+	if err := bindPatch(c, &patch); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(err))
 		return
 	}
-	updated, err := h.service.UpdateDealer(c.Request.Context(), id, updates)
+	updated, err := h.service.UpdateDealer(c.Request.Context(), id, patch)
 	if err != nil {
 		h.logger.Error("update failed", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(dealerErrorStatus(err), errorBody(err))
 		return
 	}
 	c.JSON(http.StatusOK, updated)
@@ -69,6 +74,22 @@ func (h *DealerHandler) DeleteDealer(c *gin.Context) {
 }
 
 func (h *DealerHandler) ListDealers(c *gin.Context) {
+	near, err := resolveGeoQuery(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if near != nil {
+		dealers, err := h.service.ListDealersNear(c.Request.Context(), *near, parsePagination(c).Limit)
+		if err != nil {
+			h.logger.Error("list nearby failed", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, dealers)
+		return
+	}
+
 	dealers, err := h.service.ListDealers(c.Request.Context())
 	if err != nil {
 		h.logger.Error("list failed", zap.Error(err))
@@ -77,3 +98,17 @@ func (h *DealerHandler) ListDealers(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, dealers)
 }
+
+func dealerErrorStatus(err error) int {
+	if status, ok := patchErrorStatus(err); ok {
+		return status
+	}
+	switch {
+	case errors.Is(err, dealer.ErrDealerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, dealer.ErrNotDealerOwner):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
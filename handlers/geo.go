@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"carsawa/middleware"
+	"carsawa/models"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxSearchRadiusKm = 1000
+
+var errLocationRequired = errors.New("lat and lng are required for a nearby search")
+
+// resolveGeoQuery completes a nearby search from ?lat, ?lng and ?radiusKm,
+// which override near from the body. ?sort=distance asks for nearest first
+// without a radius. With no coordinates the centre is the caller's IP
+// location from GeolocationMiddleware. It returns nil when no geo search was
+// asked for.
+func resolveGeoQuery(c *gin.Context, near *models.GeoQuery) (*models.GeoQuery, error) {
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+	radius, radiusErr := strconv.ParseFloat(c.Query("radiusKm"), 64)
+	hasCoords := latErr == nil && lngErr == nil
+
+	if near == nil && !hasCoords && radiusErr != nil && c.Query("sort") != "distance" {
+		return nil, nil
+	}
+
+	var q models.GeoQuery
+	if near != nil {
+		q = *near
+	}
+	hasCentre := q.Lat != 0 || q.Lng != 0
+	if hasCoords {
+		q.Lat, q.Lng = lat, lng
+		hasCentre = true
+	}
+	if radiusErr == nil {
+		q.RadiusKm = radius
+	}
+	if !hasCentre {
+		geo, _ := c.Get("geoLocation")
+		loc, ok := geo.(*middleware.GeoLocation)
+		if !ok || (loc.Latitude == 0 && loc.Longitude == 0) {
+			return nil, errLocationRequired
+		}
+		q.Lat, q.Lng = loc.Latitude, loc.Longitude
+	}
+
+	if !q.Point().Valid() {
+		return nil, errors.New("lat must be within ±90 and lng within ±180")
+	}
+	if q.RadiusKm < 0 || q.RadiusKm > maxSearchRadiusKm {
+		return nil, fmt.Errorf("radiusKm must be between 0 and %d", maxSearchRadiusKm)
+	}
+	return &q, nil
+}
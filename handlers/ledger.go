@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	ledgerRepo "carsawa/database/repository/ledger"
+	"carsawa/services/ledger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type LedgerHandler struct {
+	service ledger.LedgerService
+	logger  *zap.Logger
+}
+
+func NewLedgerHandler(service ledger.LedgerService, logger *zap.Logger) *LedgerHandler {
+	return &LedgerHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetBalance returns what the dealer is owed, holds in escrow and has been paid.
+func (h *LedgerHandler) GetBalance(c *gin.Context) {
+	bal, err := h.service.GetDealerBalance(c.Request.Context(), c.GetString("dealerID"))
+	if err != nil {
+		h.logger.Error("Failed to get dealer balance", zap.Error(err))
+		c.JSON(ledgerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bal)
+}
+
+func (h *LedgerHandler) GetPayouts(c *gin.Context) {
+	list, err := h.service.GetDealerPayouts(c.Request.Context(), c.GetString("dealerID"), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list payouts", zap.Error(err))
+		c.JSON(ledgerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetStatement lists the journal entries behind the dealer's balance.
+func (h *LedgerHandler) GetStatement(c *gin.Context) {
+	entries, err := h.service.GetDealerStatement(c.Request.Context(), c.GetString("dealerID"), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to get dealer statement", zap.Error(err))
+		c.JSON(ledgerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// CompletePayout records, for an admin, that a scheduled payout's transfer
+// went through.
+func (h *LedgerHandler) CompletePayout(c *gin.Context) {
+	h.settlePayout(c, true)
+}
+
+// FailPayout records, for an admin, that a scheduled payout's transfer failed;
+// the amount goes back to the dealer's balance for the next batch.
+func (h *LedgerHandler) FailPayout(c *gin.Context) {
+	h.settlePayout(c, false)
+}
+
+func (h *LedgerHandler) settlePayout(c *gin.Context, paid bool) {
+	var req struct {
+		Reference string `json:"reference" binding:"required"` // Transfer reference, or why it failed
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout update"})
+		return
+	}
+
+	payout, err := h.service.CompletePayout(c.Request.Context(), c.Param("id"), paid, req.Reference)
+	if err != nil {
+		h.logger.Error("Failed to complete payout", zap.Error(err))
+		c.JSON(ledgerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, payout)
+}
+
+// ledgerErrorStatus maps ledger service errors onto HTTP status codes.
+func ledgerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ledgerRepo.ErrAccountNotFound), errors.Is(err, ledgerRepo.ErrPayoutNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ledgerRepo.ErrPayoutConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
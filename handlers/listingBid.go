@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
@@ -35,10 +36,18 @@ func (h *ListingHandler) AddBid(c *gin.Context) {
 		return
 	}
 
+	// the bidding dealer always comes from the authenticated session
+	dealerID, err := primitive.ObjectIDFromHex(c.GetString("dealerID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid dealer session"})
+		return
+	}
+	bid.DealerID = dealerID
+
 	listing, err := h.service.AddBid(c.Request.Context(), listingID, bid)
 	if err != nil {
 		h.logger.Error("Failed to add bid", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, listing)
@@ -47,12 +56,96 @@ func (h *ListingHandler) AddBid(c *gin.Context) {
 func (h *ListingHandler) AcceptBid(c *gin.Context) {
 	listingID := c.Param("id")
 	bidID := c.Param("bidID")
-	userID := c.Query("userID")
+	userID := c.GetString("userID")
 
 	listing, err := h.service.AcceptBid(c.Request.Context(), listingID, bidID, userID)
 	if err != nil {
 		h.logger.Error("Failed to accept bid", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listing)
+}
+
+type negotiationRequest struct {
+	Amount  float64 `json:"amount"`
+	Message string  `json:"message"`
+}
+
+// CounterBid lets the listing owner counter a dealer's bid.
+func (h *ListingHandler) CounterBid(c *gin.Context) {
+	var req negotiationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid counter offer"})
+		return
+	}
+
+	listing, err := h.service.CounterBid(c.Request.Context(), c.Param("id"), c.Param("bidID"), c.GetString("userID"), req.Amount, req.Message)
+	if err != nil {
+		h.logger.Error("Failed to counter bid", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listing)
+}
+
+// RejectBid lets the listing owner reject a dealer's bid.
+func (h *ListingHandler) RejectBid(c *gin.Context) {
+	var req negotiationRequest
+	_ = c.ShouldBindJSON(&req) // message is optional
+
+	listing, err := h.service.RejectBid(c.Request.Context(), c.Param("id"), c.Param("bidID"), c.GetString("userID"), req.Message)
+	if err != nil {
+		h.logger.Error("Failed to reject bid", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listing)
+}
+
+// DealerCounterBid lets the bidding dealer answer the owner's counter offer.
+func (h *ListingHandler) DealerCounterBid(c *gin.Context) {
+	var req negotiationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid counter offer"})
+		return
+	}
+
+	listing, err := h.service.DealerCounterBid(c.Request.Context(), c.Param("id"), c.Param("bidID"), c.GetString("dealerID"), req.Amount, req.Message)
+	if err != nil {
+		h.logger.Error("Failed to counter bid", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listing)
+}
+
+// UpdateBid lets the bidding dealer revise a bid still awaiting the owner.
+func (h *ListingHandler) UpdateBid(c *gin.Context) {
+	var req negotiationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bid data"})
+		return
+	}
+
+	listing, err := h.service.UpdateBid(c.Request.Context(), c.Param("id"), c.Param("bidID"), c.GetString("dealerID"), req.Amount, req.Message)
+	if err != nil {
+		h.logger.Error("Failed to update bid", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listing)
+}
+
+// WithdrawBid lets the bidding dealer withdraw from a negotiation.
+func (h *ListingHandler) WithdrawBid(c *gin.Context) {
+	var req negotiationRequest
+	_ = c.ShouldBindJSON(&req) // message is optional
+
+	listing, err := h.service.WithdrawBid(c.Request.Context(), c.Param("id"), c.Param("bidID"), c.GetString("dealerID"), req.Message)
+	if err != nil {
+		h.logger.Error("Failed to withdraw bid", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, listing)
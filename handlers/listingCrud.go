@@ -55,16 +55,16 @@ func (h *ListingHandler) GetListing(c *gin.Context) {
 
 func (h *ListingHandler) UpdateListing(c *gin.Context) {
 	id := c.Param("id")
-	var updates map[string]interface{}
-	if err := c.ShouldBindJSON(&updates); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid update data"})
+	var patch models.ListingPatch
+	if err := bindPatch(c, &patch); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(err))
 		return
 	}
 
-	listing, err := h.service.UpdateListing(c.Request.Context(), id, updates)
+	listing, err := h.service.UpdateListing(c.Request.Context(), id, patch)
 	if err != nil {
 		h.logger.Error("Update failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listingErrorStatus(err), errorBody(err))
 		return
 	}
 	c.JSON(http.StatusOK, listing)
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"carsawa/services/listing"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	maxPhotoSize   = 10 << 20 // 10 MB per photo
+	maxUploadBytes = 64 << 20
+)
+
+var allowedPhotoTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// mediaCaller resolves the authenticated owner from the dealer or user session.
+func mediaCaller(c *gin.Context) (ownerID string, isDealer bool) {
+	if id := c.GetString("dealerID"); id != "" {
+		return id, true
+	}
+	return c.GetString("userID"), false
+}
+
+// UploadListingMedia accepts one or more "photos" parts, with optional
+// matching "captions" fields, and adds them to the listing's gallery.
+func (h *ListingHandler) UploadListingMedia(c *gin.Context) {
+	ownerID, isDealer := mediaCaller(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBytes)
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart form"})
+		return
+	}
+	files := form.File["photos"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one photo is required"})
+		return
+	}
+	captions := form.Value["captions"]
+
+	uploads := make([]listing.MediaUpload, 0, len(files))
+	defer func() {
+		for _, u := range uploads {
+			os.Remove(u.LocalPath)
+		}
+	}()
+
+	for i, fh := range files {
+		if fh.Size > maxPhotoSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s exceeds the 10MB limit", fh.Filename)})
+			return
+		}
+		ext, ok := allowedPhotoTypes[fh.Header.Get("Content-Type")]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be a JPEG, PNG or WebP image", fh.Filename)})
+			return
+		}
+
+		dst := filepath.Join(os.TempDir(), primitive.NewObjectID().Hex()+ext)
+		if err := c.SaveUploadedFile(fh, dst); err != nil {
+			h.logger.Error("Failed to save uploaded photo", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read photo"})
+			return
+		}
+
+		u := listing.MediaUpload{LocalPath: dst}
+		if i < len(captions) {
+			u.Caption = captions[i]
+		}
+		uploads = append(uploads, u)
+	}
+
+	lst, err := h.service.UploadListingMedia(c.Request.Context(), c.Param("id"), ownerID, isDealer, uploads)
+	if err != nil {
+		h.logger.Error("Failed to upload listing photos", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, lst)
+}
+
+// ReorderListingMedia sets the gallery order from a full list of photo IDs.
+func (h *ListingHandler) ReorderListingMedia(c *gin.Context) {
+	var req struct {
+		Order []string `json:"order" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid photo order"})
+		return
+	}
+	ownerID, isDealer := mediaCaller(c)
+
+	lst, err := h.service.ReorderListingMedia(c.Request.Context(), c.Param("id"), ownerID, isDealer, req.Order)
+	if err != nil {
+		h.logger.Error("Failed to reorder listing photos", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lst)
+}
+
+// SetListingCover makes a photo the listing's cover image.
+func (h *ListingHandler) SetListingCover(c *gin.Context) {
+	ownerID, isDealer := mediaCaller(c)
+
+	lst, err := h.service.SetListingCover(c.Request.Context(), c.Param("id"), ownerID, isDealer, c.Param("mediaID"))
+	if err != nil {
+		h.logger.Error("Failed to set listing cover", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lst)
+}
+
+// UpdateMediaCaption changes a single photo's caption.
+func (h *ListingHandler) UpdateMediaCaption(c *gin.Context) {
+	var req struct {
+		Caption string `json:"caption"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid caption"})
+		return
+	}
+	ownerID, isDealer := mediaCaller(c)
+
+	lst, err := h.service.UpdateMediaCaption(c.Request.Context(), c.Param("id"), ownerID, isDealer, c.Param("mediaID"), req.Caption)
+	if err != nil {
+		h.logger.Error("Failed to update photo caption", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lst)
+}
+
+// DeleteListingMedia removes a photo from the gallery.
+func (h *ListingHandler) DeleteListingMedia(c *gin.Context) {
+	ownerID, isDealer := mediaCaller(c)
+
+	lst, err := h.service.DeleteListingMedia(c.Request.Context(), c.Param("id"), ownerID, isDealer, c.Param("mediaID"))
+	if err != nil {
+		h.logger.Error("Failed to delete listing photo", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, lst)
+}
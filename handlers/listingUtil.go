@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"carsawa/database/keyset"
+	listingRepo "carsawa/database/repository/listing"
 	"carsawa/models"
+	"carsawa/services/listing"
+	"carsawa/utils"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -16,7 +21,19 @@ func (h *ListingHandler) PublishListing(c *gin.Context) {
 	listing, err := h.service.PublishListing(c.Request.Context(), listingID, dealerID)
 	if err != nil {
 		h.logger.Error("Failed to publish listing", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listing)
+}
+
+// RenewListing gives one of the dealer's listings a fresh lifetime, putting
+// it back on the market if it has expired.
+func (h *ListingHandler) RenewListing(c *gin.Context) {
+	listing, err := h.service.RenewListing(c.Request.Context(), c.Param("id"), c.GetString("dealerID"))
+	if err != nil {
+		h.logger.Error("Failed to renew listing", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, listing)
@@ -30,7 +47,7 @@ func (h *ListingHandler) CloseListing(c *gin.Context) {
 	err := h.service.CloseListing(c.Request.Context(), listingID, ownerID, isDealer)
 	if err != nil {
 		h.logger.Error("Failed to close listing", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.Status(http.StatusOK)
@@ -74,36 +91,214 @@ func (h *ListingHandler) SearchListings(c *gin.Context) {
 func (h *ListingHandler) GetFeed(c *gin.Context) {
 	var filter models.ListingFilter
 
-	// Defaults
-	page := 1
-	limit := 20
-
-	// Parse query params for page and limit
-	if p := c.Query("page"); p != "" {
-		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
-			page = parsed
-		}
-	}
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
-			limit = parsed
-		}
+	// ?page and ?limit, or ?cursor from a previous page
+	pagination, err := parseCursorPagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	offset := (page - 1) * limit
-	pagination := models.Pagination{Limit: limit, Offset: offset}
-
 	// Bind filters from JSON body
 	if err := c.ShouldBindJSON(&filter); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter"})
 		return
 	}
+	near, err := resolveGeoQuery(c, filter.Near)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Near = near
 
 	resp, err := h.service.GetFeed(c.Request.Context(), filter, pagination)
 	if err != nil {
 		h.logger.Error("Failed to fetch feed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
+	resp.Page = utils.EncodePage(resp.Cursors)
 	c.JSON(http.StatusOK, resp)
 }
+
+// Search runs a free-text search over live listings, nearest first when a
+// location is given, with facet counts for narrowing it down. Facet values
+// are applied by passing them back as query parameters.
+func (h *ListingHandler) Search(c *gin.Context) {
+	filter := parseListingFilter(c)
+	near, err := resolveGeoQuery(c, nil)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Near = near
+	pagination, err := parseCursorPagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Search(c.Request.Context(), c.Query("q"), filter, pagination)
+	if err != nil {
+		h.logger.Error("Search error", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	result.Page = utils.EncodePage(result.Cursors)
+	c.JSON(http.StatusOK, result)
+}
+
+// SearchSuggestions completes a partly typed ?q as the buyer types, with
+// makes, models and dealers.
+func (h *ListingHandler) SearchSuggestions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	suggestions, err := h.service.GetSearchSuggestions(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		h.logger.Error("Suggestion error", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
+
+// RecordSearchClick is sent by clients when a buyer opens a listing from
+// search results, for click-through in the search trends.
+func (h *ListingHandler) RecordSearchClick(c *gin.Context) {
+	var req struct {
+		SearchID  string `json:"searchId" binding:"required"`
+		ListingID string `json:"listingId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.service.RecordSearchClick(c.Request.Context(), req.SearchID, req.ListingID); err != nil {
+		h.logger.Error("Failed to record search click", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSearchTrends shows what buyers searched for over the last week, in
+// ?city or everywhere, including searches nothing in stock matched.
+func (h *ListingHandler) GetSearchTrends(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	trends, err := h.service.GetSearchTrends(c.Request.Context(), c.Query("city"), limit)
+	if err != nil {
+		h.logger.Error("Failed to get search trends", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, trends)
+}
+
+// GetPriceHistory returns the asking prices a listing has had, oldest first.
+func (h *ListingHandler) GetPriceHistory(c *gin.Context) {
+	history, err := h.service.GetPriceHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.logger.Error("Failed to get price history", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// GetListingEvents returns a page of a listing's audit trail, newest first.
+func (h *ListingHandler) GetListingEvents(c *gin.Context) {
+	events, err := h.service.GetListingEvents(c.Request.Context(), c.Param("id"), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to get listing events", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// GetDealerListings pages through the signed-in dealer's inventory,
+// optionally narrowed to one ?status.
+func (h *ListingHandler) GetDealerListings(c *gin.Context) {
+	pagination, err := parseCursorPagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listings, cursors, err := h.service.GetDealerListings(c.Request.Context(), c.GetString("dealerID"),
+		models.ListingStatus(c.Query("status")), pagination)
+	if err != nil {
+		h.logger.Error("Failed to list dealer listings", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, struct {
+		Listings []models.Listing `json:"listings"`
+		models.Page
+	}{listings, utils.EncodePage(cursors)})
+}
+
+// parseListingFilter reads the facet filters of a search from the query
+// string. Numeric bounds that do not parse are ignored.
+func parseListingFilter(c *gin.Context) models.ListingFilter {
+	filter := models.ListingFilter{
+		Type:              models.ListingType(c.Query("type")),
+		Make:              c.Query("make"),
+		Model:             c.Query("model"),
+		FuelType:          models.FuelType(c.Query("fuelType")),
+		Transmission:      models.Transmission(c.Query("transmission")),
+		BodyType:          models.BodyType(c.Query("bodyType")),
+		DriveType:         models.DriveType(c.Query("driveType")),
+		Colour:            c.Query("colour"),
+		ConditionGrade:    models.ConditionGrade(c.Query("conditionGrade")),
+		RegistrationPlate: c.Query("registrationPlate"),
+		City:              c.Query("city"),
+	}
+	queryInt(c, "minYear", &filter.MinYear)
+	queryInt(c, "maxYear", &filter.MaxYear)
+	queryInt(c, "minMileage", &filter.MinMileage)
+	queryInt(c, "maxMileage", &filter.MaxMileage)
+	queryInt(c, "minEngineSize", &filter.MinEngineSize)
+	queryInt(c, "maxEngineSize", &filter.MaxEngineSize)
+	queryInt(c, "maxOwners", &filter.MaxOwners)
+	if p, err := strconv.ParseFloat(c.Query("minPrice"), 64); err == nil {
+		filter.MinPrice = p
+	}
+	if p, err := strconv.ParseFloat(c.Query("maxPrice"), 64); err == nil {
+		filter.MaxPrice = p
+	}
+	return filter
+}
+
+// queryInt sets *dst from the integer query parameter key, if it parses.
+func queryInt(c *gin.Context, key string, dst *int) {
+	if n, err := strconv.Atoi(c.Query(key)); err == nil {
+		*dst = n
+	}
+}
+
+// listingErrorStatus maps listing service errors onto HTTP status codes.
+func listingErrorStatus(err error) int {
+	if status, ok := patchErrorStatus(err); ok {
+		return status
+	}
+	switch {
+	case errors.Is(err, listingRepo.ErrNotFound), errors.Is(err, listing.ErrBidNotFound),
+		errors.Is(err, listing.ErrMediaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, listingRepo.ErrUnauthorizedAction), errors.Is(err, listingRepo.ErrTransitionForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, listingRepo.ErrBidConflict), errors.Is(err, listingRepo.ErrDuplicateBid),
+		errors.Is(err, listingRepo.ErrInvalidTransition),
+		errors.Is(err, listing.ErrInvalidBidTransition),
+		errors.Is(err, listing.ErrAuctionManaged), errors.Is(err, listing.ErrAuctionEnded),
+		errors.Is(err, listing.ErrAuctionNotStarted), errors.Is(err, listingRepo.ErrMediaLimit),
+		errors.Is(err, listingRepo.ErrMediaConflict):
+		return http.StatusConflict
+	case errors.Is(err, listing.ErrBidTooLow):
+		return http.StatusBadRequest
+	case errors.Is(err, listingRepo.ErrInvalidID), errors.Is(err, listingRepo.ErrInvalidType),
+		errors.Is(err, keyset.ErrCursorMismatch):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"carsawa/services/listing"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ModerationHandler struct {
+	service listing.ListingService
+	logger  *zap.Logger
+}
+
+func NewModerationHandler(service listing.ListingService, logger *zap.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetSuspiciousVINClusters lists VINs shared by several listings together
+// with the reasons each group looks like fraud.
+func (h *ModerationHandler) GetSuspiciousVINClusters(c *gin.Context) {
+	clusters, err := h.service.GetSuspiciousVINClusters(c.Request.Context(), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list VIN clusters", zap.Error(err))
+		c.JSON(listingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, clusters)
+}
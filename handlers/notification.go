@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"carsawa/database/keyset"
+	"carsawa/models"
+	"carsawa/services/notification"
+	"carsawa/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type NotificationHandler struct {
+	service notification.NotificationService
+	logger  *zap.Logger
+}
+
+func NewNotificationHandler(service notification.NotificationService, logger *zap.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetNotifications pages through the caller's inbox, newest first. The same
+// handler serves the dealer and user route groups.
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	pagination, err := parseCursorPagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var (
+		list    []models.Notification
+		cursors models.PageCursors
+	)
+	if dealerID := c.GetString("dealerID"); dealerID != "" {
+		list, cursors, err = h.service.GetDealerNotifications(c.Request.Context(), dealerID, pagination)
+	} else {
+		list, cursors, err = h.service.GetUserNotifications(c.Request.Context(), c.GetString("userID"), pagination)
+	}
+	if err != nil {
+		h.logger.Error("Failed to list notifications", zap.Error(err))
+		c.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, struct {
+		Notifications []models.Notification `json:"notifications"`
+		models.Page
+	}{list, utils.EncodePage(cursors)})
+}
+
+// MarkRead marks one of the caller's notifications read; anyone else's is
+// reported as not found.
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	var err error
+	if dealerID := c.GetString("dealerID"); dealerID != "" {
+		err = h.service.MarkDealerNotificationRead(c.Request.Context(), dealerID, c.Param("id"))
+	} else {
+		err = h.service.MarkUserNotificationRead(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	}
+	if err != nil {
+		h.logger.Error("Failed to mark notification read", zap.Error(err))
+		c.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	var err error
+	if dealerID := c.GetString("dealerID"); dealerID != "" {
+		err = h.service.MarkAllDealerNotificationsRead(c.Request.Context(), dealerID)
+	} else {
+		err = h.service.MarkAllUserNotificationsRead(c.Request.Context(), c.GetString("userID"))
+	}
+	if err != nil {
+		h.logger.Error("Failed to mark notifications read", zap.Error(err))
+		c.JSON(notificationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// notificationErrorStatus maps notification service errors onto HTTP status codes.
+func notificationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, notification.ErrNotificationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, notification.ErrInvalidRecipientID), errors.Is(err, keyset.ErrCursorMismatch):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
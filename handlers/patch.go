@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"carsawa/models"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindPatch decodes a JSON Merge Patch request body into v, rejecting
+// unknown fields and mistyped values by name.
+func bindPatch(c *gin.Context, v interface{}) error {
+	body, err := c.GetRawData()
+	if err != nil {
+		return err
+	}
+	return models.DecodePatch(body, v)
+}
+
+// patchErrorStatus maps the field errors a patch is rejected with. ok is
+// false for any other error.
+func patchErrorStatus(err error) (status int, ok bool) {
+	switch {
+	case errors.Is(err, models.ErrFieldForbidden):
+		return http.StatusForbidden, true
+	case errors.Is(err, models.ErrUnknownField), errors.Is(err, models.ErrInvalidField):
+		return http.StatusBadRequest, true
+	}
+	return 0, false
+}
+
+// errorBody is the JSON error response, naming the offending field when
+// there is one.
+func errorBody(err error) gin.H {
+	var fe *models.FieldError
+	if errors.As(err, &fe) && fe.Field != "" {
+		return gin.H{"error": err.Error(), "field": fe.Field}
+	}
+	return gin.H{"error": err.Error()}
+}
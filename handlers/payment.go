@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	paymentRepo "carsawa/database/repository/payment"
+	"carsawa/services/payments"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxCallbackBytes bounds gateway callback bodies; M-Pesa sends well under 2KB.
+const maxCallbackBytes = 64 << 10
+
+type PaymentHandler struct {
+	service payments.PaymentService
+	logger  *zap.Logger
+}
+
+func NewPaymentHandler(service payments.PaymentService, logger *zap.Logger) *PaymentHandler {
+	return &PaymentHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// InitiateDeposit sends an M-Pesa prompt to hold a car. Clients should send an
+// Idempotency-Key header so a retried request does not prompt twice.
+func (h *PaymentHandler) InitiateDeposit(c *gin.Context) {
+	var req payments.DepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deposit request"})
+		return
+	}
+
+	p, err := h.service.InitiateDeposit(c.Request.Context(), c.GetString("userID"), req, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		h.logger.Error("Failed to initiate deposit", zap.Error(err))
+		c.JSON(paymentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, p)
+}
+
+func (h *PaymentHandler) GetPayment(c *gin.Context) {
+	p, err := h.service.GetPayment(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		h.logger.Error("Failed to get payment", zap.Error(err))
+		c.JSON(paymentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+func (h *PaymentHandler) GetUserPayments(c *gin.Context) {
+	list, err := h.service.GetUserPayments(c.Request.Context(), c.GetString("userID"), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list payments", zap.Error(err))
+		c.JSON(paymentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// PaymentCallback receives the gateway's result on the signed URL handed out
+// with each STK push. M-Pesa retries anything but a 200, so results that are
+// valid but already applied are acknowledged too.
+func (h *PaymentHandler) PaymentCallback(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCallbackBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"ResultCode": 1, "ResultDesc": "Invalid body"})
+		return
+	}
+
+	err = h.service.HandleCallback(c.Request.Context(), c.Param("paymentID"), c.Query("signature"), body)
+	if err != nil {
+		h.logger.Warn("Rejected payment callback", zap.String("paymentID", c.Param("paymentID")), zap.Error(err))
+		c.JSON(paymentErrorStatus(err), gin.H{"ResultCode": 1, "ResultDesc": "Rejected"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ResultCode": 0, "ResultDesc": "Accepted"})
+}
+
+// paymentErrorStatus maps payment service errors onto HTTP status codes.
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, paymentRepo.ErrNotFound), errors.Is(err, listingRepo.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, payments.ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, listingRepo.ErrUnauthorizedAction):
+		return http.StatusForbidden
+	case errors.Is(err, payments.ErrNotReservable), errors.Is(err, payments.ErrCheckoutMismatch):
+		return http.StatusConflict
+	case errors.Is(err, payments.ErrGatewayFailed):
+		return http.StatusBadGateway
+	default:
+		return http.StatusBadRequest
+	}
+}
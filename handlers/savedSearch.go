@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	savedSearchRepo "carsawa/database/repository/savedsearch"
+	"carsawa/models"
+	"carsawa/services/savedsearch"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SavedSearchHandler struct {
+	service savedsearch.SavedSearchService
+	logger  *zap.Logger
+}
+
+func NewSavedSearchHandler(service savedsearch.SavedSearchService, logger *zap.Logger) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateSavedSearch saves a query and filter to be alerted about; frequency
+// defaults to instant.
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req models.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search"})
+		return
+	}
+
+	ss, err := h.service.CreateSavedSearch(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		h.logger.Error("Failed to save search", zap.Error(err))
+		c.JSON(savedSearchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, ss)
+}
+
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	searches, err := h.service.GetSavedSearches(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		h.logger.Error("Failed to list saved searches", zap.Error(err))
+		c.JSON(savedSearchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, searches)
+}
+
+// UpdateSavedSearch changes only the fields present in the body.
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	var req models.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search"})
+		return
+	}
+
+	ss, err := h.service.UpdateSavedSearch(c.Request.Context(), c.GetString("userID"), c.Param("id"), req)
+	if err != nil {
+		h.logger.Error("Failed to update saved search", zap.Error(err))
+		c.JSON(savedSearchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ss)
+}
+
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	if err := h.service.DeleteSavedSearch(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.logger.Error("Failed to delete saved search", zap.Error(err))
+		c.JSON(savedSearchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// savedSearchErrorStatus maps saved search service errors onto HTTP status codes.
+func savedSearchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, savedSearchRepo.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, savedSearchRepo.ErrInvalidID), errors.Is(err, savedsearch.ErrInvalidSavedSearch):
+		return http.StatusBadRequest
+	case errors.Is(err, savedsearch.ErrSavedSearchLimit):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
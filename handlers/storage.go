@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"carsawa/services/storage"
+	"carsawa/utils"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+type StorageHandler struct {
+	service storage.StorageService
+	logger  *zap.Logger
+}
+
+func NewStorageHandler(service storage.StorageService) *StorageHandler {
+	return &StorageHandler{
+		service: service,
+		logger:  utils.GetLogger(),
+	}
+}
+
+// UploadFile stores a single multipart "file" under the optional "folder" field.
+func (h *StorageHandler) UploadFile(c *gin.Context) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	if fh.Size > maxPhotoSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File exceeds the 10MB limit"})
+		return
+	}
+
+	tmp := filepath.Join(os.TempDir(), primitive.NewObjectID().Hex()+strings.ToLower(filepath.Ext(fh.Filename)))
+	if err := c.SaveUploadedFile(fh, tmp); err != nil {
+		h.logger.Error("Failed to save uploaded file", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer os.Remove(tmp)
+
+	publicID, err := h.service.UploadFile(c.Request.Context(), tmp, c.PostForm("folder"))
+	if err != nil {
+		h.logger.Error("Failed to upload file", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"publicId": publicID})
+}
+
+// GetDownloadURL returns a delivery URL; ?secure=true yields a signed, expiring one.
+func (h *StorageHandler) GetDownloadURL(c *gin.Context) {
+	publicID := c.Query("publicId")
+	if publicID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publicId is required"})
+		return
+	}
+	resourceType := c.DefaultQuery("resourceType", "image")
+
+	var expires time.Duration
+	if e := c.Query("expiresIn"); e != "" {
+		secs, err := strconv.Atoi(e)
+		if err != nil || secs <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expiresIn must be a positive number of seconds"})
+			return
+		}
+		expires = time.Duration(secs) * time.Second
+	}
+
+	var (
+		url string
+		err error
+	)
+	if c.Query("secure") == "true" {
+		url, err = h.service.GetSecureDownloadURL(c.Request.Context(), resourceType, publicID, expires)
+	} else {
+		url, err = h.service.GetDownloadURL(c.Request.Context(), resourceType, publicID, expires)
+	}
+	if err != nil {
+		h.logger.Error("Failed to build download URL", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+// ServePublicFile delivers a public object for the self-hosted backends.
+func (h *StorageHandler) ServePublicFile(c *gin.Context) {
+	// Check the canonical key so that "//private/..." and the like cannot
+	// reach a private object through the public route.
+	publicID, err := storage.CleanPublicID(strings.TrimPrefix(c.Param("publicID"), "/"))
+	if err != nil || storage.IsPrivate(publicID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+	h.serve(c, publicID)
+}
+
+// ServeSecureFile delivers any object once its signed URL has been verified.
+func (h *StorageHandler) ServeSecureFile(c *gin.Context) {
+	fs, ok := h.service.(storage.FileServer)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+
+	publicID := strings.TrimPrefix(c.Param("publicID"), "/")
+	if err := fs.VerifyURL(publicID, c.Query("expires"), c.Query("signature")); err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, storage.ErrURLExpired) {
+			status = http.StatusGone
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	h.serve(c, publicID)
+}
+
+func (h *StorageHandler) serve(c *gin.Context, publicID string) {
+	fs, ok := h.service.(storage.FileServer)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		return
+	}
+
+	body, contentType, err := fs.Open(c.Request.Context(), publicID)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrObjectNotFound), errors.Is(err, storage.ErrInvalidPublicID):
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+		default:
+			h.logger.Error("Failed to open file", zap.String("publicID", publicID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		}
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, -1, contentType, body, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	tradeInRepo "carsawa/database/repository/tradein"
+	"carsawa/models"
+	"carsawa/services/tradein"
+	"carsawa/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type TradeInHandler struct {
+	service tradein.TradeInService
+	logger  *zap.Logger
+}
+
+func NewTradeInHandler(service tradein.TradeInService, logger *zap.Logger) *TradeInHandler {
+	return &TradeInHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateTradeIn submits the authenticated user's car against a dealer listing.
+func (h *TradeInHandler) CreateTradeIn(c *gin.Context) {
+	var req tradein.CreateTradeInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trade-in data"})
+		return
+	}
+
+	t, err := h.service.CreateTradeIn(c.Request.Context(), c.GetString("userID"), req)
+	if err != nil {
+		h.logger.Error("Failed to create trade-in", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+func (h *TradeInHandler) GetUserTradeIns(c *gin.Context) {
+	tradeIns, err := h.service.GetUserTradeIns(c.Request.Context(), c.GetString("userID"), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list trade-ins", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tradeIns)
+}
+
+func (h *TradeInHandler) DeleteTradeIn(c *gin.Context) {
+	if err := h.service.DeleteTradeIn(c.Request.Context(), c.Param("id"), c.GetString("userID")); err != nil {
+		h.logger.Error("Failed to withdraw trade-in", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TradeInHandler) GetTradeInOffers(c *gin.Context) {
+	offers, err := h.service.GetTradeInOffers(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		h.logger.Error("Failed to get trade-in offers", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, offers)
+}
+
+// AcceptTradeInOffer applies a dealer's valuation as credit on the target listing.
+func (h *TradeInHandler) AcceptTradeInOffer(c *gin.Context) {
+	t, err := h.service.AcceptOffer(c.Request.Context(), c.Param("id"), c.Param("offerID"), c.GetString("userID"))
+	if err != nil {
+		h.logger.Error("Failed to accept trade-in offer", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// GetTradeInLeads lists trade-ins against the dealer's listings, filtered by
+// ?status, ?minYear and ?maxMileage.
+func (h *TradeInHandler) GetTradeInLeads(c *gin.Context) {
+	leads, err := h.service.GetDealerLeads(c.Request.Context(), c.GetString("dealerID"), parseTradeInFilter(c), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list trade-in leads", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, leads)
+}
+
+// MakeTradeInOffer lets the dealer value a lead.
+func (h *TradeInHandler) MakeTradeInOffer(c *gin.Context) {
+	var req struct {
+		Amount  float64 `json:"amount" binding:"required"`
+		Message string  `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offer"})
+		return
+	}
+
+	t, err := h.service.MakeOffer(c.Request.Context(), c.Param("id"), c.GetString("dealerID"), req.Amount, req.Message)
+	if err != nil {
+		h.logger.Error("Failed to make trade-in offer", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// ContactUser records the dealer's contact attempt and returns the user's details.
+func (h *TradeInHandler) ContactUser(c *gin.Context) {
+	var req struct {
+		Channel string `json:"channel" binding:"required"`
+		Message string `json:"message"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contact request"})
+		return
+	}
+
+	contact, err := h.service.ContactUser(c.Request.Context(), c.Param("id"), c.GetString("dealerID"), req.Channel, req.Message)
+	if err != nil {
+		h.logger.Error("Failed to contact trade-in user", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, contact)
+}
+
+func (h *TradeInHandler) GetPublicTradeIns(c *gin.Context) {
+	tradeIns, err := h.service.GetPublicTradeIns(c.Request.Context(), parseTradeInFilter(c), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list public trade-ins", zap.Error(err))
+		c.JSON(tradeInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tradeIns)
+}
+
+// parsePagination reads ?page and ?limit, defaulting to the first 20 results.
+func parsePagination(c *gin.Context) models.Pagination {
+	page, limit := 1, 20
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		page = p
+	}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	return models.Pagination{Limit: limit, Offset: (page - 1) * limit}
+}
+
+// parseCursorPagination is parsePagination plus ?cursor, a token from a
+// previous page's nextCursor or prevCursor. A cursor takes over from ?page.
+func parseCursorPagination(c *gin.Context) (models.Pagination, error) {
+	p := parsePagination(c)
+	if token := c.Query("cursor"); token != "" {
+		cur, err := utils.DecodeCursor(token)
+		if err != nil {
+			return p, err
+		}
+		p.Cursor, p.Offset = cur, 0
+	}
+	return p, nil
+}
+
+func parseTradeInFilter(c *gin.Context) models.TradeInFilter {
+	filter := models.TradeInFilter{Status: c.Query("status")}
+	if y, err := strconv.Atoi(c.Query("minYear")); err == nil {
+		filter.MinYear = y
+	}
+	if m, err := strconv.Atoi(c.Query("maxMileage")); err == nil {
+		filter.MaxMileage = m
+	}
+	return filter
+}
+
+// tradeInErrorStatus maps trade-in service errors onto HTTP status codes.
+func tradeInErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, tradeInRepo.ErrNotFound), errors.Is(err, listingRepo.ErrNotFound),
+		errors.Is(err, tradein.ErrOfferNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, listingRepo.ErrUnauthorizedAction):
+		return http.StatusForbidden
+	case errors.Is(err, tradeInRepo.ErrNotOpen), errors.Is(err, tradeInRepo.ErrOfferConflict),
+		errors.Is(err, tradein.ErrListingNotActive):
+		return http.StatusConflict
+	case errors.Is(err, tradein.ErrInvalidTradeIn), errors.Is(err, models.ErrInvalidField),
+		errors.Is(err, tradeInRepo.ErrInvalidID), errors.Is(err, listingRepo.ErrInvalidID):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	transactionRepo "carsawa/database/repository/transaction"
+	"carsawa/models"
+	"carsawa/services/transaction"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type TransactionHandler struct {
+	service transaction.TransactionService
+	logger  *zap.Logger
+}
+
+func NewTransactionHandler(service transaction.TransactionService, logger *zap.Logger) *TransactionHandler {
+	return &TransactionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// partyCaller resolves the authenticated dealer or user; the same handlers
+// serve both route groups.
+func partyCaller(c *gin.Context) (string, models.PartyType) {
+	if id := c.GetString("dealerID"); id != "" {
+		return id, models.PartyTypeDealer
+	}
+	return c.GetString("userID"), models.PartyTypeUser
+}
+
+// GetUserPurchases lists the cars the caller has bought, newest first.
+func (h *TransactionHandler) GetUserPurchases(c *gin.Context) {
+	callerID, _ := partyCaller(c)
+	txns, err := h.service.GetPurchases(c.Request.Context(), callerID, parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list purchases", zap.Error(err))
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, txns)
+}
+
+// GetUserSales lists the cars the caller has sold, newest first.
+func (h *TransactionHandler) GetUserSales(c *gin.Context) {
+	callerID, _ := partyCaller(c)
+	txns, err := h.service.GetSales(c.Request.Context(), callerID, parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to list sales", zap.Error(err))
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, txns)
+}
+
+// RecordPurchase asks a dealer to confirm the user bought one of their cars.
+func (h *TransactionHandler) RecordPurchase(c *gin.Context) {
+	var req struct {
+		ListingID string `json:"listingId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid purchase data"})
+		return
+	}
+
+	if err := h.service.RecordPurchase(c.Request.Context(), c.GetString("userID"), req.ListingID); err != nil {
+		h.logger.Error("Failed to record purchase", zap.Error(err))
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "The dealer has been asked to confirm your purchase"})
+}
+
+// RecordSale marks one of the dealer's listings sold.
+func (h *TransactionHandler) RecordSale(c *gin.Context) {
+	var req transaction.RecordSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sale data"})
+		return
+	}
+
+	tx, err := h.service.RecordSale(c.Request.Context(), c.GetString("dealerID"), req)
+	if err != nil {
+		h.logger.Error("Failed to record sale", zap.Error(err))
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, tx)
+}
+
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	callerID, callerType := partyCaller(c)
+	tx, err := h.service.GetTransaction(c.Request.Context(), c.Param("id"), callerID, callerType)
+	if err != nil {
+		h.logger.Error("Failed to get transaction", zap.Error(err))
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tx)
+}
+
+// UpdateTransactionStatus moves a transaction to the requested status.
+func (h *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
+	var req struct {
+		Status models.TransactionStatus `json:"status" binding:"required"`
+		Note   string                   `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status update"})
+		return
+	}
+
+	callerID, callerType := partyCaller(c)
+	tx, err := h.service.UpdateStatus(c.Request.Context(), c.Param("id"), callerID, callerType, req.Status, req.Note)
+	if err != nil {
+		h.logger.Error("Failed to update transaction status", zap.Error(err))
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tx)
+}
+
+// ConfirmHandover records the caller's side of the handover; the sale
+// completes and escrow is released once buyer and seller have both confirmed.
+func (h *TransactionHandler) ConfirmHandover(c *gin.Context) {
+	callerID, callerType := partyCaller(c)
+	tx, err := h.service.ConfirmHandover(c.Request.Context(), c.Param("id"), callerID, callerType)
+	if err != nil {
+		h.logger.Error("Failed to confirm handover", zap.Error(err))
+		c.JSON(transactionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tx)
+}
+
+// transactionErrorStatus maps transaction service errors onto HTTP status codes.
+func transactionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, transactionRepo.ErrNotFound), errors.Is(err, listingRepo.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, listingRepo.ErrUnauthorizedAction), errors.Is(err, listingRepo.ErrTransitionForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, transactionRepo.ErrDuplicateTransaction), errors.Is(err, transactionRepo.ErrStatusConflict),
+		errors.Is(err, listingRepo.ErrInvalidTransition),
+		errors.Is(err, transaction.ErrInvalidTransition), errors.Is(err, transaction.ErrNotForSale),
+		errors.Is(err, transaction.ErrBuyerConflict), errors.Is(err, transaction.ErrBuyerUnknown):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
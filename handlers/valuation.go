@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"carsawa/models"
+	"carsawa/services/valuation"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ValuationHandler struct {
+	service valuation.ValuationService
+	logger  *zap.Logger
+}
+
+func NewValuationHandler(service valuation.ValuationService, logger *zap.Logger) *ValuationHandler {
+	return &ValuationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetValuation estimates a fair price range from ?make, ?model, ?year and
+// the optional ?mileage in kilometres.
+func (h *ValuationHandler) GetValuation(c *gin.Context) {
+	var req models.ValuationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "make, model and year are required"})
+		return
+	}
+
+	v, err := h.service.Estimate(req)
+	if err != nil {
+		if !errors.Is(err, valuation.ErrNoMarketData) {
+			h.logger.Error("Failed to value car", zap.Error(err))
+		}
+		c.JSON(valuationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// valuationErrorStatus maps valuation service errors onto HTTP status codes.
+func valuationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, valuation.ErrInvalidValuation):
+		return http.StatusBadRequest
+	case errors.Is(err, valuation.ErrNoMarketData):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/services/watchlist"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type WatchlistHandler struct {
+	service watchlist.WatchlistService
+	logger  *zap.Logger
+}
+
+func NewWatchlistHandler(service watchlist.WatchlistService, logger *zap.Logger) *WatchlistHandler {
+	return &WatchlistHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Watch adds the listing in :id to the user's watchlist. Watching a listing
+// twice is not an error.
+func (h *WatchlistHandler) Watch(c *gin.Context) {
+	if err := h.service.Watch(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.logger.Error("Failed to watch listing", zap.Error(err))
+		c.JSON(watchlistErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WatchlistHandler) Unwatch(c *gin.Context) {
+	if err := h.service.Unwatch(c.Request.Context(), c.GetString("userID"), c.Param("id")); err != nil {
+		h.logger.Error("Failed to unwatch listing", zap.Error(err))
+		c.JSON(watchlistErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	watched, err := h.service.GetWatchlist(c.Request.Context(), c.GetString("userID"), parsePagination(c))
+	if err != nil {
+		h.logger.Error("Failed to get watchlist", zap.Error(err))
+		c.JSON(watchlistErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, watched)
+}
+
+// GetListingInterest returns how many users watch each of the dealer's
+// listings that are still for sale.
+func (h *WatchlistHandler) GetListingInterest(c *gin.Context) {
+	interest, err := h.service.GetDealerInterest(c.Request.Context(), c.GetString("dealerID"))
+	if err != nil {
+		h.logger.Error("Failed to get listing interest", zap.Error(err))
+		c.JSON(watchlistErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, interest)
+}
+
+// watchlistErrorStatus maps watchlist service errors onto HTTP status codes.
+func watchlistErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, listingRepo.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, listingRepo.ErrInvalidID):
+		return http.StatusBadRequest
+	case errors.Is(err, watchlist.ErrNotWatchable):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
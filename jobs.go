@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"carsawa/config"
+	"carsawa/services/autocomplete"
+	"carsawa/services/ledger"
+	"carsawa/services/listing"
+	"carsawa/services/payments"
+	"carsawa/services/savedsearch"
+	"carsawa/services/valuation"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// jobServices are the services the background jobs run against.
+type jobServices struct {
+	Listing   listing.ListingService
+	Payments  payments.PaymentService
+	Ledger    ledger.LedgerService
+	Suggest   autocomplete.AutocompleteService
+	Alerts    savedsearch.SavedSearchService
+	Valuation valuation.ValuationService
+}
+
+// startJobs starts the background jobs until ctx is cancelled. Every replica
+// starts them; work on shared data takes a Redis lock, so only one replica
+// does it.
+func startJobs(ctx context.Context, rdb *redis.Client, svc jobServices) {
+	cfg := config.AppConfig
+	go listing.RunAuctionScheduler(ctx, svc.Listing, rdb, time.Duration(cfg.AuctionSchedulerMins)*time.Minute)
+	go listing.RunLifecycleJobs(ctx, svc.Listing, rdb, time.Duration(cfg.ListingLifecycleMins)*time.Minute)
+	go payments.RunReconciler(ctx, svc.Payments, rdb, time.Duration(cfg.PaymentReconcileSecs)*time.Second)
+	go ledger.RunPayoutScheduler(ctx, svc.Ledger, rdb, time.Duration(cfg.PayoutIntervalHours)*time.Hour)
+	go autocomplete.RunRefresher(ctx, svc.Suggest, rdb, time.Duration(cfg.AutocompleteRefreshMins)*time.Minute)
+	go listing.RunSearchTrendsJob(ctx, svc.Listing, rdb, time.Duration(cfg.SearchTrendsIntervalMins)*time.Minute)
+	go savedsearch.RunDigestScheduler(ctx, svc.Alerts, rdb, time.Duration(cfg.SavedSearchDigestMins)*time.Minute)
+	go valuation.RunRefresher(ctx, svc.Valuation, time.Duration(cfg.ValuationRefreshMins)*time.Minute)
+}
@@ -27,11 +27,17 @@ func main() {
 	database.InitDB()
 	utils.InitRedis()
 
-	storageService, err := utils.Cloudinary()
+	storageService, err := utils.Storage()
 	if err != nil {
 		logger.Sugar().Fatalf("failed to init storage: %v", err)
 	}
 
+	// The listing service checks VINs through the registries and caches.
+	vinDecoder, err := newVINDecoder(database.MongoClient.Database("carsawa"), utils.GetCacheClient())
+	if err != nil {
+		logger.Sugar().Fatalf("failed to init VIN decoder: %v", err)
+	}
+
 	router := gin.New()
 	router.Use(
 		gin.Recovery(),
@@ -46,6 +52,11 @@ func main() {
 	userSvc := user.NewUserService(userRepo, jwtProvider, emailSvc)
 	dealerSvc := dealer.NewDealerService(dealerRepo, jwtProvider, emailSvc)
 
+	jobSvc, err := newJobServices(database.MongoClient.Database("carsawa"), userSvc, dealerSvc, storageService, vinDecoder)
+	if err != nil {
+		logger.Sugar().Fatalf("failed to init services: %v", err)
+	}
+
 	userRepo := user.NewMongoUserRepo()
 	dealerRepo := dealer.NewMongoDealerRepo()
 	carRepo := car.NewMongoCarRepo()
@@ -107,8 +118,10 @@ func main() {
 		MarkAllNotificationsReadHandler:   userHandler.MarkAllNotificationsRead,
 		GetUnreadNotificationCountHandler: userHandler.GetUnreadNotificationCount,
 
-		UploadFileHandler:     storageHandler.UploadFile,
-		GetDownloadURLHandler: storageHandler.GetDownloadURL,
+		UploadFileHandler:      storageHandler.UploadFile,
+		GetDownloadURLHandler:  storageHandler.GetDownloadURL,
+		ServePublicFileHandler: storageHandler.ServePublicFile,
+		ServeSecureFileHandler: storageHandler.ServeSecureFile,
 	}
 
 	routes.RegisterRoutes(router, hb)
@@ -123,6 +136,9 @@ func main() {
 		Handler: router,
 	}
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	startJobs(jobsCtx, utils.GetCacheClient(), jobSvc)
+
 	logger.Sugar().Infof("Server starting on %s...", srv.Addr)
 
 	go func() {
@@ -134,6 +150,7 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminKeyMiddleware admits requests carrying key in the X-Admin-Key header.
+// With no key configured the admin API stays closed.
+func AdminKeyMiddleware(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Admin API is disabled",
+				"code":  0,
+			})
+			return
+		}
+		given := c.GetHeader("X-Admin-Key")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid admin key",
+				"code":  0,
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"carsawa/models"
+	"carsawa/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditActorMiddleware tags the request context with the caller and route
+// for the listing audit trail. It must run after the group's auth middleware
+// so the caller's ID is already set.
+func AuditActorMiddleware(actorType models.ActorType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := models.AuditActor{
+			Type:   actorType,
+			Source: c.Request.Method + " " + c.FullPath(),
+		}
+		switch actorType {
+		case models.ActorDealer:
+			actor.ID = c.GetString("dealerID")
+		case models.ActorUser:
+			actor.ID = c.GetString("userID")
+		}
+		c.Request = c.Request.WithContext(utils.WithAuditActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
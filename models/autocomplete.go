@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Autocomplete term kinds, also used as SearchSuggestion.Type.
+const (
+	SuggestionMake   = "make"
+	SuggestionModel  = "model"
+	SuggestionDealer = "dealer"
+)
+
+// AutocompleteTerm is one entry in the autocomplete index. Listings counts
+// the live listings behind a make or model; Searches counts the searches
+// that named the term.
+type AutocompleteTerm struct {
+	ID        string    `bson:"_id" json:"id"` // kind:key, see autocompleteRepo.TermID
+	Kind      string    `bson:"kind" json:"kind"`
+	Text      string    `bson:"text" json:"text"`
+	Make      string    `bson:"make,omitempty" json:"make,omitempty"`
+	Slug      string    `bson:"slug,omitempty" json:"slug,omitempty"`
+	Listings  int       `bson:"listings" json:"listings"`
+	Searches  int       `bson:"searches" json:"searches"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
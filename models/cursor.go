@@ -0,0 +1,26 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Cursor is a keyset position in a sorted result set: the sort key and _id
+// of the item at the edge of a page the client has seen. It travels to the
+// client as a signed, opaque token.
+type Cursor struct {
+	Sort   string             `bson:"s"`           // Ordering the key belongs to, e.g. "createdAt"
+	Key    interface{}        `bson:"k,omitempty"` // Sort key of the edge item
+	ID     primitive.ObjectID `bson:"i"`
+	Before bool               `bson:"b,omitempty"` // Page backwards from the position
+}
+
+// PageCursors locate the pages either side of a result page; nil when there
+// is no such page.
+type PageCursors struct {
+	Next *Cursor
+	Prev *Cursor
+}
+
+// Page carries the tokens for the neighbouring pages in a response.
+type Page struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
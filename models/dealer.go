@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 )
 
@@ -15,6 +16,46 @@ type Dealer struct {
 	Devices      []Device      `bson:"devices" json:"devices"`
 }
 
+// DealerPatch is the body of a dealer update, applied as a JSON Merge Patch:
+// absent fields are left alone and null clears an optional one. The email
+// and slug are identities and are not patchable.
+type DealerPatch struct {
+	Profile      *DealerProfilePatch `json:"profile"`
+	Store        *StorePatch         `json:"store"`
+	Verification *VerificationPatch  `json:"verification"`
+}
+
+type DealerProfilePatch struct {
+	DealerName Patch[string]   `json:"dealerName"`
+	Contact    *ContactPatch   `json:"contact"`
+	Location   Patch[Location] `json:"location"`
+}
+
+type ContactPatch struct {
+	Phone    Patch[string] `json:"phone"`
+	WhatsApp Patch[string] `json:"whatsapp"`
+}
+
+type StorePatch struct {
+	Banners        Patch[[]Banner]         `json:"banners"`
+	ServiceCatalog Patch[ServiceCatalogue] `json:"serviceCatalog"`
+	Promotions     Patch[[]Promotion]      `json:"promotions"`
+	Branding       *StoreBrandingPatch     `json:"branding"`
+}
+
+type StoreBrandingPatch struct {
+	PrimaryColor   Patch[string] `json:"primaryColor"`
+	SecondaryColor Patch[string] `json:"secondaryColor"`
+	FontFamily     Patch[string] `json:"fontFamily"`
+}
+
+// VerificationPatch is the KYC outcome, which only admins record.
+type VerificationPatch struct {
+	Level     Patch[string]   `json:"level"`
+	Status    Patch[string]   `json:"status"`
+	Documents Patch[[]string] `json:"documents"`
+}
+
 type Store struct {
 	Banners        []Banner         `bson:"banners" json:"banners"`
 	ServiceCatalog ServiceCatalogue `bson:"serviceCatalog" json:"serviceCatalog"`
@@ -89,7 +130,7 @@ type Verification struct {
 type Location struct {
 	Address  string   `bson:"address" json:"address" binding:"required"`
 	City     string   `bson:"city" json:"city" binding:"required"`
-	GeoPoint GeoPoint `bson:"geoPoint" json:"geoPoint"` // For location-based feeds
+	GeoPoint GeoPoint `bson:"geoPoint,omitempty" json:"geoPoint"` // For location-based feeds; omitted when unset
 }
 
 // GeoPoint for MongoDB geospatial queries
@@ -98,6 +139,51 @@ type GeoPoint struct {
 	Coordinates []float64 `bson:"coordinates" json:"coordinates"` // [longitude, latitude]
 }
 
+// NewGeoPoint builds a GeoJSON point from a latitude and longitude.
+func NewGeoPoint(lat, lng float64) GeoPoint {
+	return GeoPoint{Type: "Point", Coordinates: []float64{lng, lat}}
+}
+
+// IsZero reports a point with no coordinates; the BSON encoder uses it to
+// honour omitempty.
+func (g GeoPoint) IsZero() bool {
+	return len(g.Coordinates) == 0
+}
+
+// Valid reports whether the point is a longitude/latitude pair in range.
+func (g GeoPoint) Valid() bool {
+	return len(g.Coordinates) == 2 &&
+		g.Coordinates[0] >= -180 && g.Coordinates[0] <= 180 &&
+		g.Coordinates[1] >= -90 && g.Coordinates[1] <= 90
+}
+
+// GeoQuery centres a search on a point. Results come back nearest first,
+// within RadiusKm when it is set.
+type GeoQuery struct {
+	Lat      float64 `json:"lat"`
+	Lng      float64 `json:"lng"`
+	RadiusKm float64 `json:"radiusKm,omitempty"`
+}
+
+// Point is the query centre as a GeoJSON point.
+func (q GeoQuery) Point() GeoPoint {
+	return NewGeoPoint(q.Lat, q.Lng)
+}
+
+// EarthRadiusKm is the equatorial radius Mongo's spherical queries use.
+const EarthRadiusKm = 6378.1
+
+// DistanceKm is the great-circle distance from the query centre to p.
+func (q GeoQuery) DistanceKm(p GeoPoint) float64 {
+	const rad = math.Pi / 180
+	lat, lng := p.Coordinates[1], p.Coordinates[0]
+	dLat := (lat - q.Lat) * rad
+	dLng := (lng - q.Lng) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(q.Lat*rad)*math.Cos(lat*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
 // Contact information
 type Contact struct {
 	Email    string `bson:"email" json:"email" binding:"required,email"`
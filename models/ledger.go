@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Ledger amounts are integer minor units (cents) so postings always balance exactly.
+
+type LedgerAccountType string
+
+const (
+	LedgerAccountBuyer         LedgerAccountType = "buyer"          // Money a buyer has paid in; a credit balance is owed back
+	LedgerAccountEscrow        LedgerAccountType = "escrow"         // Held against one transaction until handover
+	LedgerAccountPlatformFees  LedgerAccountType = "platform_fees"  // Platform revenue
+	LedgerAccountDealerPayable LedgerAccountType = "dealer_payable" // Owed to a dealer, paid out in batches
+	LedgerAccountGateway       LedgerAccountType = "gateway"        // Money held at M-Pesa; the platform's cash
+)
+
+// PlatformFeesAccount and GatewayAccount are the platform's singleton accounts.
+const (
+	PlatformFeesAccount = string(LedgerAccountPlatformFees)
+	GatewayAccount      = string(LedgerAccountGateway)
+)
+
+// LedgerAccountID names the account of the given type owned by owner, e.g.
+// "dealer_payable:64f0…".
+func LedgerAccountID(t LedgerAccountType, owner primitive.ObjectID) string {
+	return string(t) + ":" + owner.Hex()
+}
+
+// LedgerAccount holds a running balance, debits positive and credits negative.
+// Accounts are created on their first posting.
+type LedgerAccount struct {
+	ID        string              `bson:"_id" json:"id"`
+	Type      LedgerAccountType   `bson:"type" json:"type"`
+	OwnerID   *primitive.ObjectID `bson:"ownerId,omitempty" json:"ownerId,omitempty"`   // User, dealer or transaction
+	DealerID  *primitive.ObjectID `bson:"dealerId,omitempty" json:"dealerId,omitempty"` // Beneficiary of escrow and payable accounts
+	Balance   int64               `bson:"balance" json:"balance"`
+	Currency  string              `bson:"currency" json:"currency"`
+	CreatedAt time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+type JournalKind string
+
+const (
+	JournalFundsReceived JournalKind = "funds_received" // Gateway → buyer
+	JournalEscrowHold    JournalKind = "escrow_hold"    // Buyer → escrow
+	JournalEscrowRelease JournalKind = "escrow_release" // Escrow → platform fees + dealer payable
+	JournalEscrowRefund  JournalKind = "escrow_refund"  // Escrow → buyer
+	JournalPayout        JournalKind = "payout"         // Dealer payable → gateway
+	JournalPayoutReverse JournalKind = "payout_reverse" // Failed payout back to dealer payable
+)
+
+// JournalEntry is an immutable, balanced set of postings. Reference is unique,
+// so replaying the same business event never posts twice.
+type JournalEntry struct {
+	ID            primitive.ObjectID  `bson:"_id" json:"id"`
+	Kind          JournalKind         `bson:"kind" json:"kind"`
+	Reference     string              `bson:"reference" json:"reference"`
+	TransactionID *primitive.ObjectID `bson:"transactionId,omitempty" json:"transactionId,omitempty"`
+	PaymentID     *primitive.ObjectID `bson:"paymentId,omitempty" json:"paymentId,omitempty"`
+	PayoutID      *primitive.ObjectID `bson:"payoutId,omitempty" json:"payoutId,omitempty"`
+	DealerID      *primitive.ObjectID `bson:"dealerId,omitempty" json:"dealerId,omitempty"`
+	Lines         []JournalLine       `bson:"lines" json:"lines"`
+	Currency      string              `bson:"currency" json:"currency"`
+	Memo          string              `bson:"memo,omitempty" json:"memo,omitempty"`
+	CreatedAt     time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+type JournalLine struct {
+	Account string `bson:"account" json:"account"`
+	Debit   int64  `bson:"debit,omitempty" json:"debit,omitempty"`
+	Credit  int64  `bson:"credit,omitempty" json:"credit,omitempty"`
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusScheduled PayoutStatus = "scheduled" // Debited from the dealer's payable, awaiting transfer
+	PayoutStatusPaid      PayoutStatus = "paid"
+	PayoutStatusFailed    PayoutStatus = "failed" // Reversed back to the dealer's payable
+)
+
+// Payout is one transfer of a dealer's payable balance.
+type Payout struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	DealerID    primitive.ObjectID `bson:"dealerId" json:"dealerId"`
+	BatchID     string             `bson:"batchId" json:"batchId"`
+	Amount      float64            `bson:"amount" json:"amount"`
+	Currency    string             `bson:"currency" json:"currency"`
+	Status      PayoutStatus       `bson:"status" json:"status"`
+	Reference   string             `bson:"reference,omitempty" json:"reference,omitempty"` // Bank or M-Pesa reference once sent
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	CompletedAt *time.Time         `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
+
+// DealerBalance summarises what the platform holds for a dealer.
+type DealerBalance struct {
+	DealerID  string  `json:"dealerId"`
+	Currency  string  `json:"currency"`
+	Available float64 `json:"available"` // Released and waiting for the next payout
+	InEscrow  float64 `json:"inEscrow"`  // Deposits on sales not yet handed over
+	PaidOut   float64 `json:"paidOut"`
+}
@@ -21,33 +21,107 @@ const (
 	ListingStatusActive   ListingStatus = "active"
 	ListingStatusOpen     ListingStatus = "open"
 	ListingStatusAccepted ListingStatus = "accepted"
+	ListingStatusReserved ListingStatus = "reserved" // Held for a buyer whose deposit has cleared
 	ListingStatusClosed   ListingStatus = "closed"
 	ListingStatusSold     ListingStatus = "sold"
+	ListingStatusExpired  ListingStatus = "expired" // Dealer listing past its lifetime; the dealer may renew it
 )
 
+// LiveListingStatuses are the statuses in which a listing is on the market
+// and holds its VIN; only one listing per VIN should be live at a time.
+var LiveListingStatuses = []ListingStatus{
+	ListingStatusActive,
+	ListingStatusOpen,
+	ListingStatusAccepted,
+	ListingStatusReserved,
+}
+
 type Listing struct {
 	ID            primitive.ObjectID `bson:"_id" json:"id"`
 	Type          ListingType        `bson:"type" json:"type"`
 	CarDetails    CarDetails         `bson:"carDetails" json:"carDetails" binding:"required"`
 	Status        ListingStatus      `bson:"status" json:"status"`
 	Views         int64              `bson:"views" json:"views"`
+	Watchers      int                `bson:"watchers,omitempty" json:"watchers"` // Users with the listing in their watchlist
 	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
 	UserListing   UserListing        `bson:"userListing" json:"userListing,omitzero"`
 	DealerListing DealerListing      `bson:"dealerListing" json:"dealerListing,omitzero"`
+	Media         []ListingMedia     `bson:"media,omitempty" json:"media,omitempty"`
+	CoverThumbURL string             `bson:"coverThumbUrl,omitempty" json:"coverThumbUrl,omitempty"` // Denormalised for feed and search
+	MediaVersion  int                `bson:"mediaVersion,omitempty" json:"-"`                        // Bumped on every gallery write; see SetMedia
+	VINClaim      string             `bson:"vinClaim,omitempty" json:"-"`                            // Set on the one live listing that holds its VIN
+	Moderation    *ListingModeration `bson:"moderation,omitempty" json:"moderation,omitempty"`
+	DistanceKm    *float64           `bson:"distanceKm,omitempty" json:"distanceKm,omitempty"` // Set only on results of a geo search
+	SearchScore   float64            `bson:"score,omitempty" json:"-"`                         // Text relevance, kept for search cursors
+	Market        *MarketBadge       `bson:"-" json:"market,omitempty"`                        // Price vs market, set on feed and search results
+	ExpiresAt     *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`   // End of the listing's lifetime on the market
+	RemindedAt    *time.Time         `bson:"expiryRemindedAt,omitempty" json:"-"`              // When the owner was told expiry is near
+}
+
+// ListingMedia is one photo in a listing's gallery, stored through the StorageService.
+type ListingMedia struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	PublicID  string             `bson:"publicId" json:"-"` // Storage identifier used for deletion
+	URL       string             `bson:"url" json:"url"`
+	ThumbURL  string             `bson:"thumbUrl" json:"thumbUrl"`
+	Caption   string             `bson:"caption,omitempty" json:"caption,omitempty"`
+	Position  int                `bson:"position" json:"position"`
+	IsCover   bool               `bson:"isCover" json:"isCover"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
 }
 
 type CarDetails struct {
-	VIN   string  `bson:"vin" json:"vin"`
-	Make  string  `bson:"make" json:"make"`
-	Model string  `bson:"model" json:"model"`
-	Year  int     `bson:"year" json:"year"`
-	Price float64 `bson:"price,omitempty" json:"price,omitempty"`
+	VIN               string         `bson:"vin" json:"vin"`
+	Make              string         `bson:"make" json:"make"`
+	Model             string         `bson:"model" json:"model"`
+	Year              int            `bson:"year" json:"year"`
+	Price             float64        `bson:"price,omitempty" json:"price,omitempty"`
+	Mileage           int            `bson:"mileage" json:"mileage"` // Kilometres
+	FuelType          FuelType       `bson:"fuelType,omitempty" json:"fuelType,omitempty"`
+	Transmission      Transmission   `bson:"transmission,omitempty" json:"transmission,omitempty"`
+	BodyType          BodyType       `bson:"bodyType,omitempty" json:"bodyType,omitempty"`
+	EngineSize        int            `bson:"engineSize,omitempty" json:"engineSize,omitempty"` // Displacement in cc
+	DriveType         DriveType      `bson:"driveType,omitempty" json:"driveType,omitempty"`
+	Colour            string         `bson:"colour,omitempty" json:"colour,omitempty"`
+	ConditionGrade    ConditionGrade `bson:"conditionGrade,omitempty" json:"conditionGrade,omitempty"`
+	NumberOfOwners    int            `bson:"numberOfOwners,omitempty" json:"numberOfOwners,omitempty"`
+	RegistrationPlate string         `bson:"registrationPlate,omitempty" json:"registrationPlate,omitempty"` // Empty for unregistered imports
+	Location          *Location      `bson:"location,omitempty" json:"location,omitempty"`
+}
+
+// ListingPatch is the body of a listing update, applied as a JSON Merge
+// Patch: absent fields are left alone and null clears an optional one.
+type ListingPatch struct {
+	CarDetails *CarDetailsPatch     `json:"carDetails"`
+	Status     Patch[ListingStatus] `json:"status"`
+}
+
+// CarDetailsPatch holds the car details a listing update may change. The
+// VIN is fixed once listed, since it holds the listing's VIN claim.
+type CarDetailsPatch struct {
+	Make              Patch[string]         `json:"make"`
+	Model             Patch[string]         `json:"model"`
+	Year              Patch[int]            `json:"year"`
+	Price             Patch[float64]        `json:"price"`
+	Mileage           Patch[int]            `json:"mileage"`
+	FuelType          Patch[FuelType]       `json:"fuelType"`
+	Transmission      Patch[Transmission]   `json:"transmission"`
+	BodyType          Patch[BodyType]       `json:"bodyType"`
+	EngineSize        Patch[int]            `json:"engineSize"`
+	DriveType         Patch[DriveType]      `json:"driveType"`
+	Colour            Patch[string]         `json:"colour"`
+	ConditionGrade    Patch[ConditionGrade] `json:"conditionGrade"`
+	NumberOfOwners    Patch[int]            `json:"numberOfOwners"`
+	RegistrationPlate Patch[string]         `json:"registrationPlate"`
+	Location          Patch[Location]       `json:"location"`
 }
 
 type DealerListing struct {
-	DealerID  primitive.ObjectID `bson:"dealerId,omitempty" json:"dealerId,omitempty"`
-	Interests []InterestedUser   `bson:"interestedUser" json:"interestedUser,omitzero"`
+	DealerID primitive.ObjectID `bson:"dealerId,omitempty" json:"dealerId,omitempty"`
+	// Interests predates the watchlist and is no longer written; see
+	// Listing.Watchers and models.WatchlistEntry.
+	Interests []InterestedUser `bson:"interestedUser" json:"interestedUser,omitzero"`
 }
 
 type InterestedUser struct {
@@ -59,30 +133,108 @@ type UserListing struct {
 	UserID      primitive.ObjectID  `bson:"userId,omitempty" json:"userId,omitempty"`
 	Bids        []Bid               `bson:"bids,omitempty" json:"bids,omitempty"`
 	AcceptedBid *primitive.ObjectID `bson:"acceptedBid,omitempty" json:"acceptedBid,omitempty"`
+	Auction     *Auction            `bson:"auction,omitempty" json:"auction,omitempty"` // nil for open-ended bidding
+}
+
+type AuctionStatus string
+
+const (
+	AuctionStatusScheduled AuctionStatus = "scheduled"
+	AuctionStatusLive      AuctionStatus = "live"
+	AuctionStatusSold      AuctionStatus = "sold"
+	AuctionStatusNoSale    AuctionStatus = "no_sale"
+)
+
+// Auction turns a user bid listing into a timed auction that is closed by the scheduler.
+type Auction struct {
+	StartAt      time.Time           `bson:"startAt" json:"startAt"`
+	EndAt        time.Time           `bson:"endAt" json:"endAt"`
+	ReservePrice float64             `bson:"reservePrice,omitempty" json:"reservePrice,omitempty"` // Minimum winning offer
+	MinIncrement float64             `bson:"minIncrement,omitempty" json:"minIncrement,omitempty"` // Required step over the highest bid
+	Status       AuctionStatus       `bson:"status" json:"status"`
+	WinningBid   *primitive.ObjectID `bson:"winningBid,omitempty" json:"winningBid,omitempty"`
+	ClosedAt     *time.Time          `bson:"closedAt,omitempty" json:"closedAt,omitempty"`
 }
 
 type Bid struct {
-	ID        primitive.ObjectID `bson:"_id" json:"id"`
-	DealerID  primitive.ObjectID `bson:"dealerId" json:"dealerId"`
-	Offer     float64            `bson:"offer" json:"offer"`
-	Message   string             `bson:"message" json:"message"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	DealerID     primitive.ObjectID `bson:"dealerId" json:"dealerId"`
+	Offer        float64            `bson:"offer" json:"offer"`                                   // Dealer's latest offer
+	CounterOffer float64            `bson:"counterOffer,omitempty" json:"counterOffer,omitempty"` // Owner's latest counter
+	Message      string             `bson:"message" json:"message"`
+	Status       BidStatus          `bson:"status" json:"status"`
+	Version      int                `bson:"version" json:"version"` // Incremented on every negotiation round
+	History      []BidRound         `bson:"history,omitempty" json:"history,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+type BidStatus string
+
+const (
+	BidStatusPending   BidStatus = "pending"   // Awaiting the listing owner
+	BidStatusCountered BidStatus = "countered" // Awaiting the dealer
+	BidStatusWithdrawn BidStatus = "withdrawn"
+	BidStatusRejected  BidStatus = "rejected"
+	BidStatusAccepted  BidStatus = "accepted"
+)
+
+type BidActor string
+
+const (
+	BidActorOwner  BidActor = "owner"
+	BidActorDealer BidActor = "dealer"
+)
+
+type BidAction string
+
+const (
+	BidActionOffer    BidAction = "offer"
+	BidActionCounter  BidAction = "counter"
+	BidActionUpdate   BidAction = "update"
+	BidActionWithdraw BidAction = "withdraw"
+	BidActionReject   BidAction = "reject"
+	BidActionAccept   BidAction = "accept"
+)
+
+// BidRound is one versioned step in a bid's negotiation thread.
+type BidRound struct {
+	Version   int       `bson:"version" json:"version"`
+	Actor     BidActor  `bson:"actor" json:"actor"`
+	Action    BidAction `bson:"action" json:"action"`
+	Amount    float64   `bson:"amount,omitempty" json:"amount,omitempty"`
+	Message   string    `bson:"message,omitempty" json:"message,omitempty"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
 }
 
 type ListingFilter struct {
-	Type     ListingType `json:"type"`
-	Make     string      `json:"make"`
-	Model    string      `json:"model"`
-	MinYear  int         `json:"minYear"`
-	MaxYear  int         `json:"maxYear"`
-	MinPrice float64     `json:"minPrice"`
-	MaxPrice float64     `json:"maxPrice"`
+	Type              ListingType    `json:"type"`
+	Make              string         `json:"make"`
+	Model             string         `json:"model"`
+	MinYear           int            `json:"minYear"`
+	MaxYear           int            `json:"maxYear"`
+	MinPrice          float64        `json:"minPrice"`
+	MaxPrice          float64        `json:"maxPrice"`
+	MinMileage        int            `json:"minMileage"`
+	MaxMileage        int            `json:"maxMileage"`
+	FuelType          FuelType       `json:"fuelType"`
+	Transmission      Transmission   `json:"transmission"`
+	BodyType          BodyType       `json:"bodyType"`
+	MinEngineSize     int            `json:"minEngineSize"`
+	MaxEngineSize     int            `json:"maxEngineSize"`
+	DriveType         DriveType      `json:"driveType"`
+	Colour            string         `json:"colour"`
+	ConditionGrade    ConditionGrade `json:"conditionGrade"`
+	MaxOwners         int            `json:"maxOwners"`
+	RegistrationPlate string         `json:"registrationPlate"`
+	City              string         `json:"city"`
+	Near              *GeoQuery      `json:"near,omitempty"`
 }
 
 type Pagination struct {
-	Limit  int `json:"limit"`  // No of items per page
-	Offset int `json:"offset"` // No of items to skip
+	Limit  int     `json:"limit"`  // No of items per page
+	Offset int     `json:"offset"` // No of items to skip
+	Cursor *Cursor `json:"-"`      // Keyset position; Offset is ignored when set
 }
 
 type ListingPerformance struct {
@@ -103,6 +255,8 @@ type FeedResponse struct {
 	Listings   []Listing   `json:"listings"`
 	Promotions []Promotion `json:"promotions,omitempty"`
 	Banners    []Banner    `json:"banners,omitempty"`
+	Cursors    PageCursors `json:"-"`
+	Page
 }
 
 type Promotion struct {
@@ -134,12 +288,39 @@ type Banner struct {
 
 type SearchResult struct {
 	Listings    []Listing          `json:"listings"`
+	Total       int                `json:"total"` // Matches across all pages
+	Facets      *SearchFacets      `json:"facets,omitempty"`
 	Promotions  []Promotion        `json:"promotions,omitempty"`
 	Suggestions []SearchSuggestion `json:"suggestions,omitempty"`
+	SearchID    string             `json:"searchId,omitempty"` // Sent back with clicks on the results
+	Cursors     PageCursors        `json:"-"`
+	Page
+}
+
+// SearchFacets counts the listings matching a search by attribute, for
+// narrowing it down.
+type SearchFacets struct {
+	Makes     []FacetCount `json:"makes"`
+	Models    []FacetCount `json:"models"`
+	Years     []FacetCount `json:"years"`
+	Prices    []FacetCount `json:"prices"`
+	BodyTypes []FacetCount `json:"bodyTypes"`
+	Cities    []FacetCount `json:"cities"`
+}
+
+// FacetCount is one facet value. Year and price buckets also carry their
+// bounds, Min inclusive and Max exclusive; the top bucket has no Max.
+type FacetCount struct {
+	Value string   `json:"value"`
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
 }
 
 type SearchSuggestion struct {
 	Text  string  `json:"text"`
-	Type  string  `json:"type"` // "make", "model", "keyword"
+	Type  string  `json:"type"`           // "make", "model", "dealer", "keyword"
+	Make  string  `json:"make,omitempty"` // Models only
+	Slug  string  `json:"slug,omitempty"` // Dealers only
 	Score float64 `json:"score"`
 }
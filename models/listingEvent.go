@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ListingEventType string
+
+const (
+	ListingEventCreated     ListingEventType = "created"
+	ListingEventUpdated     ListingEventType = "updated"
+	ListingEventPublished   ListingEventType = "published"
+	ListingEventClosed      ListingEventType = "closed"
+	ListingEventSold        ListingEventType = "sold"
+	ListingEventDeleted     ListingEventType = "deleted"
+	ListingEventBid         ListingEventType = "bid" // Placed, raised, countered, rejected or withdrawn
+	ListingEventBidAccepted ListingEventType = "bid_accepted"
+	ListingEventExpired     ListingEventType = "expired"
+	ListingEventRenewed     ListingEventType = "renewed"
+	ListingEventPurged      ListingEventType = "purged" // Stale draft deleted by the cleanup job
+)
+
+type ActorType string
+
+const (
+	ActorDealer ActorType = "dealer"
+	ActorUser   ActorType = "user"
+	ActorAdmin  ActorType = "admin"
+	ActorSystem ActorType = "system" // Schedulers and other background jobs
+)
+
+// AuditActor is who made a change and through which endpoint.
+type AuditActor struct {
+	Type   ActorType `bson:"type" json:"type"`
+	ID     string    `bson:"id,omitempty" json:"id,omitempty"`
+	Source string    `bson:"source,omitempty" json:"source,omitempty"` // e.g. "PUT /api/dealers/listings/:id"
+}
+
+// FieldChange is one field of a listing before and after a change, by its
+// dotted path. Array elements with an ID are addressed by that ID.
+type FieldChange struct {
+	Field string      `bson:"field" json:"field"`
+	From  interface{} `bson:"from,omitempty" json:"from,omitempty"`
+	To    interface{} `bson:"to,omitempty" json:"to,omitempty"`
+}
+
+// ListingEvent is one entry in a listing's audit trail.
+type ListingEvent struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	ListingID primitive.ObjectID `bson:"listingId" json:"listingId"`
+	Type      ListingEventType   `bson:"type" json:"type"`
+	Actor     AuditActor         `bson:"actor" json:"actor"`
+	Changes   []FieldChange      `bson:"changes,omitempty" json:"changes,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// PriceChange is a listing's asking price from a point in time.
+type PriceChange struct {
+	Price float64   `json:"price"`
+	At    time.Time `json:"at"`
+}
+
+type PriceHistory struct {
+	ListingID    string        `json:"listingId"`
+	CurrentPrice float64       `json:"currentPrice"`
+	Changes      []PriceChange `json:"changes"` // Oldest first
+}
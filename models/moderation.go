@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ModerationStatus string
+
+const (
+	ModerationStatusFlagged ModerationStatus = "flagged" // Awaiting admin review
+	ModerationStatusCleared ModerationStatus = "cleared"
+)
+
+type ModerationReason string
+
+const (
+	ModerationReasonDuplicateVIN ModerationReason = "duplicate_vin"
+)
+
+// ListingModeration collects the flags raised on a listing. A flagged
+// listing stays on the market until an admin reviews it.
+type ListingModeration struct {
+	Status ModerationStatus `bson:"status" json:"status"`
+	Flags  []ModerationFlag `bson:"flags" json:"flags"`
+}
+
+type ModerationFlag struct {
+	Reason          ModerationReason     `bson:"reason" json:"reason"`
+	RelatedListings []primitive.ObjectID `bson:"relatedListings,omitempty" json:"relatedListings,omitempty"`
+	Message         string               `bson:"message" json:"message"` // Explanation shown to the listing owner
+	CreatedAt       time.Time            `bson:"createdAt" json:"createdAt"`
+}
+
+// VINCluster is every listing that has ever carried one VIN, with the
+// reasons the group looks suspicious.
+type VINCluster struct {
+	VIN               string              `bson:"_id" json:"vin"`
+	Listings          []VINClusterListing `bson:"listings" json:"listings"` // Oldest first
+	LiveCount         int                 `bson:"liveCount" json:"liveCount"`
+	Reasons           []string            `bson:"-" json:"reasons"`
+	PriceGapPercent   float64             `bson:"-" json:"priceGapPercent,omitempty"`
+	MileageRollbackKm int                 `bson:"-" json:"mileageRollbackKm,omitempty"`
+	Years             []int               `bson:"-" json:"years,omitempty"`
+}
+
+type VINClusterListing struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Type      ListingType        `bson:"type" json:"type"`
+	Status    ListingStatus      `bson:"status" json:"status"`
+	DealerID  primitive.ObjectID `bson:"dealerId,omitempty" json:"dealerId,omitempty"`
+	UserID    primitive.ObjectID `bson:"userId,omitempty" json:"userId,omitempty"`
+	Make      string             `bson:"make" json:"make"`
+	Model     string             `bson:"model" json:"model"`
+	Year      int                `bson:"year" json:"year"`
+	Price     float64            `bson:"price,omitempty" json:"price,omitempty"`
+	Mileage   int                `bson:"mileage" json:"mileage"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -15,12 +15,46 @@ const (
 	NotificationTargetDealer NotificationTarget = "dealer"
 
 	// Type is WHAT the notification is about (action)
-	NotificationTypeBidPlaced        NotificationType = "bid_placed"
-	NotificationTypeBidAccepted      NotificationType = "bid_accepted"
-	NotificationTypeListingCreated   NotificationType = "listing_created"
-	NotificationTypeListingUpdated   NotificationType = "listing_updated"
-	NotificationTypeListingPublished NotificationType = "listing_published"
-	NotificationTypeListingClosed    NotificationType = "listing_closed"
+	NotificationTypeBidPlaced          NotificationType = "bid_placed"
+	NotificationTypeBidAccepted        NotificationType = "bid_accepted"
+	NotificationTypeBidRaised          NotificationType = "bid_raised"
+	NotificationTypeBidUpdated         NotificationType = "bid_updated"
+	NotificationTypeBidCountered       NotificationType = "bid_countered"
+	NotificationTypeBidWithdrawn       NotificationType = "bid_withdrawn"
+	NotificationTypeBidRejected        NotificationType = "bid_rejected"
+	NotificationTypeListingCreated     NotificationType = "listing_created"
+	NotificationTypeListingUpdated     NotificationType = "listing_updated"
+	NotificationTypeListingPublished   NotificationType = "listing_published"
+	NotificationTypeListingClosed      NotificationType = "listing_closed"
+	NotificationTypeListingSold        NotificationType = "listing_sold"
+	NotificationTypeListingReopened    NotificationType = "listing_reopened"
+	NotificationTypeAuctionWon         NotificationType = "auction_won"
+	NotificationTypeAuctionLost        NotificationType = "auction_lost"
+	NotificationTypeAuctionEnded       NotificationType = "auction_ended"
+	NotificationTypeAuctionNoSale      NotificationType = "auction_no_sale"
+	NotificationTypeTradeInCreated     NotificationType = "trade_in_created"
+	NotificationTypeTradeInOffer       NotificationType = "trade_in_offer"
+	NotificationTypeTradeInAccepted    NotificationType = "trade_in_accepted"
+	NotificationTypeTradeInCancelled   NotificationType = "trade_in_cancelled"
+	NotificationTypeTradeInContact     NotificationType = "trade_in_contact"
+	NotificationTypeTransactionOpened  NotificationType = "transaction_opened"
+	NotificationTypeTransactionUpdated NotificationType = "transaction_updated"
+	NotificationTypePurchaseClaimed    NotificationType = "purchase_claimed"
+	NotificationTypePaymentReceived    NotificationType = "payment_received"
+	NotificationTypePaymentFailed      NotificationType = "payment_failed"
+	NotificationTypeListingReserved    NotificationType = "listing_reserved"
+	NotificationTypeHandoverConfirmed  NotificationType = "handover_confirmed"
+	NotificationTypePayoutScheduled    NotificationType = "payout_scheduled"
+	NotificationTypePayoutFailed       NotificationType = "payout_failed"
+	NotificationTypeListingFlagged     NotificationType = "listing_flagged"
+	NotificationTypeSavedSearchMatch   NotificationType = "saved_search_match"
+	NotificationTypeSavedSearchDigest  NotificationType = "saved_search_digest"
+	NotificationTypeWatchedPriceDrop   NotificationType = "watched_price_drop"
+	NotificationTypeWatchedSold        NotificationType = "watched_listing_sold"
+	NotificationTypeWatchedClosed      NotificationType = "watched_listing_closed"
+	NotificationTypeListingExpiring    NotificationType = "listing_expiring"
+	NotificationTypeListingExpired     NotificationType = "listing_expired"
+	NotificationTypeListingRenewed     NotificationType = "listing_renewed"
 )
 
 type Notification struct {
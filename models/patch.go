@@ -0,0 +1,246 @@
+package models
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnknownField   = errors.New("unknown field")
+	ErrFieldForbidden = errors.New("field not writable")
+	ErrInvalidField   = errors.New("invalid value")
+)
+
+// FieldError rejects one field of a request body, named by its dotted JSON
+// path. Err is one of ErrUnknownField, ErrFieldForbidden or ErrInvalidField.
+type FieldError struct {
+	Field  string
+	Err    error
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	msg := e.Err.Error()
+	if e.Reason != "" {
+		msg = e.Reason
+	}
+	if e.Field == "" {
+		return msg
+	}
+	return e.Field + ": " + msg
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// InvalidField rejects the value sent for field.
+func InvalidField(field, format string, args ...interface{}) error {
+	return &FieldError{Field: field, Err: ErrInvalidField, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Patch is one field of a JSON Merge Patch (RFC 7396) body. Set reports the
+// key was present; Null that it was sent as null, asking for the field to be
+// cleared. An absent key leaves the stored value alone.
+type Patch[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (p *Patch[T]) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if string(data) == "null" {
+		p.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &p.Value)
+}
+
+func (p Patch[T]) isSet() bool { return p.Set }
+
+func (p Patch[T]) valueType() reflect.Type { return reflect.TypeOf((*T)(nil)).Elem() }
+
+type patchField interface {
+	isSet() bool
+	valueType() reflect.Type
+}
+
+var (
+	patchFieldType  = reflect.TypeOf((*patchField)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textType        = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodePatch decodes a merge-patch body into v, a pointer to a patch struct.
+// Nested patch structs are held by pointer and merge into the stored object;
+// Patch fields replace the stored value whole, arrays included. Every key is
+// checked against v's JSON tags and every value against its field's type
+// before anything is decoded, so the first bad field comes back as a
+// *FieldError naming it.
+func DecodePatch(data []byte, v interface{}) error {
+	if err := checkObject(data, reflect.TypeOf(v).Elem(), ""); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func checkObject(raw json.RawMessage, t reflect.Type, path string) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return InvalidField(path, "must be an object")
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		sf, ok := jsonField(t, key)
+		if !ok {
+			return &FieldError{Field: joinPath(path, key), Err: ErrUnknownField}
+		}
+		if err := checkValue(fields[key], sf.Type, joinPath(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkValue(raw json.RawMessage, t reflect.Type, path string) error {
+	isNull := string(raw) == "null"
+	switch {
+	case t.Implements(patchFieldType):
+		if isNull {
+			return nil
+		}
+		return checkValue(raw, reflect.Zero(t).Interface().(patchField).valueType(), path)
+	case t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct:
+		if isNull {
+			return InvalidField(path, "cannot be null")
+		}
+		return checkObject(raw, t.Elem(), path)
+	case t.Kind() == reflect.Struct && !decodesItself(t):
+		return checkObject(raw, t, path)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct && !decodesItself(t.Elem()):
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return InvalidField(path, "must be an array")
+		}
+		for i, item := range items {
+			if err := checkObject(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+		return InvalidField(path, "must be %s", describeType(t))
+	}
+	return nil
+}
+
+// PatchedFields lists the dotted paths of the fields a decoded patch sets,
+// in declaration order.
+func PatchedFields(v interface{}) []string {
+	var out []string
+	collectPatched(reflect.Indirect(reflect.ValueOf(v)), "", &out)
+	return out
+}
+
+func collectPatched(v reflect.Value, path string, out *[]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		f := v.Field(i)
+		switch {
+		case f.Type().Implements(patchFieldType):
+			if f.Interface().(patchField).isSet() {
+				*out = append(*out, joinPath(path, name))
+			}
+		case f.Kind() == reflect.Pointer && !f.IsNil() && f.Elem().Kind() == reflect.Struct:
+			collectPatched(f.Elem(), joinPath(path, name), out)
+		}
+	}
+}
+
+// CheckPatchFields rejects the first field not covered by allowed. An
+// allowed path also covers everything beneath it.
+func CheckPatchFields(fields, allowed []string) error {
+	for _, field := range fields {
+		if !pathAllowed(field, allowed) {
+			return &FieldError{Field: field, Err: ErrFieldForbidden}
+		}
+	}
+	return nil
+}
+
+func pathAllowed(field string, allowed []string) bool {
+	for _, a := range allowed {
+		if field == a || strings.HasPrefix(field, a+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); jsonName(sf) == key {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func jsonName(sf reflect.StructField) string {
+	if !sf.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return sf.Name
+	}
+	return name
+}
+
+func decodesItself(t reflect.Type) bool {
+	p := reflect.PointerTo(t)
+	return p.Implements(unmarshalerType) || p.Implements(textType)
+}
+
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a valid " + t.String()
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+		field  string // Field named by the error
+		err    error
+	}{
+		{name: "empty", body: `{}`},
+		{name: "top level field", body: `{"status":"closed"}`, fields: []string{"status"}},
+		{name: "nested fields", body: `{"carDetails":{"price":1250000,"mileage":42000}}`, fields: []string{"carDetails.price", "carDetails.mileage"}},
+		{name: "null clears", body: `{"carDetails":{"colour":null}}`, fields: []string{"carDetails.colour"}},
+		{name: "not an object", body: `[]`, err: ErrInvalidField},
+		{name: "unknown field", body: `{"views":10}`, field: "views", err: ErrUnknownField},
+		{name: "unknown nested field", body: `{"carDetails":{"vin":"1HGCM82633A004352"}}`, field: "carDetails.vin", err: ErrUnknownField},
+		{name: "wrong type", body: `{"carDetails":{"price":"cheap"}}`, field: "carDetails.price", err: ErrInvalidField},
+		{name: "nested object null", body: `{"carDetails":null}`, field: "carDetails", err: ErrInvalidField},
+		{name: "nested object not an object", body: `{"carDetails":5}`, field: "carDetails", err: ErrInvalidField},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch ListingPatch
+			err := DecodePatch([]byte(tt.body), &patch)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("DecodePatch(%s) error = %v, want %v", tt.body, err, tt.err)
+			}
+			if err != nil {
+				var fe *FieldError
+				if !errors.As(err, &fe) || fe.Field != tt.field {
+					t.Errorf("DecodePatch(%s) error field = %v, want %q", tt.body, err, tt.field)
+				}
+				return
+			}
+			if got := PatchedFields(&patch); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("PatchedFields(%s) = %v, want %v", tt.body, got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestPatchUnmarshal(t *testing.T) {
+	tests := []struct {
+		body string
+		want Patch[int]
+		err  bool
+	}{
+		{body: `{}`, want: Patch[int]{}},
+		{body: `{"n":null}`, want: Patch[int]{Set: true, Null: true}},
+		{body: `{"n":0}`, want: Patch[int]{Set: true}},
+		{body: `{"n":7}`, want: Patch[int]{Set: true, Value: 7}},
+		{body: `{"n":"7"}`, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			var v struct {
+				N Patch[int] `json:"n"`
+			}
+			err := json.Unmarshal([]byte(tt.body), &v)
+			if (err != nil) != tt.err {
+				t.Fatalf("Unmarshal(%s) error = %v, want error %v", tt.body, err, tt.err)
+			}
+			if !tt.err && v.N != tt.want {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.body, v.N, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPatchFields(t *testing.T) {
+	allowed := []string{"status", "carDetails.price"}
+	tests := []struct {
+		name   string
+		fields []string
+		denied string
+	}{
+		{name: "nothing patched"},
+		{name: "allowed fields", fields: []string{"status", "carDetails.price"}},
+		{name: "beneath an allowed path", fields: []string{"carDetails.price.amount"}},
+		{name: "sibling of an allowed path", fields: []string{"carDetails.price", "carDetails.make"}, denied: "carDetails.make"},
+		{name: "shared prefix is not a parent", fields: []string{"statusNote"}, denied: "statusNote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPatchFields(tt.fields, allowed)
+			if tt.denied == "" {
+				if err != nil {
+					t.Fatalf("CheckPatchFields(%v) error = %v, want nil", tt.fields, err)
+				}
+				return
+			}
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Field != tt.denied || !errors.Is(err, ErrFieldForbidden) {
+				t.Errorf("CheckPatchFields(%v) error = %v, want %s forbidden", tt.fields, err, tt.denied)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CurrencyKES is the currency every payment and ledger amount is held in.
+const CurrencyKES = "KES"
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"    // STK push sent, waiting for the customer
+	PaymentStatusSucceeded PaymentStatus = "succeeded"  // Money received and applied
+	PaymentStatusFailed    PaymentStatus = "failed"     // Declined, cancelled or timed out
+	PaymentStatusRefundDue PaymentStatus = "refund_due" // Money received but the car was no longer available
+)
+
+type PaymentPurpose string
+
+const (
+	PaymentPurposeDeposit PaymentPurpose = "deposit"
+)
+
+// Payment is one attempt to collect money through a payment gateway.
+type Payment struct {
+	ID                primitive.ObjectID  `bson:"_id" json:"id"`
+	ListingID         primitive.ObjectID  `bson:"listingId" json:"listingId"`
+	TransactionID     *primitive.ObjectID `bson:"transactionId,omitempty" json:"transactionId,omitempty"` // Set once the deposit is applied
+	Payer             TransactionParty    `bson:"payer" json:"payer"`
+	Purpose           PaymentPurpose      `bson:"purpose" json:"purpose"`
+	Amount            float64             `bson:"amount" json:"amount"`
+	Currency          string              `bson:"currency" json:"currency"`
+	PhoneNumber       string              `bson:"phoneNumber" json:"phoneNumber"` // MSISDN in 2547XXXXXXXX form
+	Gateway           string              `bson:"gateway" json:"gateway"`
+	MerchantRequestID string              `bson:"merchantRequestId,omitempty" json:"-"`
+	CheckoutRequestID string              `bson:"checkoutRequestId,omitempty" json:"checkoutRequestId,omitempty"`
+	Receipt           string              `bson:"receipt,omitempty" json:"receipt,omitempty"` // M-Pesa receipt number
+	ResultCode        int                 `bson:"resultCode,omitempty" json:"resultCode,omitempty"`
+	ResultDesc        string              `bson:"resultDesc,omitempty" json:"resultDesc,omitempty"`
+	IdempotencyKey    string              `bson:"idempotencyKey,omitempty" json:"-"`
+	Status            PaymentStatus       `bson:"status" json:"status"`
+	Attempts          int                 `bson:"attempts" json:"-"`              // Status queries made by reconciliation
+	ListingHeld       bool                `bson:"listingHeld,omitempty" json:"-"` // Set before the deposit reserves the car
+	CreatedAt         time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt         time.Time           `bson:"updatedAt" json:"updatedAt"`
+	CompletedAt       *time.Time          `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
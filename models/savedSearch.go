@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AlertFrequency is how often a saved search tells its owner about new matches.
+type AlertFrequency string
+
+const (
+	AlertInstant AlertFrequency = "instant"
+	AlertDaily   AlertFrequency = "daily"
+	AlertOff     AlertFrequency = "off"
+)
+
+func (f AlertFrequency) Valid() bool {
+	switch f {
+	case AlertInstant, AlertDaily, AlertOff:
+		return true
+	}
+	return false
+}
+
+// SavedSearch is a search a user wants to hear about when new listings match.
+type SavedSearch struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Name      string             `bson:"name" json:"name"`
+	Query     string             `bson:"query,omitempty" json:"query,omitempty"`
+	Filter    ListingFilter      `bson:"filter" json:"filter"`
+	MakeKey   string             `bson:"makeKey" json:"-"` // Lower-cased Filter.Make, for finding candidates
+	Frequency AlertFrequency     `bson:"frequency" json:"frequency"`
+	// Pending holds matches waiting for the next daily digest.
+	Pending        []primitive.ObjectID `bson:"pending,omitempty" json:"-"`
+	LastNotifiedAt *time.Time           `bson:"lastNotifiedAt,omitempty" json:"lastNotifiedAt,omitempty"`
+	CreatedAt      time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// SavedSearchRequest creates a saved search, or changes the fields it sets.
+type SavedSearchRequest struct {
+	Name      *string         `json:"name"`
+	Query     *string         `json:"query"`
+	Filter    *ListingFilter  `json:"filter"`
+	Frequency *AlertFrequency `json:"frequency"`
+}
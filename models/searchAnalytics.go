@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SearchEventKind string
+
+const (
+	SearchEventSearch SearchEventKind = "search"
+	SearchEventClick  SearchEventKind = "click"
+)
+
+// SearchEvent records a search, or a click on one of its results. Events
+// are kept for a limited time; SearchTrend holds what is learned from them.
+type SearchEvent struct {
+	ID        primitive.ObjectID  `bson:"_id"`
+	Kind      SearchEventKind     `bson:"kind"`
+	SearchID  primitive.ObjectID  `bson:"searchId"` // Clicks: the search clicked from; searches: their own ID
+	Query     string              `bson:"query"`    // Lower-cased text, or the make and model filtered on
+	Make      string              `bson:"make,omitempty"`
+	Model     string              `bson:"model,omitempty"`
+	City      string              `bson:"city,omitempty"`
+	Results   int                 `bson:"results"`
+	ListingID *primitive.ObjectID `bson:"listingId,omitempty"`
+	CreatedAt time.Time           `bson:"createdAt"`
+	ExpiresAt time.Time           `bson:"expiresAt"`
+}
+
+// SearchTrend is one query's activity over the trend window, in one city or,
+// with City empty, everywhere.
+type SearchTrend struct {
+	City         string    `bson:"city" json:"city,omitempty"`
+	Query        string    `bson:"query" json:"query"`
+	Searches     int       `bson:"searches" json:"searches"`
+	ZeroResults  int       `bson:"zeroResults" json:"zeroResults"` // Searches that found nothing
+	Clicks       int       `bson:"clicks" json:"clicks"`
+	ClickThrough float64   `bson:"clickThrough" json:"clickThrough"` // Share of searches with a click
+	LastSeen     time.Time `bson:"lastSeen" json:"lastSeen"`
+	WindowStart  time.Time `bson:"windowStart" json:"-"`
+	ComputedAt   time.Time `bson:"computedAt" json:"-"`
+}
+
+// SearchTrends is what buyers searched for most, and what they searched for
+// without finding anything.
+type SearchTrends struct {
+	City        string        `json:"city,omitempty"`
+	WindowStart time.Time     `json:"windowStart"`
+	ComputedAt  time.Time     `json:"computedAt"`
+	Top         []SearchTrend `json:"top"`
+	Unmet       []SearchTrend `json:"unmet"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TradeInStatus string
+
+const (
+	TradeInStatusOpen      TradeInStatus = "open"      // Awaiting dealer valuations
+	TradeInStatusAccepted  TradeInStatus = "accepted"  // User took an offer; credit applied to the target listing
+	TradeInStatusCancelled TradeInStatus = "cancelled" // Withdrawn by the user or the target listing went away
+)
+
+type TradeInOfferStatus string
+
+const (
+	TradeInOfferPending    TradeInOfferStatus = "pending"
+	TradeInOfferSuperseded TradeInOfferStatus = "superseded" // Replaced by a newer valuation from the same dealer
+	TradeInOfferAccepted   TradeInOfferStatus = "accepted"
+	TradeInOfferRejected   TradeInOfferStatus = "rejected"
+)
+
+// TradeIn is a user's current car offered as part-payment on a dealer listing.
+type TradeIn struct {
+	ID              primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID          primitive.ObjectID  `bson:"userId" json:"userId"`
+	TargetListingID primitive.ObjectID  `bson:"targetListingId" json:"targetListingId"`
+	DealerID        primitive.ObjectID  `bson:"dealerId" json:"dealerId"` // Owner of the target listing
+	CarDetails      CarDetails          `bson:"carDetails" json:"carDetails" binding:"required"`
+	Notes           string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	Status          TradeInStatus       `bson:"status" json:"status"`
+	Offers          []TradeInOffer      `bson:"offers" json:"offers"`
+	AcceptedOffer   *primitive.ObjectID `bson:"acceptedOffer,omitempty" json:"acceptedOffer,omitempty"`
+	Deal            *TradeInDeal        `bson:"deal,omitempty" json:"deal,omitempty"`
+	Contacts        []TradeInContact    `bson:"contacts,omitempty" json:"contacts,omitempty"`
+	CreatedAt       time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TradeInOffer is a dealer's valuation of the trade-in car.
+type TradeInOffer struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	DealerID  primitive.ObjectID `bson:"dealerId" json:"dealerId"`
+	Amount    float64            `bson:"amount" json:"amount"`
+	Message   string             `bson:"message,omitempty" json:"message,omitempty"`
+	Status    TradeInOfferStatus `bson:"status" json:"status"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TradeInDeal records how an accepted offer was credited against the target listing.
+type TradeInDeal struct {
+	ListingPrice float64   `bson:"listingPrice" json:"listingPrice"`
+	Credit       float64   `bson:"credit" json:"credit"`
+	Balance      float64   `bson:"balance" json:"balance"` // What the user still owes the dealer
+	AcceptedAt   time.Time `bson:"acceptedAt" json:"acceptedAt"`
+}
+
+// TradeInContact logs a dealer reaching out to the user about a lead.
+type TradeInContact struct {
+	DealerID  primitive.ObjectID `bson:"dealerId" json:"dealerId"`
+	Channel   string             `bson:"channel" json:"channel"` // "whatsapp", "call", "email"
+	Message   string             `bson:"message,omitempty" json:"message,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionStatusPending     TransactionStatus = "pending"      // Deal agreed, no money moved yet
+	TransactionStatusDepositPaid TransactionStatus = "deposit_paid" // Buyer has paid a holding deposit
+	TransactionStatusCompleted   TransactionStatus = "completed"    // Car handed over and paid for
+	TransactionStatusCancelled   TransactionStatus = "cancelled"
+	TransactionStatusRefunded    TransactionStatus = "refunded"
+)
+
+// PartyType says whether a transaction party is a private user or a dealer.
+type PartyType string
+
+const (
+	PartyTypeUser   PartyType = "user"
+	PartyTypeDealer PartyType = "dealer"
+)
+
+type TransactionParty struct {
+	ID   primitive.ObjectID `bson:"id" json:"id"`
+	Type PartyType          `bson:"type" json:"type"`
+}
+
+// Transaction is the record of a car changing hands on the platform.
+type Transaction struct {
+	ID          primitive.ObjectID   `bson:"_id" json:"id"`
+	ListingID   primitive.ObjectID   `bson:"listingId" json:"listingId"`
+	ListingType ListingType          `bson:"listingType" json:"listingType"`
+	CarDetails  CarDetails           `bson:"carDetails" json:"carDetails"`           // Snapshot at the time of sale
+	Buyer       *TransactionParty    `bson:"buyer,omitempty" json:"buyer,omitempty"` // Unknown until the dealer records who bought
+	Seller      TransactionParty     `bson:"seller" json:"seller"`
+	BidID       *primitive.ObjectID  `bson:"bidId,omitempty" json:"bidId,omitempty"`
+	Price       float64              `bson:"price" json:"price"`
+	Fees        []TransactionFee     `bson:"fees,omitempty" json:"fees,omitempty"`
+	Deposit     float64              `bson:"deposit,omitempty" json:"deposit,omitempty"`
+	TradeIn     *TransactionTradeIn  `bson:"tradeIn,omitempty" json:"tradeIn,omitempty"` // Buyer's accepted trade-in, credited against the price
+	Status      TransactionStatus    `bson:"status" json:"status"`
+	Open        bool                 `bson:"open" json:"-"` // False once cancelled or refunded; one open transaction per listing
+	Handover    *TransactionHandover `bson:"handover,omitempty" json:"handover,omitempty"`
+	History     []TransactionEvent   `bson:"history" json:"history"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TransactionTradeIn credits the buyer's accepted trade-in against the price.
+type TransactionTradeIn struct {
+	TradeInID primitive.ObjectID `bson:"tradeInId" json:"tradeInId"`
+	Credit    float64            `bson:"credit" json:"credit"`
+	Balance   float64            `bson:"balance" json:"balance"` // What the buyer still pays
+}
+
+type TransactionFee struct {
+	Name   string    `bson:"name" json:"name"` // e.g. "platform"
+	Amount float64   `bson:"amount" json:"amount"`
+	Payer  PartyType `bson:"payer" json:"payer"`
+}
+
+// TransactionHandover records each side confirming the car and keys changed
+// hands; escrow is released once both have.
+type TransactionHandover struct {
+	BuyerConfirmedAt  *time.Time `bson:"buyerConfirmedAt,omitempty" json:"buyerConfirmedAt,omitempty"`
+	SellerConfirmedAt *time.Time `bson:"sellerConfirmedAt,omitempty" json:"sellerConfirmedAt,omitempty"`
+}
+
+// TransactionEvent is one status change in a transaction's lifetime.
+type TransactionEvent struct {
+	From      TransactionStatus `bson:"from,omitempty" json:"from,omitempty"`
+	To        TransactionStatus `bson:"to" json:"to"`
+	Actor     *TransactionParty `bson:"actor,omitempty" json:"actor,omitempty"` // Nil for system transitions
+	Note      string            `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt time.Time         `bson:"createdAt" json:"createdAt"`
+}
@@ -11,6 +11,7 @@ type User struct {
 	PasswordHash string    `bson:"passwordHash" json:"-"`
 	ProfileImage string    `bson:"profileImage,omitempty" json:"profileImage,omitempty"`
 	Preferences  []string  `bson:"preferences,omitempty" json:"preferences,omitempty"`
+	Location     *Location `bson:"location,omitempty" json:"location,omitempty"` // Default location for the user's listings
 	Devices      []Device  `bson:"devices,omitempty" json:"devices,omitempty"`
 	CreatedAt    time.Time `bson:"createdAt" json:"createdAt"`
 	UpdatedAt    time.Time `bson:"updatedAt" json:"updatedAt"`
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// PriceSource is where a market price point came from.
+type PriceSource string
+
+const (
+	PriceSourceSale        PriceSource = "sale"         // Asking price of a listing marked sold
+	PriceSourceAcceptedBid PriceSource = "accepted_bid" // Dealer offer a seller accepted
+	PriceSourceClosed      PriceSource = "closed"       // Asking price of a listing withdrawn unsold
+)
+
+// PricePoint is one observed price for a car, used to value others like it.
+type PricePoint struct {
+	Make    string      `bson:"make"`
+	Model   string      `bson:"model"`
+	Year    int         `bson:"year"`
+	Mileage int         `bson:"mileage"`
+	Price   float64     `bson:"price"`
+	Source  PriceSource `bson:"source"`
+	At      time.Time   `bson:"at"`
+}
+
+type ValuationRequest struct {
+	Make    string `form:"make" json:"make" binding:"required"`
+	Model   string `form:"model" json:"model" binding:"required"`
+	Year    int    `form:"year" json:"year" binding:"required"`
+	Mileage int    `form:"mileage" json:"mileage"` // Kilometres; 0 assumes a typical mileage for the age
+}
+
+type ConfidenceLevel string
+
+const (
+	ConfidenceHigh   ConfidenceLevel = "high"
+	ConfidenceMedium ConfidenceLevel = "medium"
+	ConfidenceLow    ConfidenceLevel = "low"
+)
+
+// Valuation is a fair price range for a car. Basis says whether it was drawn
+// from the same model or, with less data, from the make as a whole.
+type Valuation struct {
+	Make             string          `json:"make"`
+	Model            string          `json:"model"`
+	Year             int             `json:"year"`
+	Mileage          int             `json:"mileage"`
+	Estimate         float64         `json:"estimate"`
+	Low              float64         `json:"low"`
+	High             float64         `json:"high"`
+	Confidence       float64         `json:"confidence"` // 0 to 1
+	ConfidenceLevel  ConfidenceLevel `json:"confidenceLevel"`
+	Comparables      int             `json:"comparables"`
+	Basis            string          `json:"basis"`
+	DepreciationRate float64         `json:"depreciationRate"` // Yearly fraction of value lost
+	ComputedAt       time.Time       `json:"computedAt"`
+}
+
+type PriceRating string
+
+const (
+	PriceRatingGreat PriceRating = "great"
+	PriceRatingGood  PriceRating = "good"
+	PriceRatingFair  PriceRating = "fair"
+	PriceRatingHigh  PriceRating = "high"
+)
+
+// MarketBadge compares a listing's asking price with its valuation.
+type MarketBadge struct {
+	Rating   PriceRating `json:"rating"`
+	Estimate float64     `json:"estimate"`
+	DeltaPct float64     `json:"deltaPct"` // Asking price above (+) or below (-) the estimate
+}
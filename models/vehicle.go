@@ -0,0 +1,61 @@
+package models
+
+type FuelType string
+
+const (
+	FuelTypePetrol   FuelType = "petrol"
+	FuelTypeDiesel   FuelType = "diesel"
+	FuelTypeHybrid   FuelType = "hybrid"
+	FuelTypeElectric FuelType = "electric"
+	FuelTypeLPG      FuelType = "lpg"
+)
+
+type Transmission string
+
+const (
+	TransmissionAutomatic Transmission = "automatic"
+	TransmissionManual    Transmission = "manual"
+	TransmissionCVT       Transmission = "cvt"
+)
+
+type BodyType string
+
+const (
+	BodyTypeSedan        BodyType = "sedan"
+	BodyTypeHatchback    BodyType = "hatchback"
+	BodyTypeSUV          BodyType = "suv"
+	BodyTypeStationWagon BodyType = "station_wagon"
+	BodyTypeCoupe        BodyType = "coupe"
+	BodyTypeConvertible  BodyType = "convertible"
+	BodyTypePickup       BodyType = "pickup"
+	BodyTypeVan          BodyType = "van"
+	BodyTypeBus          BodyType = "bus"
+	BodyTypeTruck        BodyType = "truck"
+)
+
+type DriveType string
+
+const (
+	DriveTypeFWD DriveType = "fwd"
+	DriveTypeRWD DriveType = "rwd"
+	DriveTypeAWD DriveType = "awd"
+	DriveType4WD DriveType = "4wd"
+)
+
+type ConditionGrade string
+
+const (
+	ConditionNew       ConditionGrade = "new"
+	ConditionExcellent ConditionGrade = "excellent"
+	ConditionGood      ConditionGrade = "good"
+	ConditionFair      ConditionGrade = "fair"
+	ConditionPoor      ConditionGrade = "poor"
+)
+
+var (
+	FuelTypes       = []FuelType{FuelTypePetrol, FuelTypeDiesel, FuelTypeHybrid, FuelTypeElectric, FuelTypeLPG}
+	Transmissions   = []Transmission{TransmissionAutomatic, TransmissionManual, TransmissionCVT}
+	BodyTypes       = []BodyType{BodyTypeSedan, BodyTypeHatchback, BodyTypeSUV, BodyTypeStationWagon, BodyTypeCoupe, BodyTypeConvertible, BodyTypePickup, BodyTypeVan, BodyTypeBus, BodyTypeTruck}
+	DriveTypes      = []DriveType{DriveTypeFWD, DriveTypeRWD, DriveTypeAWD, DriveType4WD}
+	ConditionGrades = []ConditionGrade{ConditionNew, ConditionExcellent, ConditionGood, ConditionFair, ConditionPoor}
+)
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+type VINKind string
+
+const (
+	VINKindVIN     VINKind = "vin"     // 17-character ISO 3779 VIN
+	VINKindChassis VINKind = "chassis" // Japanese domestic-market frame number, e.g. NZE141-1234567
+)
+
+// DecodedVIN is what is known about a car from its VIN or chassis number.
+type DecodedVIN struct {
+	Number       string   `bson:"number" json:"number"` // Canonical form
+	Kind         VINKind  `bson:"kind" json:"kind"`
+	WMI          string   `bson:"wmi,omitempty" json:"wmi,omitempty"`
+	Manufacturer string   `bson:"manufacturer,omitempty" json:"manufacturer,omitempty"`
+	Make         string   `bson:"make,omitempty" json:"make,omitempty"`
+	Model        string   `bson:"model,omitempty" json:"model,omitempty"`
+	BodyClass    string   `bson:"bodyClass,omitempty" json:"bodyClass,omitempty"`
+	Country      string   `bson:"country,omitempty" json:"country,omitempty"`
+	Region       string   `bson:"region,omitempty" json:"region,omitempty"`
+	ModelYears   []int    `bson:"modelYears,omitempty" json:"modelYears,omitempty"` // Newest first; the year code repeats every 30 years
+	ModelCode    string   `bson:"modelCode,omitempty" json:"modelCode,omitempty"`   // Chassis numbers only
+	CheckDigitOK bool     `bson:"checkDigitOk" json:"checkDigitOk"`
+	Verified     bool     `bson:"verified" json:"verified"` // Confirmed by a remote registry
+	Sources      []string `bson:"sources,omitempty" json:"sources,omitempty"`
+
+	// Incomplete marks a decode where a registry could not be reached; it is
+	// never cached.
+	Incomplete bool `bson:"-" json:"-"`
+}
+
+// VINCacheEntry is a decode result, or the reason a number could not be
+// decoded, kept until ExpiresAt.
+type VINCacheEntry struct {
+	Number    string      `bson:"_id" json:"number"`
+	Result    *DecodedVIN `bson:"result,omitempty" json:"result,omitempty"`
+	ErrorCode string      `bson:"errorCode,omitempty" json:"errorCode,omitempty"`
+	Error     string      `bson:"error,omitempty" json:"error,omitempty"`
+	ExpiresAt time.Time   `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt time.Time   `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WatchlistEntry is a user following a listing.
+type WatchlistEntry struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	UserID       primitive.ObjectID `bson:"userId" json:"userId"`
+	ListingID    primitive.ObjectID `bson:"listingId" json:"listingId"`
+	PriceAtWatch float64            `bson:"priceAtWatch,omitempty" json:"priceAtWatch,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// WatchedListing is a watchlist entry with the listing as it is now.
+type WatchedListing struct {
+	Listing      Listing   `json:"listing"`
+	WatchedAt    time.Time `json:"watchedAt"`
+	PriceAtWatch float64   `json:"priceAtWatch,omitempty"`
+	PriceChange  float64   `json:"priceChange,omitempty"` // Negative when the price has dropped since
+}
+
+// ListingInterest is how many users watch one of a dealer's listings.
+type ListingInterest struct {
+	ListingID primitive.ObjectID `bson:"_id" json:"listingId"`
+	Make      string             `bson:"make" json:"make"`
+	Model     string             `bson:"model" json:"model"`
+	Year      int                `bson:"year" json:"year"`
+	Price     float64            `bson:"price" json:"price"`
+	Status    ListingStatus      `bson:"status" json:"status"`
+	Views     int64              `bson:"views" json:"views"`
+	Watchers  int                `bson:"watchers" json:"watchers"`
+}
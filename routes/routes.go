@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"time"
 
+	"carsawa/config"
 	"carsawa/handlers"
 	"carsawa/middleware"
+	"carsawa/models"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -21,17 +23,49 @@ func RegisterDealerRoutes(r *gin.Engine, hb *handlers.HandlerBundle) {
 
 		protected := dealers.Group("")
 		protected.Use(middleware.JWTAuthDealerMiddleware(hb.DealerRepo))
+		protected.Use(middleware.AuditActorMiddleware(models.ActorDealer))
 		{
 			protected.GET("/profile", hb.GetDealerProfileHandler)
 			protected.PUT("/profile", hb.UpdateDealerProfileHandler)
+			protected.PATCH("/profile", hb.UpdateDealerProfileHandler)
 
 			protected.POST("/listings", hb.CreateListingHandler)
 			protected.PUT("/listings/:id", hb.UpdateListingHandler)
+			protected.PATCH("/listings/:id", hb.UpdateListingHandler)
 			protected.DELETE("/listings/:id", hb.DeleteListingHandler)
+			protected.POST("/listings/:id/renew", hb.RenewListingHandler)
 			protected.GET("/listings", hb.GetDealerListingsHandler)
+			protected.GET("/listings/interest", hb.GetListingInterestHandler)
+			protected.POST("/listings/:id/bids", hb.PlaceBidOnUserCarHandler)
+			protected.PUT("/listings/:id/bids/:bidID", hb.UpdateBidHandler)
+			protected.POST("/listings/:id/bids/:bidID/counter", hb.DealerCounterBidHandler)
+			protected.POST("/listings/:id/bids/:bidID/withdraw", hb.WithdrawBidHandler)
+			protected.POST("/listings/:id/media", hb.UploadListingMediaHandler)
+			protected.PUT("/listings/:id/media/order", hb.ReorderListingMediaHandler)
+			protected.PUT("/listings/:id/media/:mediaID/cover", hb.SetListingCoverHandler)
+			protected.PATCH("/listings/:id/media/:mediaID", hb.UpdateMediaCaptionHandler)
+			protected.DELETE("/listings/:id/media/:mediaID", hb.DeleteListingMediaHandler)
 
 			protected.GET("/trade-ins/leads", hb.GetTradeInLeadsHandler)
+			protected.POST("/trade-ins/:id/offers", hb.MakeTradeInOfferHandler)
 			protected.POST("/trade-ins/:id/contact", hb.ContactUserHandler)
+
+			protected.GET("/transactions/purchases", hb.GetUserPurchasesHandler)
+			protected.GET("/transactions/sales", hb.GetUserSalesHandler)
+			protected.POST("/transactions/sales", hb.RecordSaleHandler)
+			protected.GET("/transactions/:id", hb.GetTransactionHandler)
+			protected.POST("/transactions/:id/status", hb.UpdateTransactionStatusHandler)
+			protected.POST("/transactions/:id/handover", hb.ConfirmHandoverHandler)
+
+			protected.GET("/ledger/balance", hb.GetLedgerBalanceHandler)
+			protected.GET("/ledger/payouts", hb.GetLedgerPayoutsHandler)
+			protected.GET("/ledger/statement", hb.GetLedgerStatementHandler)
+
+			protected.GET("/search/trends", hb.GetSearchTrendsHandler)
+
+			protected.GET("/notifications", hb.GetNotificationsHandler)
+			protected.POST("/notifications/read-all", hb.MarkAllNotificationsReadHandler)
+			protected.POST("/notifications/:id/read", hb.MarkNotificationsReadHandler)
 		}
 	}
 
@@ -48,26 +82,90 @@ func RegisterUserRoutes(r *gin.Engine, hb *handlers.HandlerBundle) {
 
 		protected := users.Group("")
 		protected.Use(middleware.JWTAuthUserMiddleware(hb.UserRepo))
+		protected.Use(middleware.AuditActorMiddleware(models.ActorUser))
 		{
 			protected.POST("/trade-ins", hb.CreateTradeInHandler)
 			protected.GET("/trade-ins", hb.GetUserTradeInsHandler)
 			protected.DELETE("/trade-ins/:id", hb.DeleteTradeInHandler)
 			protected.GET("/trade-ins/:id/offers", hb.GetTradeInOffersHandler)
+			protected.POST("/trade-ins/:id/offers/:offerID/accept", hb.AcceptTradeInOfferHandler)
+
+			protected.GET("/transactions/purchases", hb.GetUserPurchasesHandler)
+			protected.GET("/transactions/sales", hb.GetUserSalesHandler)
+			protected.POST("/transactions/purchases", hb.RecordPurchaseHandler)
+
+			protected.POST("/payments/deposits", hb.InitiateDepositHandler)
+			protected.GET("/payments", hb.GetUserPaymentsHandler)
+			protected.GET("/payments/:id", hb.GetPaymentHandler)
+			protected.GET("/transactions/:id", hb.GetTransactionHandler)
+			protected.POST("/transactions/:id/status", hb.UpdateTransactionStatusHandler)
+			protected.POST("/transactions/:id/handover", hb.ConfirmHandoverHandler)
+
+			protected.PATCH("/listings/:id", hb.UpdateListingHandler)
+			protected.POST("/listings/:id/bids/:bidID/accept", hb.AcceptDealerBidHandler)
+			protected.POST("/listings/:id/bids/:bidID/counter", hb.CounterBidHandler)
+			protected.POST("/listings/:id/bids/:bidID/reject", hb.RejectBidHandler)
+
+			protected.POST("/listings/:id/media", hb.UploadListingMediaHandler)
+			protected.PUT("/listings/:id/media/order", hb.ReorderListingMediaHandler)
+			protected.PUT("/listings/:id/media/:mediaID/cover", hb.SetListingCoverHandler)
+			protected.PATCH("/listings/:id/media/:mediaID", hb.UpdateMediaCaptionHandler)
+			protected.DELETE("/listings/:id/media/:mediaID", hb.DeleteListingMediaHandler)
+
+			protected.GET("/saved-searches", hb.GetSavedSearchesHandler)
+			protected.POST("/saved-searches", hb.CreateSavedSearchHandler)
+			protected.PATCH("/saved-searches/:id", hb.UpdateSavedSearchHandler)
+			protected.DELETE("/saved-searches/:id", hb.DeleteSavedSearchHandler)
+
+			protected.GET("/watchlist", hb.GetWatchlistHandler)
+			protected.POST("/watchlist/:id", hb.WatchListingHandler)
+			protected.DELETE("/watchlist/:id", hb.UnwatchListingHandler)
+
+			protected.GET("/notifications", hb.GetNotificationsHandler)
+			protected.POST("/notifications/read-all", hb.MarkAllNotificationsReadHandler)
+			protected.POST("/notifications/:id/read", hb.MarkNotificationsReadHandler)
 		}
 	}
 }
 
 func RegisterPublicRoutes(r *gin.Engine, hb *handlers.HandlerBundle) {
 	r.GET("/api/listings", hb.GetListingsHandler)
+	r.GET("/api/listings/:id/price-history", hb.GetPriceHistoryHandler)
 	r.GET("/api/trade-ins", hb.GetPublicTradeInsHandler)
 	r.GET("/api/search", hb.SearchHandler)
+	r.GET("/api/search/suggest", hb.SearchSuggestionsHandler)
+	r.POST("/api/search/clicks", hb.RecordSearchClickHandler)
+	r.GET("/api/valuation", hb.GetValuationHandler)
+
+	// Delivery for the local and S3 storage backends
+	r.GET("/api/files/public/*publicID", hb.ServePublicFileHandler)
+	r.GET("/api/files/secure/*publicID", hb.ServeSecureFileHandler)
+
+	// Gateway results; each URL carries the payment's own signature
+	r.POST("/api/payments/callback/:paymentID", hb.PaymentCallbackHandler)
+}
+
+// RegisterAdminRoutes exposes back-office tools to holders of the admin API key.
+func RegisterAdminRoutes(r *gin.Engine, hb *handlers.HandlerBundle) {
+	admin := r.Group("/api/admin")
+	admin.Use(middleware.AdminKeyMiddleware(config.AppConfig.AdminAPIKey))
+	admin.Use(middleware.AuditActorMiddleware(models.ActorAdmin))
+	{
+		admin.GET("/vin-clusters", hb.GetSuspiciousVINClustersHandler)
+		admin.GET("/search/trends", hb.GetSearchTrendsHandler)
+		admin.GET("/listings/:id/events", hb.GetListingEventsHandler)
+		admin.PATCH("/listings/:id", hb.UpdateListingHandler)
+		admin.PATCH("/dealers/:id", hb.UpdateDealerProfileHandler)
+		admin.POST("/payouts/:id/complete", hb.CompletePayoutHandler)
+		admin.POST("/payouts/:id/fail", hb.FailPayoutHandler)
+	}
 }
 
 func RegisterRoutes(r *gin.Engine, hb *handlers.HandlerBundle) {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Authorization", "Content-Type", "X-Admin-Key"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -76,6 +174,7 @@ func RegisterRoutes(r *gin.Engine, hb *handlers.HandlerBundle) {
 	RegisterDealerRoutes(r, hb)
 	RegisterUserRoutes(r, hb)
 	RegisterPublicRoutes(r, hb)
+	RegisterAdminRoutes(r, hb)
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
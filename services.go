@@ -0,0 +1,125 @@
+package main
+
+import (
+	"time"
+
+	"carsawa/config"
+	autocompleteRepo "carsawa/database/repository/autocomplete"
+	ledgerRepo "carsawa/database/repository/ledger"
+	listingRepo "carsawa/database/repository/listing"
+	listingEventRepo "carsawa/database/repository/listingevent"
+	notificationsRepo "carsawa/database/repository/notifications"
+	paymentRepo "carsawa/database/repository/payment"
+	savedSearchRepo "carsawa/database/repository/savedsearch"
+	tradeInRepo "carsawa/database/repository/tradein"
+	transactionRepo "carsawa/database/repository/transaction"
+	watchlistRepo "carsawa/database/repository/watchlist"
+	"carsawa/services/autocomplete"
+	"carsawa/services/dealer"
+	"carsawa/services/ledger"
+	"carsawa/services/listing"
+	"carsawa/services/notification"
+	"carsawa/services/payments"
+	"carsawa/services/savedsearch"
+	"carsawa/services/storage"
+	"carsawa/services/transaction"
+	"carsawa/services/user"
+	"carsawa/services/valuation"
+	"carsawa/services/vin"
+	"carsawa/services/watchlist"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// newJobServices builds the services the background jobs run against, and
+// the listing service's own dependencies, from their repositories and config.
+func newJobServices(
+	db *mongo.Database,
+	users user.UserService,
+	dealers dealer.DealerService,
+	store storage.StorageService,
+	vins vin.VINDecoder,
+) (jobServices, error) {
+	cfg := config.AppConfig
+
+	listings := listingRepo.NewMongoListingsRepository(db)
+	notifSvc := notification.NewNotificationService(notificationsRepo.NewMongoNotificationRepository(db))
+
+	ledgerSvc := ledger.NewLedgerService(ledgerRepo.NewMongoLedgerRepo(db), notifSvc, ledger.Config{
+		MinPayout: cfg.PayoutMinAmount,
+	})
+	watchlistSvc := watchlist.NewWatchlistService(watchlistRepo.NewMongoWatchlistRepo(db), listings, notifSvc)
+	transactionSvc := transaction.NewTransactionService(
+		transactionRepo.NewMongoTransactionRepo(db),
+		listings,
+		tradeInRepo.NewMongoTradeInRepository(db),
+		notifSvc,
+		ledgerSvc,
+		watchlistSvc,
+		cfg.TransactionFeePercent,
+	)
+
+	gateway, err := payments.NewGateway(cfg.PaymentGateway, payments.DarajaConfig{
+		BaseURL:        cfg.MpesaBaseURL,
+		ConsumerKey:    cfg.MpesaConsumerKey,
+		ConsumerSecret: cfg.MpesaConsumerSecret,
+		ShortCode:      cfg.MpesaShortCode,
+		Passkey:        cfg.MpesaPasskey,
+		TillNumber:     cfg.MpesaTillNumber,
+	}, time.Duration(cfg.PaymentFakeCompleteSecs)*time.Second)
+	if err != nil {
+		return jobServices{}, err
+	}
+	signer, err := payments.NewCallbackSigner(cfg.PaymentCallbackURL, cfg.PaymentCallbackSecret)
+	if err != nil {
+		return jobServices{}, err
+	}
+	paymentSvc := payments.NewPaymentService(
+		paymentRepo.NewMongoPaymentRepo(db),
+		listings,
+		transactionSvc,
+		ledgerSvc,
+		gateway,
+		signer,
+		notifSvc,
+		payments.Config{
+			DepositPercent: cfg.DepositPercent,
+			MinDeposit:     cfg.DepositMinAmount,
+			PendingExpiry:  time.Duration(cfg.PaymentPendingExpiryMins) * time.Minute,
+		},
+	)
+
+	autocompleteSvc := autocomplete.NewAutocompleteService(autocompleteRepo.NewMongoAutocompleteRepo(db))
+	savedSearchSvc := savedsearch.NewSavedSearchService(savedSearchRepo.NewMongoSavedSearchRepo(db), notifSvc)
+	valuationSvc := valuation.NewValuationService(listings)
+
+	listingSvc := listing.NewListingService(
+		listings,
+		notifSvc,
+		users,
+		dealers,
+		store,
+		transactionSvc,
+		vins,
+		autocompleteSvc,
+		savedSearchSvc,
+		watchlistSvc,
+		valuationSvc,
+		listingEventRepo.NewMongoListingEventRepo(db),
+		listing.LifecycleConfig{
+			DealerLifetime: time.Duration(cfg.DealerListingLifetimeDays) * 24 * time.Hour,
+			UserLifetime:   time.Duration(cfg.UserListingLifetimeDays) * 24 * time.Hour,
+			ReminderLead:   time.Duration(cfg.ListingExpiryReminderDays) * 24 * time.Hour,
+			DraftTTL:       time.Duration(cfg.DraftPurgeDays) * 24 * time.Hour,
+		},
+	)
+
+	return jobServices{
+		Listing:   listingSvc,
+		Payments:  paymentSvc,
+		Ledger:    ledgerSvc,
+		Suggest:   autocompleteSvc,
+		Alerts:    savedSearchSvc,
+		Valuation: valuationSvc,
+	}, nil
+}
@@ -0,0 +1,230 @@
+package autocomplete
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	autocompleteRepo "carsawa/database/repository/autocomplete"
+	"carsawa/models"
+)
+
+// kindWeights rank makes over models over dealers when popularity is equal.
+var kindWeights = map[string]float64{
+	models.SuggestionMake:   1.0,
+	models.SuggestionModel:  0.8,
+	models.SuggestionDealer: 0.6,
+}
+
+// Score multipliers for weaker matches.
+const (
+	wordMatch  = 0.8 // Query starts a later word, e.g. "cruiser" for "Land Cruiser"
+	typoMatch  = 0.5 // Per edit
+	fuzzyAfter = 4   // Shorter queries are only prefix matched
+)
+
+type entry struct {
+	term   models.AutocompleteTerm
+	weight float64
+}
+
+// posting is one key an entry can be found under. whole keys start the
+// term's text; the rest start a later word or the dealer slug.
+type posting struct {
+	key   string
+	whole bool
+	e     *entry
+}
+
+// index is immutable once built; the service swaps in a new one on change.
+type index struct {
+	entries  map[string]*entry
+	postings []posting // Sorted by key
+	names    map[string][]*entry
+}
+
+func newIndex(terms []models.AutocompleteTerm) *index {
+	idx := &index{
+		entries: make(map[string]*entry, len(terms)),
+		names:   make(map[string][]*entry),
+	}
+	for _, t := range terms {
+		idx.add(t)
+	}
+	sort.Slice(idx.postings, func(i, j int) bool {
+		return idx.postings[i].key < idx.postings[j].key
+	})
+	return idx
+}
+
+// with returns a copy of idx holding terms as well.
+func (idx *index) with(terms []models.AutocompleteTerm) *index {
+	all := make([]models.AutocompleteTerm, 0, len(idx.entries)+len(terms))
+	for _, e := range idx.entries {
+		all = append(all, e.term)
+	}
+	for _, t := range terms {
+		if !idx.has(t.ID) {
+			all = append(all, t)
+		}
+	}
+	return newIndex(all)
+}
+
+func (idx *index) has(id string) bool {
+	_, ok := idx.entries[id]
+	return ok
+}
+
+func (idx *index) add(t models.AutocompleteTerm) {
+	name := normalize(t.Text)
+	if name == "" {
+		return
+	}
+	e := &entry{
+		term: t,
+		weight: kindWeights[t.Kind] +
+			0.5*math.Log1p(float64(t.Listings)) +
+			math.Log1p(float64(t.Searches)),
+	}
+	idx.entries[t.ID] = e
+	idx.names[name] = append(idx.names[name], e)
+
+	seen := map[string]bool{}
+	post := func(key string, whole bool) {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			idx.postings = append(idx.postings, posting{key: key, whole: whole, e: e})
+		}
+	}
+	post(name, true)
+	post(compact(name), true)
+	if t.Kind == models.SuggestionModel && t.Make != "" {
+		full := normalize(t.Make + " " + t.Text)
+		post(full, true)
+		post(compact(full), true)
+	}
+	words := strings.Fields(name)
+	for i := 1; i < len(words); i++ {
+		post(strings.Join(words[i:], " "), false)
+	}
+	if t.Slug != "" {
+		slug := normalize(t.Slug)
+		post(slug, false)
+		if slug != name {
+			idx.names[slug] = append(idx.names[slug], e)
+		}
+	}
+}
+
+func (idx *index) suggest(query string, limit int) []models.SearchSuggestion {
+	q := normalize(query)
+	if q == "" {
+		return nil
+	}
+
+	scores := map[*entry]float64{}
+	keep := func(e *entry, score float64) {
+		if score > scores[e] {
+			scores[e] = score
+		}
+	}
+	for _, key := range []string{q, compact(q)} {
+		i := sort.Search(len(idx.postings), func(i int) bool { return idx.postings[i].key >= key })
+		for ; i < len(idx.postings) && strings.HasPrefix(idx.postings[i].key, key); i++ {
+			p := idx.postings[i]
+			if p.whole {
+				keep(p.e, p.e.weight)
+			} else {
+				keep(p.e, p.e.weight*wordMatch)
+			}
+		}
+	}
+
+	// Typos only get a look in when nothing matches as typed.
+	if cq := []rune(compact(q)); len(scores) == 0 && len(cq) >= fuzzyAfter {
+		maxEdits := 1
+		if len(cq) >= 8 {
+			maxEdits = 2
+		}
+		for _, p := range idx.postings {
+			if strings.Contains(p.key, " ") {
+				continue
+			}
+			if d := prefixDistance(cq, []rune(p.key), maxEdits); d > 0 && d <= maxEdits {
+				keep(p.e, p.e.weight*math.Pow(typoMatch, float64(d)))
+			}
+		}
+	}
+
+	ranked := make([]*entry, 0, len(scores))
+	for e := range scores {
+		ranked = append(ranked, e)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i].term.Text < ranked[j].term.Text
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	out := make([]models.SearchSuggestion, len(ranked))
+	for i, e := range ranked {
+		out[i] = models.SearchSuggestion{
+			Text:  e.term.Text,
+			Type:  e.term.Kind,
+			Make:  e.term.Make,
+			Slug:  e.term.Slug,
+			Score: math.Round(scores[e]/scores[ranked[0]]*100) / 100,
+		}
+	}
+	return out
+}
+
+// match returns the IDs of the terms a search names, in its text or its
+// make and model filters.
+func (idx *index) match(query string, filter models.ListingFilter) []string {
+	seen := map[string]bool{}
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] && idx.has(id) {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	words := strings.Fields(normalize(query))
+	for n := 1; n <= 3; n++ {
+		for i := 0; i+n <= len(words); i++ {
+			phrase := strings.Join(words[i:i+n], " ")
+			for _, key := range []string{phrase, compact(phrase)} {
+				for _, e := range idx.names[key] {
+					add(e.term.ID)
+				}
+			}
+		}
+	}
+	if filter.Make != "" {
+		add(autocompleteRepo.TermID(models.SuggestionMake, filter.Make))
+		if filter.Model != "" {
+			add(autocompleteRepo.TermID(models.SuggestionModel, filter.Make, filter.Model))
+		}
+	}
+	return ids
+}
+
+// normalize lower-cases s and reduces everything between letters and digits
+// to single spaces, so "Mercedes-Benz" and "mercedes benz" meet.
+func normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func compact(s string) string {
+	return strings.ReplaceAll(s, " ", "")
+}
@@ -0,0 +1,43 @@
+package autocomplete
+
+import (
+	"context"
+	"sync"
+
+	autocompleteRepo "carsawa/database/repository/autocomplete"
+	"carsawa/models"
+)
+
+const (
+	defaultSuggestions = 10
+	maxSuggestions     = 20
+)
+
+// AutocompleteService suggests makes, models and dealers as a buyer types.
+// Lookups are served from an in-memory index built from the terms
+// collection; Refresh reloads it and listings add to it as they are saved.
+type AutocompleteService interface {
+	Suggest(query string, limit int) []models.SearchSuggestion
+	// IndexListing makes a listing's make and model suggestible straight away.
+	IndexListing(ctx context.Context, lst *models.Listing)
+	// RecordSearch counts a search towards the popularity of the terms it names.
+	RecordSearch(ctx context.Context, query string, filter models.ListingFilter)
+	// SyncTerms resyncs the stored terms from listings and dealers.
+	SyncTerms(ctx context.Context) error
+	// Refresh reloads this replica's index from the stored terms.
+	Refresh(ctx context.Context) error
+}
+
+type autocompleteService struct {
+	repo autocompleteRepo.AutocompleteRepository
+
+	mu  sync.RWMutex
+	idx *index
+}
+
+func NewAutocompleteService(repo autocompleteRepo.AutocompleteRepository) AutocompleteService {
+	return &autocompleteService{
+		repo: repo,
+		idx:  newIndex(nil),
+	}
+}
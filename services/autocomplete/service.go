@@ -0,0 +1,106 @@
+package autocomplete
+
+import (
+	"context"
+	"time"
+
+	autocompleteRepo "carsawa/database/repository/autocomplete"
+	"carsawa/models"
+	"carsawa/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+func (s *autocompleteService) index() *index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.idx
+}
+
+func (s *autocompleteService) Suggest(query string, limit int) []models.SearchSuggestion {
+	if limit <= 0 {
+		limit = defaultSuggestions
+	}
+	if limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+	return s.index().suggest(query, limit)
+}
+
+func (s *autocompleteService) IndexListing(ctx context.Context, lst *models.Listing) {
+	cd := lst.CarDetails
+	idx := s.index()
+	if idx.has(autocompleteRepo.TermID(models.SuggestionMake, cd.Make)) &&
+		(cd.Model == "" || idx.has(autocompleteRepo.TermID(models.SuggestionModel, cd.Make, cd.Model))) {
+		return
+	}
+
+	terms, err := s.repo.AddListingTerms(ctx, cd.Make, cd.Model)
+	if err != nil {
+		utils.GetLogger().Warn("failed to index listing for autocomplete",
+			zap.String("listingID", lst.ID.Hex()), zap.Error(err))
+		return
+	}
+	if len(terms) == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.idx = s.idx.with(terms)
+	s.mu.Unlock()
+}
+
+func (s *autocompleteService) RecordSearch(ctx context.Context, query string, filter models.ListingFilter) {
+	ids := s.index().match(query, filter)
+	if err := s.repo.IncrementSearches(ctx, ids); err != nil {
+		utils.GetLogger().Warn("failed to record autocomplete search", zap.Error(err))
+	}
+}
+
+func (s *autocompleteService) SyncTerms(ctx context.Context) error {
+	if err := s.repo.SyncListingTerms(ctx); err != nil {
+		return err
+	}
+	return s.repo.SyncDealerTerms(ctx)
+}
+
+func (s *autocompleteService) Refresh(ctx context.Context) error {
+	terms, err := s.repo.GetTerms(ctx)
+	if err != nil {
+		return err
+	}
+
+	idx := newIndex(terms)
+	s.mu.Lock()
+	s.idx = idx
+	s.mu.Unlock()
+	return nil
+}
+
+// RunRefresher loads the index straight away and then refreshes it every
+// interval until ctx is cancelled. Each replica keeps its own index, so
+// terms first seen by another replica show up here on the next refresh.
+// The resync from listings and dealers scans both collections and runs on
+// one replica at a time.
+func RunRefresher(ctx context.Context, svc AutocompleteService, rdb *redis.Client, interval time.Duration) {
+	go utils.RunLockedJob(ctx, rdb, "autocomplete-sync", interval, svc.SyncTerms)
+
+	refresh := func() {
+		if err := svc.Refresh(ctx); err != nil {
+			utils.GetLogger().Error("autocomplete refresh failed", zap.Error(err))
+		}
+	}
+	refresh()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
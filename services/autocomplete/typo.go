@@ -0,0 +1,50 @@
+package autocomplete
+
+// prefixDistance is the fewest edits turning q into some prefix of key, so a
+// half-typed "toyt" is one edit from "Toyota". Returns maxEdits+1 when the
+// key is further away than that.
+func prefixDistance(q, key []rune, maxEdits int) int {
+	best := maxEdits + 1
+	for n := len(q) - maxEdits; n <= len(q)+maxEdits; n++ {
+		if n < 1 || n > len(key) {
+			continue
+		}
+		if d := editDistance(q, key[:n]); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// editDistance counts insertions, deletions, substitutions and swaps of
+// adjacent letters (optimal string alignment).
+func editDistance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := rows[i-1][j-1] + cost
+			if rows[i-1][j]+1 < d {
+				d = rows[i-1][j] + 1
+			}
+			if rows[i][j-1]+1 < d {
+				d = rows[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && rows[i-2][j-2]+1 < d {
+				d = rows[i-2][j-2] + 1
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
@@ -2,24 +2,38 @@ package dealer
 
 import (
 	"carsawa/models"
+	"carsawa/utils"
 	"context"
 	"fmt"
-	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func (s *dealerService) UpdateDealer(ctx context.Context, id string, updates bson.M) (*models.Dealer, error) {
-	// Protect immutable fields
-	delete(updates, "_id")
-	delete(updates, "email")
-	delete(updates, "createdAt")
-	delete(updates, "slug") // Slug should be updated through separate endpoint if needed
-
-	// Set updatedAt
-	updates["updatedAt"] = time.Now()
+// UpdateDealer applies a merge patch from the dealer themself or an admin.
+// Each role has its own allowlist of fields; the first field the caller may
+// not write, or whose value is invalid, is returned as a *models.FieldError.
+func (s *dealerService) UpdateDealer(ctx context.Context, id string, patch models.DealerPatch) (*models.Dealer, error) {
+	actor := utils.AuditActorFrom(ctx)
+	allowed := ownerDealerFields
+	switch actor.Type {
+	case models.ActorAdmin:
+		allowed = adminDealerFields
+	case models.ActorDealer:
+		if actor.ID != id {
+			return nil, ErrNotDealerOwner
+		}
+	default:
+		return nil, ErrNotDealerOwner
+	}
+	if err := models.CheckPatchFields(models.PatchedFields(&patch), allowed); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.UpdateDealer(id, bson.M{"$set": updates}); err != nil {
+	update, err := buildDealerUpdate(patch)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateDealer(id, update); err != nil {
 		return nil, fmt.Errorf("failed to update dealer: %w", err)
 	}
 
@@ -60,6 +74,28 @@ func (s *dealerService) ListDealers(ctx context.Context) ([]models.Dealer, error
 	return dealers, nil
 }
 
+// ListDealersNear lists dealers nearest first, with Proximity in kilometres.
+func (s *dealerService) ListDealersNear(ctx context.Context, near models.GeoQuery, limit int) ([]models.DealerDTO, error) {
+	isFullAccess := getAccessLevelFromContext(ctx)
+	projection := buildDealerProjection(isFullAccess)
+
+	nearby, err := s.repo.GetDealersNear(near, projection, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nearby dealers: %w", err)
+	}
+	dtos := make([]models.DealerDTO, 0, len(nearby))
+	for _, d := range nearby {
+		dtos = append(dtos, models.DealerDTO{
+			ID:               d.ID,
+			DealerProfile:    d.Profile,
+			ServiceCatalogue: d.Store.ServiceCatalog,
+			LocationGeo:      d.Profile.Location.GeoPoint,
+			Proximity:        d.DistanceKm,
+		})
+	}
+	return dtos, nil
+}
+
 // Helper functions
 func getAccessLevelFromContext(ctx context.Context) bool {
 	if val := ctx.Value("isDealerFullAccess"); val != nil {
@@ -7,6 +7,7 @@ var (
 	ErrDealerExists      = fmt.Errorf("dealer already exists")
 	ErrInvalidDealerData = fmt.Errorf("invalid dealer data")
 	ErrSlugExists        = fmt.Errorf("dealer slug already in use")
+	ErrNotDealerOwner    = fmt.Errorf("only the dealer or an admin can update this account")
 )
 
 type DealerValidationError struct {
@@ -9,16 +9,15 @@ import (
 	"carsawa/services/notification"
 	"carsawa/utils/email"
 	"carsawa/utils/token"
-
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 type DealerService interface {
 	// Core dealer operations
-	UpdateDealer(ctx context.Context, id string, updates bson.M) (*models.Dealer, error)
+	UpdateDealer(ctx context.Context, id string, patch models.DealerPatch) (*models.Dealer, error)
 	GetDealer(ctx context.Context, id string) (*models.Dealer, error)
 	GetDealerByEmail(ctx context.Context, email string) (*models.Dealer, error)
 	ListDealers(ctx context.Context) ([]models.Dealer, error)
+	ListDealersNear(ctx context.Context, near models.GeoQuery, limit int) ([]models.DealerDTO, error)
 	DeleteDealer(ctx context.Context, id string) error
 
 	// Registration flow methods
@@ -0,0 +1,212 @@
+package dealer
+
+import (
+	"carsawa/models"
+	"carsawa/utils"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ownerDealerFields are what a dealer may patch on their own account.
+var ownerDealerFields = []string{"profile", "store"}
+
+// adminDealerFields add the KYC outcome, which only admins record.
+var adminDealerFields = []string{"profile", "store", "verification"}
+
+const maxDealerNameLen = 100
+
+var (
+	phonePattern    = regexp.MustCompile(`^\+?[0-9]{7,14}$`)
+	hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+	bannerPositions      = []string{"top", "middle", "bottom"}
+	verificationLevels   = []string{"basic", "advanced"}
+	verificationStatuses = []string{"pending", "verified", "rejected"}
+)
+
+// dealerUpdate collects a patch as Mongo $set and $unset documents keyed by
+// dotted path.
+type dealerUpdate struct {
+	set   bson.M
+	unset bson.M
+}
+
+// put stores a patched value under path, or unsets it for null.
+func put[T any](u *dealerUpdate, path string, p models.Patch[T]) {
+	if !p.Set {
+		return
+	}
+	if p.Null {
+		u.unset[path] = ""
+		return
+	}
+	u.set[path] = p.Value
+}
+
+func (u *dealerUpdate) doc() bson.M {
+	doc := bson.M{"$set": u.set}
+	if len(u.unset) > 0 {
+		doc["$unset"] = u.unset
+	}
+	return doc
+}
+
+// buildDealerUpdate validates a dealer patch field by field and turns it
+// into an update document.
+func buildDealerUpdate(p models.DealerPatch) (bson.M, error) {
+	u := &dealerUpdate{set: bson.M{"updatedAt": time.Now()}, unset: bson.M{}}
+	if p.Profile != nil {
+		if err := patchProfile(u, p.Profile); err != nil {
+			return nil, err
+		}
+	}
+	if p.Store != nil {
+		if err := patchStore(u, p.Store); err != nil {
+			return nil, err
+		}
+	}
+	if p.Verification != nil {
+		if err := patchVerification(u, p.Verification); err != nil {
+			return nil, err
+		}
+	}
+	return u.doc(), nil
+}
+
+func patchProfile(u *dealerUpdate, p *models.DealerProfilePatch) error {
+	if p.DealerName.Set {
+		p.DealerName.Value = strings.TrimSpace(p.DealerName.Value)
+		switch {
+		case p.DealerName.Null || p.DealerName.Value == "":
+			return models.InvalidField("profile.dealerName", "is required")
+		case len(p.DealerName.Value) > maxDealerNameLen:
+			return models.InvalidField("profile.dealerName", "must be at most %d characters", maxDealerNameLen)
+		}
+		put(u, "profile.dealerName", p.DealerName)
+	}
+
+	if c := p.Contact; c != nil {
+		if c.Phone.Set {
+			if c.Phone.Null {
+				return models.InvalidField("profile.contact.phone", "is required")
+			}
+			c.Phone.Value = utils.NormalizePhoneNumber(c.Phone.Value)
+			if !phonePattern.MatchString(c.Phone.Value) {
+				return models.InvalidField("profile.contact.phone", "%q is not a phone number", c.Phone.Value)
+			}
+			put(u, "profile.contact.phone", c.Phone)
+		}
+		if c.WhatsApp.Set && !c.WhatsApp.Null {
+			c.WhatsApp.Value = utils.NormalizePhoneNumber(c.WhatsApp.Value)
+			if !phonePattern.MatchString(c.WhatsApp.Value) {
+				return models.InvalidField("profile.contact.whatsapp", "%q is not a phone number", c.WhatsApp.Value)
+			}
+		}
+		put(u, "profile.contact.whatsapp", c.WhatsApp)
+	}
+
+	if p.Location.Set {
+		loc := &p.Location.Value
+		loc.Address = strings.TrimSpace(loc.Address)
+		loc.City = strings.TrimSpace(loc.City)
+		switch {
+		case p.Location.Null:
+			return models.InvalidField("profile.location", "is required")
+		case loc.Address == "":
+			return models.InvalidField("profile.location.address", "is required")
+		case loc.City == "":
+			return models.InvalidField("profile.location.city", "is required")
+		case !loc.GeoPoint.IsZero() && !loc.GeoPoint.Valid():
+			return models.InvalidField("profile.location.geoPoint.coordinates", "must be [longitude, latitude]")
+		}
+		if !loc.GeoPoint.IsZero() {
+			loc.GeoPoint.Type = "Point"
+		}
+		put(u, "profile.location", p.Location)
+	}
+	return nil
+}
+
+func patchStore(u *dealerUpdate, p *models.StorePatch) error {
+	for i := range p.Banners.Value {
+		b := &p.Banners.Value[i]
+		switch {
+		case b.ImageURL == "":
+			return models.InvalidField(indexPath("store.banners", i, "imageUrl"), "is required")
+		case b.Position != "" && !slices.Contains(bannerPositions, b.Position):
+			return models.InvalidField(indexPath("store.banners", i, "position"), "%q is not one of %v", b.Position, bannerPositions)
+		}
+		if b.ID.IsZero() {
+			b.ID = primitive.NewObjectID()
+		}
+	}
+	put(u, "store.banners", p.Banners)
+
+	for i, svc := range p.ServiceCatalog.Value.Services {
+		if strings.TrimSpace(svc.Name) == "" {
+			return models.InvalidField(indexPath("store.serviceCatalog.services", i, "name"), "is required")
+		}
+	}
+	put(u, "store.serviceCatalog", p.ServiceCatalog)
+
+	for i := range p.Promotions.Value {
+		promo := &p.Promotions.Value[i]
+		switch {
+		case strings.TrimSpace(promo.Title) == "":
+			return models.InvalidField(indexPath("store.promotions", i, "title"), "is required")
+		case !promo.DisplayTo.IsZero() && promo.DisplayTo.Before(promo.DisplayFrom):
+			return models.InvalidField(indexPath("store.promotions", i, "displayTo"), "must not be before displayFrom")
+		}
+		if promo.ID.IsZero() {
+			promo.ID = primitive.NewObjectID()
+		}
+	}
+	put(u, "store.promotions", p.Promotions)
+
+	if b := p.Branding; b != nil {
+		for _, c := range []struct {
+			path  string
+			color models.Patch[string]
+		}{
+			{"store.branding.primaryColor", b.PrimaryColor},
+			{"store.branding.secondaryColor", b.SecondaryColor},
+		} {
+			if c.color.Set && !c.color.Null && !hexColorPattern.MatchString(c.color.Value) {
+				return models.InvalidField(c.path, "%q is not a hex colour", c.color.Value)
+			}
+			put(u, c.path, c.color)
+		}
+		put(u, "store.branding.fontFamily", b.FontFamily)
+	}
+	return nil
+}
+
+func patchVerification(u *dealerUpdate, p *models.VerificationPatch) error {
+	if p.Level.Set {
+		if p.Level.Null || !slices.Contains(verificationLevels, p.Level.Value) {
+			return models.InvalidField("verification.level", "must be one of %v", verificationLevels)
+		}
+		put(u, "verification.level", p.Level)
+	}
+	if p.Status.Set {
+		if p.Status.Null || !slices.Contains(verificationStatuses, p.Status.Value) {
+			return models.InvalidField("verification.status", "must be one of %v", verificationStatuses)
+		}
+		put(u, "verification.status", p.Status)
+		if p.Status.Value == "verified" {
+			u.set["verification.verifiedAt"] = time.Now()
+		}
+	}
+	put(u, "verification.documents", p.Documents)
+	return nil
+}
+
+func indexPath(path string, i int, field string) string {
+	return path + "[" + strconv.Itoa(i) + "]." + field
+}
@@ -0,0 +1,60 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+
+	ledgerRepo "carsawa/database/repository/ledger"
+	"carsawa/models"
+	"carsawa/services/notification"
+)
+
+var ErrNotDealerSale = errors.New("escrow can only be released to a dealer")
+
+// LedgerService keeps the double-entry books for money passing through the
+// platform: deposits in, escrow on each sale, fees, and dealer payouts.
+type LedgerService interface {
+	// Postings; replaying the same event is a no-op
+	RecordFundsReceived(ctx context.Context, p *models.Payment) error
+	HoldInEscrow(ctx context.Context, p *models.Payment, tx *models.Transaction) error
+	ReleaseEscrow(ctx context.Context, tx *models.Transaction) error
+	RefundEscrow(ctx context.Context, tx *models.Transaction) error
+
+	// Dealer views
+	GetDealerBalance(ctx context.Context, dealerID string) (*models.DealerBalance, error)
+	GetDealerPayouts(ctx context.Context, dealerID string, pagination models.Pagination) ([]models.Payout, error)
+	GetDealerStatement(ctx context.Context, dealerID string, pagination models.Pagination) ([]models.JournalEntry, error)
+
+	// Payouts
+	RunPayoutBatch(ctx context.Context) (*PayoutBatch, error)
+	CompletePayout(ctx context.Context, payoutID string, paid bool, reference string) (*models.Payout, error)
+}
+
+// PayoutBatch reports one run of the payout job.
+type PayoutBatch struct {
+	BatchID string          `json:"batchId"`
+	Payouts []models.Payout `json:"payouts"`
+	Failed  int             `json:"failed"`
+}
+
+type Config struct {
+	MinPayout float64 // Dealers owed less than this wait for the next batch
+}
+
+type ledgerService struct {
+	repo     ledgerRepo.LedgerRepository
+	notifier notification.NotificationService
+	cfg      Config
+}
+
+func NewLedgerService(
+	repo ledgerRepo.LedgerRepository,
+	notifSvc notification.NotificationService,
+	cfg Config,
+) LedgerService {
+	return &ledgerService{
+		repo:     repo,
+		notifier: notifSvc,
+		cfg:      cfg,
+	}
+}
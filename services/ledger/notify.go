@@ -0,0 +1,22 @@
+package ledger
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *ledgerService) notifyDealer(
+	ctx context.Context,
+	dealerID primitive.ObjectID,
+	ntype models.NotificationType,
+	title, body string,
+	data map[string]interface{},
+) {
+	if err := s.notifier.CreateDealerNotification(ctx, dealerID.Hex(), ntype, title, body, data); err != nil {
+		// log error, but don’t fail business logic
+		fmt.Printf("notifyDealer error: %v\n", err)
+	}
+}
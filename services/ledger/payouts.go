@@ -0,0 +1,135 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ledgerRepo "carsawa/database/repository/ledger"
+	"carsawa/models"
+	"carsawa/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const payoutBatchSize = 200
+
+// GetDealerBalance reports what the dealer is owed, what is still held in
+// escrow on their sales, and what has been paid out.
+func (s *ledgerService) GetDealerBalance(ctx context.Context, dealerHex string) (*models.DealerBalance, error) {
+	dealerID, err := primitive.ObjectIDFromHex(dealerHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dealer ID: %w", err)
+	}
+
+	var available int64
+	acct, err := s.repo.GetAccount(ctx, models.LedgerAccountID(models.LedgerAccountDealerPayable, dealerID))
+	switch {
+	case err == nil:
+		available = -acct.Balance
+	case !errors.Is(err, ledgerRepo.ErrAccountNotFound):
+		return nil, err
+	}
+	inEscrow, err := s.repo.SumEscrowForDealer(ctx, dealerID)
+	if err != nil {
+		return nil, err
+	}
+	paidOut, err := s.repo.SumPaidOut(ctx, dealerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DealerBalance{
+		DealerID:  dealerHex,
+		Currency:  models.CurrencyKES,
+		Available: fromMinor(available),
+		InEscrow:  fromMinor(inEscrow),
+		PaidOut:   paidOut,
+	}, nil
+}
+
+func (s *ledgerService) GetDealerPayouts(ctx context.Context, dealerHex string, pagination models.Pagination) ([]models.Payout, error) {
+	dealerID, err := primitive.ObjectIDFromHex(dealerHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dealer ID: %w", err)
+	}
+	return s.repo.GetPayouts(ctx, dealerID, pagination)
+}
+
+// GetDealerStatement lists the journal entries touching the dealer's money, newest first.
+func (s *ledgerService) GetDealerStatement(ctx context.Context, dealerHex string, pagination models.Pagination) ([]models.JournalEntry, error) {
+	dealerID, err := primitive.ObjectIDFromHex(dealerHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dealer ID: %w", err)
+	}
+	return s.repo.GetEntries(ctx, ledgerRepo.JournalFilter{DealerID: &dealerID}, pagination)
+}
+
+// RunPayoutBatch schedules a payout of the full payable balance of every
+// dealer owed at least the minimum. Each payout is its own session, so one
+// failure does not hold up the rest of the batch.
+func (s *ledgerService) RunPayoutBatch(ctx context.Context) (*PayoutBatch, error) {
+	batch := &PayoutBatch{BatchID: time.Now().UTC().Format("20060102T150405Z")}
+	minAmount := toMinor(s.cfg.MinPayout)
+
+	accounts, err := s.repo.GetPayableAccounts(ctx, minAmount, payoutBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, acct := range accounts {
+		if acct.OwnerID == nil {
+			continue
+		}
+		payout, err := s.repo.SchedulePayout(ctx, *acct.OwnerID, batch.BatchID, minAmount)
+		if errors.Is(err, ledgerRepo.ErrBelowMinimum) {
+			// Balance moved since the account was listed.
+			continue
+		}
+		if err != nil {
+			batch.Failed++
+			utils.GetLogger().Error("RunPayoutBatch: failed to schedule payout",
+				zap.String("dealerID", acct.OwnerID.Hex()), zap.Error(err))
+			continue
+		}
+		batch.Payouts = append(batch.Payouts, *payout)
+
+		s.notifyDealer(ctx, payout.DealerID, models.NotificationTypePayoutScheduled, "Payout On Its Way",
+			fmt.Sprintf("A payout of %s %.2f has been scheduled to your account.", payout.Currency, payout.Amount),
+			map[string]interface{}{"payoutID": payout.ID.Hex(), "amount": payout.Amount})
+	}
+	return batch, nil
+}
+
+// CompletePayout records whether the transfer went through; failed transfers
+// go back to the dealer's available balance for the next batch.
+func (s *ledgerService) CompletePayout(ctx context.Context, payoutID string, paid bool, reference string) (*models.Payout, error) {
+	payout, err := s.repo.CompletePayout(ctx, payoutID, paid, reference)
+	if err != nil {
+		return nil, err
+	}
+	if !paid {
+		s.notifyDealer(ctx, payout.DealerID, models.NotificationTypePayoutFailed, "Payout Failed",
+			fmt.Sprintf("Your payout of %s %.2f could not be sent and will be retried in the next batch.", payout.Currency, payout.Amount),
+			map[string]interface{}{"payoutID": payout.ID.Hex(), "amount": payout.Amount})
+	}
+	return payout, nil
+}
+
+// RunPayoutScheduler runs a payout batch every interval until ctx is
+// cancelled, on one replica at a time.
+func RunPayoutScheduler(ctx context.Context, svc LedgerService, rdb *redis.Client, interval time.Duration) {
+	utils.RunLockedJob(ctx, rdb, "payout-batch", interval, func(ctx context.Context) error {
+		batch, err := svc.RunPayoutBatch(ctx)
+		if err != nil {
+			return err
+		}
+		utils.GetLogger().Info("payout batch finished",
+			zap.String("batchID", batch.BatchID),
+			zap.Int("scheduled", len(batch.Payouts)),
+			zap.Int("failed", batch.Failed))
+		return nil
+	})
+}
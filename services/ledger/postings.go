@@ -0,0 +1,177 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	ledgerRepo "carsawa/database/repository/ledger"
+	"carsawa/models"
+)
+
+// toMinor converts shillings to the cents the ledger counts in.
+func toMinor(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromMinor(amount int64) float64 {
+	return float64(amount) / 100
+}
+
+// RecordFundsReceived books money arriving at the gateway as owed to the
+// payer until it is applied to a sale.
+func (s *ledgerService) RecordFundsReceived(ctx context.Context, p *models.Payment) error {
+	amount := toMinor(p.Amount)
+	if amount <= 0 {
+		return errors.New("payment amount must be positive")
+	}
+	buyer := models.LedgerAccountID(models.LedgerAccountBuyer, p.Payer.ID)
+	return s.postOnce(ctx, func(ledgerRepo.BalanceFunc) (*models.JournalEntry, error) {
+		return &models.JournalEntry{
+			Kind:      models.JournalFundsReceived,
+			Reference: "funds:" + p.ID.Hex(),
+			PaymentID: &p.ID,
+			Currency:  p.Currency,
+			Memo:      fmt.Sprintf("%s payment %s", p.Gateway, p.Receipt),
+			Lines: []models.JournalLine{
+				{Account: models.GatewayAccount, Debit: amount},
+				{Account: buyer, Credit: amount},
+			},
+		}, nil
+	})
+}
+
+// HoldInEscrow moves a payer's deposit into the escrow account of the sale
+// it secures.
+func (s *ledgerService) HoldInEscrow(ctx context.Context, p *models.Payment, tx *models.Transaction) error {
+	amount := toMinor(p.Amount)
+	if amount <= 0 {
+		return errors.New("payment amount must be positive")
+	}
+	entry := &models.JournalEntry{
+		Kind:          models.JournalEscrowHold,
+		Reference:     "hold:" + p.ID.Hex(),
+		TransactionID: &tx.ID,
+		PaymentID:     &p.ID,
+		Currency:      p.Currency,
+		Lines: []models.JournalLine{
+			{Account: models.LedgerAccountID(models.LedgerAccountBuyer, p.Payer.ID), Debit: amount},
+			{Account: models.LedgerAccountID(models.LedgerAccountEscrow, tx.ID), Credit: amount},
+		},
+	}
+	if tx.Seller.Type == models.PartyTypeDealer {
+		entry.DealerID = &tx.Seller.ID
+	}
+	return s.postOnce(ctx, func(ledgerRepo.BalanceFunc) (*models.JournalEntry, error) {
+		return entry, nil
+	})
+}
+
+// ReleaseEscrow pays out everything held for a sale: the platform fee first,
+// the rest to the dealer's payable.
+func (s *ledgerService) ReleaseEscrow(ctx context.Context, tx *models.Transaction) error {
+	if tx.Seller.Type != models.PartyTypeDealer {
+		return ErrNotDealerSale
+	}
+	escrow := models.LedgerAccountID(models.LedgerAccountEscrow, tx.ID)
+	fee := toMinor(sellerFees(tx))
+
+	return s.postOnce(ctx, func(balance ledgerRepo.BalanceFunc) (*models.JournalEntry, error) {
+		bal, err := balance(escrow)
+		if err != nil {
+			return nil, err
+		}
+		held := -bal
+		if held <= 0 {
+			return nil, nil
+		}
+
+		lines := []models.JournalLine{{Account: escrow, Debit: held}}
+		f := fee
+		if f > held {
+			f = held
+		}
+		if f > 0 {
+			lines = append(lines, models.JournalLine{Account: models.PlatformFeesAccount, Credit: f})
+			held -= f
+		}
+		if held > 0 {
+			lines = append(lines, models.JournalLine{
+				Account: models.LedgerAccountID(models.LedgerAccountDealerPayable, tx.Seller.ID),
+				Credit:  held,
+			})
+		}
+		return &models.JournalEntry{
+			Kind:          models.JournalEscrowRelease,
+			Reference:     escrowSettleRef(tx),
+			TransactionID: &tx.ID,
+			DealerID:      &tx.Seller.ID,
+			Currency:      models.CurrencyKES,
+			Memo:          "Handover confirmed",
+			Lines:         lines,
+		}, nil
+	})
+}
+
+// RefundEscrow returns everything held for a sale to the buyer's account,
+// from where it is refunded.
+func (s *ledgerService) RefundEscrow(ctx context.Context, tx *models.Transaction) error {
+	if tx.Buyer == nil {
+		return nil
+	}
+	escrow := models.LedgerAccountID(models.LedgerAccountEscrow, tx.ID)
+	buyer := models.LedgerAccountID(models.LedgerAccountBuyer, tx.Buyer.ID)
+
+	return s.postOnce(ctx, func(balance ledgerRepo.BalanceFunc) (*models.JournalEntry, error) {
+		bal, err := balance(escrow)
+		if err != nil {
+			return nil, err
+		}
+		held := -bal
+		if held <= 0 {
+			return nil, nil
+		}
+		entry := &models.JournalEntry{
+			Kind:          models.JournalEscrowRefund,
+			Reference:     escrowSettleRef(tx),
+			TransactionID: &tx.ID,
+			Currency:      models.CurrencyKES,
+			Memo:          "Sale refunded",
+			Lines: []models.JournalLine{
+				{Account: escrow, Debit: held},
+				{Account: buyer, Credit: held},
+			},
+		}
+		if tx.Seller.Type == models.PartyTypeDealer {
+			entry.DealerID = &tx.Seller.ID
+		}
+		return entry, nil
+	})
+}
+
+// escrowSettleRef is shared by release and refund so a sale's escrow can only
+// ever be settled one way.
+func escrowSettleRef(tx *models.Transaction) string {
+	return "escrow_settle:" + tx.ID.Hex()
+}
+
+// sellerFees totals the fees the seller pays on a transaction.
+func sellerFees(tx *models.Transaction) float64 {
+	var total float64
+	for _, f := range tx.Fees {
+		if f.Payer == tx.Seller.Type {
+			total += f.Amount
+		}
+	}
+	return total
+}
+
+// postOnce posts an entry, treating an already-posted reference as success.
+func (s *ledgerService) postOnce(ctx context.Context, build ledgerRepo.EntryBuilder) error {
+	_, err := s.repo.PostEntry(ctx, build)
+	if errors.Is(err, ledgerRepo.ErrDuplicateEntry) {
+		return nil
+	}
+	return err
+}
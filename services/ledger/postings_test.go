@@ -0,0 +1,173 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ledgerRepo "carsawa/database/repository/ledger"
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memRepo posts entries to in-memory balances; the other repository methods
+// are not used by the postings under test.
+type memRepo struct {
+	ledgerRepo.LedgerRepository
+	balances map[string]int64
+	refs     map[string]bool
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{balances: map[string]int64{}, refs: map[string]bool{}}
+}
+
+func (r *memRepo) PostEntry(_ context.Context, build ledgerRepo.EntryBuilder) (*models.JournalEntry, error) {
+	entry, err := build(func(id string) (int64, error) { return r.balances[id], nil })
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	if err := ledgerRepo.ValidateEntry(entry); err != nil {
+		return nil, err
+	}
+	if r.refs[entry.Reference] {
+		return nil, ledgerRepo.ErrDuplicateEntry
+	}
+	r.refs[entry.Reference] = true
+	for _, l := range entry.Lines {
+		r.balances[l.Account] += l.Debit - l.Credit
+	}
+	return entry, nil
+}
+
+func TestValidateEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []models.JournalLine
+		ok    bool
+	}{
+		{name: "balanced", lines: []models.JournalLine{{Account: "a", Debit: 100}, {Account: "b", Credit: 100}}, ok: true},
+		{name: "split credit", lines: []models.JournalLine{{Account: "a", Debit: 100}, {Account: "b", Credit: 30}, {Account: "c", Credit: 70}}, ok: true},
+		{name: "single line", lines: []models.JournalLine{{Account: "a", Debit: 100}}},
+		{name: "does not net to zero", lines: []models.JournalLine{{Account: "a", Debit: 100}, {Account: "b", Credit: 99}}},
+		{name: "both sides on a line", lines: []models.JournalLine{{Account: "a", Debit: 100, Credit: 100}, {Account: "b", Debit: 1}, {Account: "c", Credit: 1}}},
+		{name: "empty line", lines: []models.JournalLine{{Account: "a"}, {Account: "b", Debit: 1}, {Account: "c", Credit: 1}}},
+		{name: "negative side", lines: []models.JournalLine{{Account: "a", Debit: -100}, {Account: "b", Credit: -100}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ledgerRepo.ValidateEntry(&models.JournalEntry{Lines: tt.lines})
+			if tt.ok && err != nil {
+				t.Fatalf("ValidateEntry() error = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, ledgerRepo.ErrUnbalancedEntry) {
+				t.Fatalf("ValidateEntry() error = %v, want %v", err, ledgerRepo.ErrUnbalancedEntry)
+			}
+		})
+	}
+}
+
+func TestEscrowBalances(t *testing.T) {
+	buyerID := primitive.NewObjectID()
+	dealerID := primitive.NewObjectID()
+	tx := &models.Transaction{
+		ID:     primitive.NewObjectID(),
+		Buyer:  &models.TransactionParty{ID: buyerID, Type: models.PartyTypeUser},
+		Seller: models.TransactionParty{ID: dealerID, Type: models.PartyTypeDealer},
+		Fees:   []models.TransactionFee{{Name: "platform", Amount: 1500, Payer: models.PartyTypeDealer}},
+	}
+	payment := &models.Payment{
+		ID:       primitive.NewObjectID(),
+		Payer:    *tx.Buyer,
+		Amount:   50000,
+		Currency: models.CurrencyKES,
+	}
+
+	buyer := models.LedgerAccountID(models.LedgerAccountBuyer, buyerID)
+	escrow := models.LedgerAccountID(models.LedgerAccountEscrow, tx.ID)
+	payable := models.LedgerAccountID(models.LedgerAccountDealerPayable, dealerID)
+
+	tests := []struct {
+		name   string
+		settle func(context.Context, LedgerService) error
+		want   map[string]int64
+	}{
+		{
+			name: "held",
+			want: map[string]int64{models.GatewayAccount: 5000000, escrow: -5000000},
+		},
+		{
+			name: "released to dealer less fees",
+			settle: func(ctx context.Context, s LedgerService) error {
+				return s.ReleaseEscrow(ctx, tx)
+			},
+			want: map[string]int64{models.GatewayAccount: 5000000, models.PlatformFeesAccount: -150000, payable: -4850000},
+		},
+		{
+			name: "released twice",
+			settle: func(ctx context.Context, s LedgerService) error {
+				if err := s.ReleaseEscrow(ctx, tx); err != nil {
+					return err
+				}
+				return s.ReleaseEscrow(ctx, tx)
+			},
+			want: map[string]int64{models.GatewayAccount: 5000000, models.PlatformFeesAccount: -150000, payable: -4850000},
+		},
+		{
+			name: "refunded to buyer",
+			settle: func(ctx context.Context, s LedgerService) error {
+				return s.RefundEscrow(ctx, tx)
+			},
+			want: map[string]int64{models.GatewayAccount: 5000000, buyer: -5000000},
+		},
+		{
+			name: "refund after release",
+			settle: func(ctx context.Context, s LedgerService) error {
+				if err := s.ReleaseEscrow(ctx, tx); err != nil {
+					return err
+				}
+				return s.RefundEscrow(ctx, tx)
+			},
+			want: map[string]int64{models.GatewayAccount: 5000000, models.PlatformFeesAccount: -150000, payable: -4850000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemRepo()
+			svc := NewLedgerService(repo, nil, Config{})
+
+			if err := svc.RecordFundsReceived(ctx, payment); err != nil {
+				t.Fatalf("RecordFundsReceived() error = %v", err)
+			}
+			if err := svc.HoldInEscrow(ctx, payment, tx); err != nil {
+				t.Fatalf("HoldInEscrow() error = %v", err)
+			}
+			if err := svc.HoldInEscrow(ctx, payment, tx); err != nil {
+				t.Fatalf("repeated HoldInEscrow() error = %v", err)
+			}
+			if tt.settle != nil {
+				if err := tt.settle(ctx, svc); err != nil {
+					t.Fatalf("settle error = %v", err)
+				}
+			}
+
+			var total int64
+			for acct, bal := range repo.balances {
+				total += bal
+				if bal != tt.want[acct] {
+					t.Errorf("balance of %s = %d, want %d", acct, bal, tt.want[acct])
+				}
+			}
+			for acct, bal := range tt.want {
+				if _, ok := repo.balances[acct]; !ok {
+					t.Errorf("balance of %s missing, want %d", acct, bal)
+				}
+			}
+			if total != 0 {
+				t.Errorf("ledger nets to %d, want 0", total)
+			}
+		})
+	}
+}
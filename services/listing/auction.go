@@ -0,0 +1,213 @@
+package listing
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/models"
+	"carsawa/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	minAuctionDuration = time.Hour
+	maxAuctionDuration = 30 * 24 * time.Hour
+	auctionCloseBatch  = 100
+)
+
+var (
+	ErrAuctionNotStarted = errors.New("auction has not started yet")
+	ErrAuctionEnded      = errors.New("auction has ended")
+	ErrBidTooLow         = errors.New("bid does not meet the minimum increment")
+	ErrAuctionManaged    = errors.New("auction listings are settled automatically")
+)
+
+// validateAuction normalises and checks the auction settings supplied on create.
+func validateAuction(a *models.Auction, now time.Time) error {
+	if a.StartAt.IsZero() {
+		a.StartAt = now
+	}
+	if a.EndAt.IsZero() {
+		return errors.New("auction end time is required")
+	}
+	if !a.EndAt.After(now) {
+		return errors.New("auction end time must be in the future")
+	}
+	duration := a.EndAt.Sub(a.StartAt)
+	if duration < minAuctionDuration {
+		return fmt.Errorf("auction must run for at least %s", minAuctionDuration)
+	}
+	if duration > maxAuctionDuration {
+		return fmt.Errorf("auction cannot run longer than %s", maxAuctionDuration)
+	}
+	if a.ReservePrice < 0 {
+		return errors.New("reserve price cannot be negative")
+	}
+	if a.MinIncrement < 0 {
+		return errors.New("minimum bid increment cannot be negative")
+	}
+
+	a.Status = models.AuctionStatusScheduled
+	if !a.StartAt.After(now) {
+		a.Status = models.AuctionStatusLive
+	}
+	a.WinningBid = nil
+	a.ClosedAt = nil
+	return nil
+}
+
+// checkAuctionBid verifies that an offer may be placed on an auction listing right now.
+func checkAuctionBid(lst *models.Listing, offer float64, now time.Time) error {
+	a := lst.UserListing.Auction
+	if now.Before(a.StartAt) {
+		return ErrAuctionNotStarted
+	}
+	if !now.Before(a.EndAt) {
+		return ErrAuctionEnded
+	}
+	if top, ok := highestBid(lst.UserListing.Bids); ok && offer < top.Offer+a.MinIncrement {
+		return fmt.Errorf("%w: offer must be at least %.2f", ErrBidTooLow, top.Offer+a.MinIncrement)
+	}
+	return nil
+}
+
+// outbidError explains a refused bid write on an auction listing: when a
+// rival bid landed first and raised the floor, the caller gets ErrBidTooLow
+// with the new minimum rather than the repository's error.
+func (s *listingService) outbidError(ctx context.Context, listingID string, offer float64, err error) error {
+	lst, lerr := s.repo.GetListingByID(ctx, listingID)
+	if lerr != nil || lst.UserListing.Auction == nil {
+		return err
+	}
+	if aerr := checkAuctionBid(lst, offer, time.Now()); aerr != nil {
+		return aerr
+	}
+	return err
+}
+
+// highestBid returns the top offer, preferring the earliest bid on ties.
+func highestBid(bids []models.Bid) (models.Bid, bool) {
+	var (
+		best  models.Bid
+		found bool
+	)
+	for _, b := range bids {
+		if !found || b.Offer > best.Offer || (b.Offer == best.Offer && b.CreatedAt.Before(best.CreatedAt)) {
+			best = b
+			found = true
+		}
+	}
+	return best, found
+}
+
+// CloseExpiredAuctions starts due auctions and settles any whose end time has passed.
+func (s *listingService) CloseExpiredAuctions(ctx context.Context) error {
+	ctx = utils.WithAuditActor(ctx, models.AuditActor{Type: models.ActorSystem, Source: "auction scheduler"})
+	now := time.Now()
+	if _, err := s.repo.ActivateDueAuctions(ctx, now); err != nil {
+		return err
+	}
+
+	expired, err := s.repo.GetExpiredAuctions(ctx, now, auctionCloseBatch)
+	if err != nil {
+		return err
+	}
+	for i := range expired {
+		if err := s.settleAuction(ctx, &expired[i], now); err != nil {
+			utils.GetLogger().Error("CloseExpiredAuctions: failed to settle auction",
+				zap.String("listingID", expired[i].ID.Hex()), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *listingService) settleAuction(ctx context.Context, lst *models.Listing, now time.Time) error {
+	a := lst.UserListing.Auction
+	top, hasBids := highestBid(lst.UserListing.Bids)
+
+	var winningBid *primitive.ObjectID
+	to := models.ListingStatusClosed
+	if hasBids && top.Offer >= a.ReservePrice {
+		winningBid = &top.ID
+		to = models.ListingStatusAccepted
+	}
+	if err := s.helper.checkTransition(ctx, lst, to); err != nil {
+		return err
+	}
+
+	if err := s.repo.CloseAuction(ctx, lst.ID.Hex(), winningBid, now); err != nil {
+		if errors.Is(err, listingRepo.ErrNotFound) {
+			// Already settled elsewhere.
+			return nil
+		}
+		return err
+	}
+	if settled, err := s.repo.GetListingByID(ctx, lst.ID.Hex()); err == nil {
+		etype := models.ListingEventClosed
+		if winningBid != nil {
+			etype = models.ListingEventBidAccepted
+		}
+		s.recordChange(ctx, etype, lst, settled)
+	}
+
+	listingID := lst.ID.Hex()
+	car := fmt.Sprintf("%s %s", lst.CarDetails.Make, lst.CarDetails.Model)
+
+	if winningBid == nil {
+		closed := *lst
+		closed.Status = models.ListingStatusClosed
+		s.alertWatchers(lst, &closed)
+
+		body := fmt.Sprintf("The auction for your %s ended without any bids.", car)
+		if hasBids {
+			body = fmt.Sprintf("The auction for your %s ended. The highest bid of %.2f did not meet your reserve.", car, top.Offer)
+		}
+		s.notifyUser(ctx, lst.UserListing.UserID, models.NotificationTypeAuctionNoSale,
+			"Auction Ended Without Sale", body, map[string]interface{}{"listingID": listingID})
+
+		notified := make(map[primitive.ObjectID]bool)
+		for _, b := range lst.UserListing.Bids {
+			if notified[b.DealerID] {
+				continue
+			}
+			notified[b.DealerID] = true
+			s.notifyDealer(ctx, b.DealerID, models.NotificationTypeAuctionEnded, "Auction Ended",
+				fmt.Sprintf("The auction for %s ended without a sale.", car),
+				map[string]interface{}{"listingID": listingID})
+		}
+		return nil
+	}
+
+	s.openBidSale(ctx, lst, top)
+
+	s.notifyUser(ctx, lst.UserListing.UserID, models.NotificationTypeAuctionEnded, "Your Auction Has Ended",
+		fmt.Sprintf("Your %s sold for %.2f.", car, top.Offer),
+		map[string]interface{}{"listingID": listingID, "bidID": top.ID.Hex(), "offer": top.Offer})
+
+	notified := map[primitive.ObjectID]bool{top.DealerID: true}
+	s.notifyDealer(ctx, top.DealerID, models.NotificationTypeAuctionWon, "You Won the Auction",
+		fmt.Sprintf("Your bid of %.2f won the auction for %s.", top.Offer, car),
+		map[string]interface{}{"listingID": listingID, "bidID": top.ID.Hex(), "offer": top.Offer})
+
+	for _, b := range lst.UserListing.Bids {
+		if notified[b.DealerID] {
+			continue
+		}
+		notified[b.DealerID] = true
+		s.notifyDealer(ctx, b.DealerID, models.NotificationTypeAuctionLost, "Auction Lost",
+			fmt.Sprintf("The auction for %s closed at %.2f.", car, top.Offer),
+			map[string]interface{}{"listingID": listingID})
+	}
+	return nil
+}
+
+// RunAuctionScheduler settles expired auctions every interval until ctx is
+// cancelled. Each run takes a Redis lock, so only one replica settles them.
+func RunAuctionScheduler(ctx context.Context, svc ListingService, rdb *redis.Client, interval time.Duration) {
+	utils.RunLockedJob(ctx, rdb, "auction-scheduler", interval, svc.CloseExpiredAuctions)
+}
@@ -0,0 +1,218 @@
+package listing
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	listingEventRepo "carsawa/database/repository/listingevent"
+	"carsawa/models"
+	"carsawa/utils"
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// unaudited are the listing fields that change without anyone editing the
+// listing, or that are audited elsewhere, and so are left out of diffs.
+var unaudited = map[string]bool{
+	"_id":           true,
+	"createdAt":     true,
+	"updatedAt":     true,
+	"views":         true,
+	"watchers":      true,
+	"vinClaim":      true,
+	"score":         true,
+	"distanceKm":    true,
+	"media":         true,
+	"mediaVersion":  true,
+	"coverThumbUrl": true,
+	"history":       true, // A bid's rounds; the bid's own fields carry the change
+}
+
+// recordChange writes an event for the difference between before and after.
+// A nil before records a creation. Nothing is written when nothing changed.
+func (s *listingService) recordChange(ctx context.Context, etype models.ListingEventType, before, after *models.Listing) {
+	changes := diffListings(before, after)
+	if len(changes) == 0 && etype == models.ListingEventUpdated {
+		return
+	}
+	s.recordEvent(ctx, etype, after.ID, changes)
+}
+
+// recordEvent appends to the listing's audit trail. A failure is logged
+// rather than failing the change it describes.
+func (s *listingService) recordEvent(ctx context.Context, etype models.ListingEventType, listingID primitive.ObjectID, changes []models.FieldChange) {
+	if s.events == nil {
+		return
+	}
+	event := &models.ListingEvent{
+		ListingID: listingID,
+		Type:      etype,
+		Actor:     utils.AuditActorFrom(ctx),
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+	if err := s.events.RecordEvent(ctx, event); err != nil {
+		utils.GetLogger().Warn("failed to record listing event",
+			zap.String("listingID", listingID.Hex()), zap.String("type", string(etype)), zap.Error(err))
+	}
+}
+
+// updateEventType names an update after the status it moved the listing to.
+func updateEventType(before, after *models.Listing) models.ListingEventType {
+	if before.Status != after.Status {
+		switch after.Status {
+		case models.ListingStatusClosed:
+			return models.ListingEventClosed
+		case models.ListingStatusSold:
+			return models.ListingEventSold
+		}
+	}
+	return models.ListingEventUpdated
+}
+
+// diffListings compares two listings field by field, as stored. A field
+// that appears or disappears with its zero value is not a change.
+func diffListings(before, after *models.Listing) []models.FieldChange {
+	from, to := flattenListing(before), flattenListing(after)
+
+	var changes []models.FieldChange
+	for field, v := range to {
+		old, ok := from[field]
+		if (!ok && isZero(v)) || (ok && reflect.DeepEqual(old, v)) {
+			continue
+		}
+		changes = append(changes, models.FieldChange{Field: field, From: old, To: v})
+	}
+	for field, old := range from {
+		if _, ok := to[field]; !ok && !isZero(old) {
+			changes = append(changes, models.FieldChange{Field: field, From: old})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func flattenListing(lst *models.Listing) map[string]interface{} {
+	out := make(map[string]interface{})
+	if lst == nil {
+		return out
+	}
+	raw, err := bson.Marshal(lst)
+	if err != nil {
+		return out
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return out
+	}
+	flatten("", doc, out)
+	return out
+}
+
+// flatten writes the leaves of v into out by dotted path. Arrays of
+// documents with an _id are keyed by it, so a changed bid reads as
+// userListing.bids.<bidID>.offer rather than a whole new array.
+func flatten(prefix string, v interface{}, out map[string]interface{}) {
+	switch val := v.(type) {
+	case bson.D:
+		for _, e := range val {
+			if unaudited[e.Key] {
+				continue
+			}
+			flatten(join(prefix, e.Key), e.Value, out)
+		}
+	case bson.M:
+		for k, e := range val {
+			if unaudited[k] {
+				continue
+			}
+			flatten(join(prefix, k), e, out)
+		}
+	case bson.A:
+		ids := make([]string, 0, len(val))
+		for _, e := range val {
+			d, ok := e.(bson.D)
+			if !ok {
+				break
+			}
+			id, ok := d.Map()["_id"].(primitive.ObjectID)
+			if !ok {
+				break
+			}
+			ids = append(ids, id.Hex())
+		}
+		if len(val) == 0 || len(ids) != len(val) {
+			out[prefix] = val
+			return
+		}
+		for i, e := range val {
+			flatten(join(prefix, ids[i]), e, out)
+		}
+	default:
+		out[prefix] = v
+	}
+}
+
+func isZero(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// GetPriceHistory returns the asking prices a listing has had. Drafts have
+// no public history.
+func (s *listingService) GetPriceHistory(ctx context.Context, listingID string) (*models.PriceHistory, error) {
+	lst, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if lst.Status == models.ListingStatusDraft {
+		return nil, listingRepo.ErrNotFound
+	}
+
+	history := &models.PriceHistory{
+		ListingID:    listingID,
+		CurrentPrice: lst.CarDetails.Price,
+		Changes:      []models.PriceChange{},
+	}
+	if s.events == nil {
+		return history, nil
+	}
+	events, err := s.events.GetPriceEvents(ctx, lst.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		for _, c := range e.Changes {
+			if c.Field != listingEventRepo.PriceField {
+				continue
+			}
+			if price, ok := c.To.(float64); ok && price > 0 {
+				history.Changes = append(history.Changes, models.PriceChange{Price: price, At: e.CreatedAt})
+			}
+		}
+	}
+	return history, nil
+}
+
+// GetListingEvents returns a page of a listing's full audit trail, newest
+// first, for admins. Events outlive the listing they describe.
+func (s *listingService) GetListingEvents(ctx context.Context, listingID string, pagination models.Pagination) ([]models.ListingEvent, error) {
+	id, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return nil, listingRepo.ErrInvalidID
+	}
+	if s.events == nil {
+		return []models.ListingEvent{}, nil
+	}
+	return s.events.GetListingEvents(ctx, id, pagination)
+}
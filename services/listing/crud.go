@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	listingRepo "carsawa/database/repository/listing"
 	"carsawa/models"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -38,6 +39,8 @@ func (s *listingService) CreateDealerListing(
 		CarDetails: listing.CarDetails,
 	}
 
+	NormalizeCarDetails(&toCreate.CarDetails)
+	s.inheritLocation(ctx, toCreate)
 	if err := s.validateCarDetails(ctx, *toCreate); err != nil {
 		return nil, err
 	}
@@ -51,6 +54,7 @@ func (s *listingService) CreateDealerListing(
 	if err != nil {
 		return nil, err
 	}
+	s.recordChange(ctx, models.ListingEventCreated, nil, lst)
 
 	// notify dealer
 	s.notifyDealer(
@@ -61,6 +65,8 @@ func (s *listingService) CreateDealerListing(
 		fmt.Sprintf("Your draft for %s %s has been saved.", lst.CarDetails.Make, lst.CarDetails.Model),
 		map[string]interface{}{"listingID": lst.ID.Hex()},
 	)
+	s.notifyIfFlagged(ctx, lst)
+	s.indexSuggestions(ctx, lst)
 
 	return lst, nil
 }
@@ -81,11 +87,12 @@ func (s *listingService) GetListing(
 	return lst, nil
 }
 
-// UpdateListing applies arbitrary updates, then notifies any bidders if price changed.
+// UpdateListing applies a merge patch from the listing's owner or an admin,
+// then notifies any bidders if price changed.
 func (s *listingService) UpdateListing(
 	ctx context.Context,
 	listingID string,
-	updates map[string]interface{},
+	patch models.ListingPatch,
 ) (*models.Listing, error) {
 	// validate ID
 	if _, err := s.helper.convertAndValidateID(listingID); err != nil {
@@ -97,22 +104,47 @@ func (s *listingService) UpdateListing(
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorizePatch(ctx, existing, patch); err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{}
 
-	// if carDetails provided, validate them
-	if cdRaw, ok := updates["carDetails"]; ok {
-		if err := s.validateAndUpdateCarDetails(existing, cdRaw); err != nil {
+	// if carDetails provided, validate them and persist the merged result
+	var newPrice *float64
+	detailsChanged := patch.CarDetails != nil
+	if detailsChanged {
+		merged, err := s.validateAndUpdateCarDetails(ctx, existing, patch.CarDetails)
+		if err != nil {
 			return nil, err
 		}
+		if merged.Price != existing.CarDetails.Price {
+			newPrice = &merged.Price
+		}
+		updates["carDetails"] = merged
 	}
 
-	// no type changes allowed
-	if _, ok := updates["type"]; ok {
-		return nil, errors.New("cannot modify listing type")
+	// a status change must be one the caller's role may make
+	if patch.Status.Set {
+		to := patch.Status.Value
+		switch {
+		case patch.Status.Null:
+			return nil, models.InvalidField("status", "cannot be null")
+		case to == existing.Status:
+		case existing.Status == models.ListingStatusDraft && to == models.ListingStatusActive:
+			return nil, fmt.Errorf("%w: publish a draft to make it active", listingRepo.ErrInvalidTransition)
+		case existing.Status == models.ListingStatusExpired && to == models.ListingStatusActive:
+			return nil, fmt.Errorf("%w: renew an expired listing to make it active", listingRepo.ErrInvalidTransition)
+		default:
+			if err := s.helper.checkTransition(ctx, existing, to); err != nil {
+				return nil, err
+			}
+			updates["status"] = to
+		}
 	}
 
-	// timestamp and persist
+	// timestamp and persist, provided the status has not moved meanwhile
 	updates["updatedAt"] = time.Now()
-	if err := s.repo.UpdateListing(ctx, listingID, updates); err != nil {
+	if err := s.repo.UpdateListing(ctx, listingID, existing.Status, updates); err != nil {
 		return nil, fmt.Errorf("failed to update listing: %w", err)
 	}
 
@@ -122,21 +154,38 @@ func (s *listingService) UpdateListing(
 		return nil, err
 	}
 
+	s.recordChange(ctx, updateEventType(existing, updated), existing, updated)
+	if existing.Status != updated.Status {
+		s.notifyTransition(ctx, existing, updated)
+	}
+
+	if detailsChanged {
+		s.indexSuggestions(ctx, updated)
+	}
+	if !onMarket(existing.Status) && onMarket(updated.Status) {
+		s.announceListing(updated)
+	}
+	s.alertWatchers(existing, updated)
+
+	// a dealer car marked sold opens its sale record
+	if updated.Type == models.ListingTypeDealer &&
+		existing.Status != models.ListingStatusSold && updated.Status == models.ListingStatusSold {
+		s.openDealerSale(ctx, updated)
+	}
+
 	// if price changed, notify all dealers who have bids
-	if cdRaw, ok := updates["carDetails"].(map[string]interface{}); ok {
-		if newPrice, ok2 := cdRaw["price"].(float64); ok2 {
-			for _, bid := range updated.UserListing.Bids {
-				s.notifyDealer(
-					ctx,
-					bid.DealerID,
-					models.NotificationTypeListingUpdated,
-					"Listing Updated",
-					fmt.Sprintf("Price for %s %s updated to %.2f.",
-						updated.CarDetails.Make, updated.CarDetails.Model, newPrice,
-					),
-					map[string]interface{}{"listingID": listingID, "newPrice": newPrice},
-				)
-			}
+	if newPrice != nil {
+		for _, bid := range updated.UserListing.Bids {
+			s.notifyDealer(
+				ctx,
+				bid.DealerID,
+				models.NotificationTypeListingUpdated,
+				"Listing Updated",
+				fmt.Sprintf("Price for %s %s updated to %.2f.",
+					updated.CarDetails.Make, updated.CarDetails.Model, *newPrice,
+				),
+				map[string]interface{}{"listingID": listingID, "newPrice": *newPrice},
+			)
 		}
 	}
 
@@ -158,10 +207,21 @@ func (s *listingService) DeleteListing(
 	if lst.Status == models.ListingStatusAccepted {
 		return errors.New("cannot delete accepted listings")
 	}
-	return s.repo.DeleteListing(ctx, listingID)
+	if err := s.repo.DeleteListing(ctx, listingID); err != nil {
+		return err
+	}
+	s.recordEvent(ctx, models.ListingEventDeleted, lst.ID, nil)
+
+	// clean up stored photos and watchlist entries once the listing is gone
+	s.discardMedia(ctx, lst.Media)
+	if s.watchers != nil {
+		s.watchers.ForgetListing(ctx, listingID)
+	}
+	return nil
 }
 
-// PublishListing flips a draft to active, then notifies the dealer.
+// PublishListing flips a draft to active, then notifies the dealer. A car
+// whose VIN is already live elsewhere is published but flagged for review.
 func (s *listingService) PublishListing(
 	ctx context.Context,
 	listingID, dealerHex string,
@@ -171,13 +231,22 @@ func (s *listingService) PublishListing(
 		return nil, errors.New("invalid dealer ID format")
 	}
 
-	// update status via existing method
-	published, err := s.UpdateListing(ctx, listingID, map[string]interface{}{
-		"status": models.ListingStatusActive,
-	})
+	lst, err := s.repo.GetListingByID(ctx, listingID)
 	if err != nil {
 		return nil, err
 	}
+	if lst.Type != models.ListingTypeDealer || lst.DealerListing.DealerID != dealerID {
+		return nil, listingRepo.ErrUnauthorizedAction
+	}
+	if err := s.helper.checkTransition(ctx, lst, models.ListingStatusActive); err != nil {
+		return nil, err
+	}
+
+	published, err := s.repo.PublishListing(ctx, listingID, s.lifecycle.expiryFrom(lst.Type, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, models.ListingEventPublished, lst, published)
 
 	s.notifyDealer(
 		ctx,
@@ -189,11 +258,14 @@ func (s *listingService) PublishListing(
 		),
 		map[string]interface{}{"listingID": listingID},
 	)
+	s.notifyIfFlagged(ctx, published)
+	s.announceListing(published)
 
 	return published, nil
 }
 
-// CloseListing marks a listing closed and notifies the closer.
+// CloseListing marks a listing closed; the owner is told through the
+// closed transition's notification.
 func (s *listingService) CloseListing(
 	ctx context.Context,
 	listingID, ownerHex string,
@@ -210,37 +282,32 @@ func (s *listingService) CloseListing(
 	}
 
 	// auth checks
-	if isDealer {
-		if lst.DealerListing.DealerID != ownerID {
-			return errors.New("unauthorized dealer operation")
-		}
-	} else {
-		if lst.UserListing.UserID != ownerID {
-			return errors.New("unauthorized user operation")
-		}
+	if err := s.helper.authorizeOwner(lst, ownerID, isDealer); err != nil {
+		return err
 	}
 
 	// close status
-	_, err = s.UpdateListing(ctx, listingID, map[string]interface{}{
-		"status": models.ListingStatusClosed,
+	_, err = s.UpdateListing(ctx, listingID, models.ListingPatch{
+		Status: models.Patch[models.ListingStatus]{Set: true, Value: models.ListingStatusClosed},
 	})
+	return err
+}
+
+// GetDealerListings pages through a dealer's own inventory, newest first.
+func (s *listingService) GetDealerListings(
+	ctx context.Context,
+	dealerHex string,
+	status models.ListingStatus,
+	pagination models.Pagination,
+) ([]models.Listing, models.PageCursors, error) {
+	dealerID, err := primitive.ObjectIDFromHex(dealerHex)
 	if err != nil {
-		return err
+		return nil, models.PageCursors{}, errors.New("invalid dealer ID format")
 	}
-
-	// fire notification
-	notifType := models.NotificationTypeListingClosed
-	title := "Listing Closed"
-	body := fmt.Sprintf("You closed %s %s.",
-		lst.CarDetails.Make, lst.CarDetails.Model,
-	)
-	if isDealer {
-		s.notifyDealer(ctx, ownerID, notifType, title, body, map[string]interface{}{"listingID": listingID})
-	} else {
-		s.notifyUser(ctx, ownerID, notifType, title, body, map[string]interface{}{"listingID": listingID})
+	if pagination.Limit == 0 {
+		pagination.Limit = defaultListingLimit
 	}
-
-	return nil
+	return s.repo.GetListingsByDealer(ctx, dealerID, status, pagination)
 }
 
 func (s *listingService) SearchListings(
@@ -248,5 +315,6 @@ func (s *listingService) SearchListings(
 	filter models.ListingFilter,
 	pagination models.Pagination,
 ) ([]models.Listing, error) {
+	NormalizeListingFilter(&filter)
 	return s.repo.SearchListings(ctx, filter, pagination)
 }
@@ -18,10 +18,12 @@ func (s *listingService) GetFeed(ctx context.Context, filter models.ListingFilte
 	if pagination.Limit == 0 {
 		pagination.Limit = defaultListingLimit
 	}
+	NormalizeListingFilter(&filter)
 
 	// Fetch content concurrently
 	var (
 		listings   []models.Listing
+		cursors    models.PageCursors
 		promotions []models.Promotion
 		banners    []models.Banner
 		errs       = make(chan error, 3)
@@ -29,7 +31,7 @@ func (s *listingService) GetFeed(ctx context.Context, filter models.ListingFilte
 
 	go func() {
 		var err error
-		listings, err = s.repo.GetActiveListings(ctx, filter, pagination)
+		listings, cursors, err = s.repo.GetActiveListings(ctx, filter, pagination)
 		errs <- err
 	}()
 
@@ -52,8 +54,13 @@ func (s *listingService) GetFeed(ctx context.Context, filter models.ListingFilte
 		}
 	}
 
-	// Apply business logic to prioritize content
-	prioritizedListings := prioritizeListings(listings)
+	// Apply business logic to prioritize content; geo feeds stay nearest
+	// first. Cursors were taken from the page edges before this reordering.
+	prioritizedListings := listings
+	if filter.Near == nil {
+		prioritizedListings = prioritizeListings(listings)
+	}
+	s.attachMarketBadges(prioritizedListings)
 	currentPromotions := filterActivePromotions(promotions)
 	positionedBanners := positionBanners(banners)
 
@@ -61,6 +68,7 @@ func (s *listingService) GetFeed(ctx context.Context, filter models.ListingFilte
 		Listings:   prioritizedListings,
 		Promotions: currentPromotions,
 		Banners:    positionedBanners,
+		Cursors:    cursors,
 	}, nil
 }
 
@@ -103,34 +111,48 @@ func positionBanners(banners []models.Banner) []models.Banner {
 }
 
 func (s *listingService) Search(ctx context.Context, query string, filter models.ListingFilter, pagination models.Pagination) (*models.SearchResult, error) {
-	// Record search for analytics
-	go func() {
-		_ = s.repo.RecordSearchQuery(context.Background(), query, filter)
-	}()
-
-	var (
-		listings []models.Listing
-		err      error
-	)
-
-	if strings.TrimSpace(query) != "" {
-		listings, err = s.repo.TextSearch(ctx, query, pagination)
-	} else {
-		listings, err = s.repo.GetActiveListings(ctx, filter, pagination)
+	if pagination.Limit == 0 {
+		pagination.Limit = defaultListingLimit
 	}
+	NormalizeListingFilter(&filter)
 
+	result, err := s.repo.FacetedSearch(ctx, query, filter, pagination)
 	if err != nil {
 		return nil, err
 	}
 
-	suggestions, _ := s.repo.GetSearchSuggestions(ctx, query)
+	s.attachMarketBadges(result.Listings)
 
-	return &models.SearchResult{
-		Listings:    listings,
-		Suggestions: suggestions,
-	}, nil
+	// Record search for analytics; later pages are the same search
+	if pagination.Offset == 0 && pagination.Cursor == nil {
+		s.recordSearch(result, query, filter)
+	}
+
+	if strings.TrimSpace(query) != "" {
+		result.Suggestions, _ = s.GetSearchSuggestions(ctx, query, 0)
+	}
+
+	return result, nil
 }
 
-func (s *listingService) GetSearchSuggestions(ctx context.Context, query string) ([]models.SearchSuggestion, error) {
-	return s.repo.GetSearchSuggestions(ctx, query)
+// GetSearchSuggestions completes a partly typed make, model or dealer name.
+func (s *listingService) GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	if s.suggest == nil {
+		return nil, nil
+	}
+	return s.suggest.Suggest(query, limit), nil
+}
+
+// indexSuggestions lets buyers find a newly seen make or model by typing it.
+func (s *listingService) indexSuggestions(ctx context.Context, lst *models.Listing) {
+	if s.suggest != nil {
+		s.suggest.IndexListing(ctx, lst)
+	}
+}
+
+// attachMarketBadges marks each listing's price against its valuation.
+func (s *listingService) attachMarketBadges(listings []models.Listing) {
+	if s.valuer != nil {
+		s.valuer.AttachBadges(listings)
+	}
 }
@@ -2,10 +2,18 @@ package listing
 
 import (
 	listingRepo "carsawa/database/repository/listing"
+	listingEventRepo "carsawa/database/repository/listingevent"
 	"carsawa/models"
+	"carsawa/services/autocomplete"
 	"carsawa/services/dealer"
 	"carsawa/services/notification"
+	"carsawa/services/savedsearch"
+	"carsawa/services/storage"
+	"carsawa/services/transaction"
 	"carsawa/services/user"
+	"carsawa/services/valuation"
+	"carsawa/services/vin"
+	"carsawa/services/watchlist"
 	"context"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,25 +23,64 @@ type ListingService interface {
 	CreateDealerListing(ctx context.Context, dealerID string, car models.Listing, price float64) (*models.Listing, error)
 	CreateUserBidListing(ctx context.Context, userID string, car models.Listing) (*models.Listing, error)
 	GetListing(ctx context.Context, id string) (*models.Listing, error)
-	UpdateListing(ctx context.Context, id string, updates map[string]interface{}) (*models.Listing, error)
+	UpdateListing(ctx context.Context, id string, patch models.ListingPatch) (*models.Listing, error)
 	DeleteListing(ctx context.Context, id string) error
 	AddBid(ctx context.Context, listingID string, bid models.Bid) (*models.Listing, error)
 	AcceptBid(ctx context.Context, listingID, bidID, userID string) (*models.Listing, error)
+	CounterBid(ctx context.Context, listingID, bidID, userID string, amount float64, message string) (*models.Listing, error)
+	RejectBid(ctx context.Context, listingID, bidID, userID, message string) (*models.Listing, error)
+	DealerCounterBid(ctx context.Context, listingID, bidID, dealerID string, amount float64, message string) (*models.Listing, error)
+	UpdateBid(ctx context.Context, listingID, bidID, dealerID string, amount float64, message string) (*models.Listing, error)
+	WithdrawBid(ctx context.Context, listingID, bidID, dealerID, message string) (*models.Listing, error)
 	PublishListing(ctx context.Context, listingID, dealerID string) (*models.Listing, error)
 	CloseListing(ctx context.Context, listingID, ownerID string, isDealer bool) error
 	SearchListings(ctx context.Context, filter models.ListingFilter, pagination models.Pagination) ([]models.Listing, error)
+	GetDealerListings(ctx context.Context, dealerID string, status models.ListingStatus, pagination models.Pagination) ([]models.Listing, models.PageCursors, error)
 	GetFeed(ctx context.Context, filter models.ListingFilter, pagination models.Pagination) (*models.FeedResponse, error)
 	Search(ctx context.Context, query string, filters models.ListingFilter, pagination models.Pagination) (*models.SearchResult, error)
+	GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error)
+	CloseExpiredAuctions(ctx context.Context) error
+
+	// Listing lifecycle
+	RenewListing(ctx context.Context, listingID, dealerID string) (*models.Listing, error)
+	SweepListingExpiry(ctx context.Context) error
+	PurgeStaleDrafts(ctx context.Context) error
+
+	// Search analytics
+	RecordSearchClick(ctx context.Context, searchID, listingID string) error
+	GetSearchTrends(ctx context.Context, city string, limit int) (*models.SearchTrends, error)
+	ComputeSearchTrends(ctx context.Context) error
+
+	// Audit trail
+	GetPriceHistory(ctx context.Context, listingID string) (*models.PriceHistory, error)
+	GetListingEvents(ctx context.Context, listingID string, pagination models.Pagination) ([]models.ListingEvent, error)
+
+	// Moderation
+	GetSuspiciousVINClusters(ctx context.Context, pagination models.Pagination) ([]models.VINCluster, error)
+
+	// Photo gallery
+	UploadListingMedia(ctx context.Context, listingID, ownerID string, isDealer bool, uploads []MediaUpload) (*models.Listing, error)
+	ReorderListingMedia(ctx context.Context, listingID, ownerID string, isDealer bool, order []string) (*models.Listing, error)
+	SetListingCover(ctx context.Context, listingID, ownerID string, isDealer bool, mediaID string) (*models.Listing, error)
+	UpdateMediaCaption(ctx context.Context, listingID, ownerID string, isDealer bool, mediaID, caption string) (*models.Listing, error)
+	DeleteListingMedia(ctx context.Context, listingID, ownerID string, isDealer bool, mediaID string) (*models.Listing, error)
 }
 
 type listingService struct {
 	repo      listingRepo.ListingRepository
 	user      user.UserService
 	dealer    dealer.DealerService
-	verifier  *NHTSAVerifier
-	validator *listingValidator
+	vins      vin.VINDecoder
 	helper    *listingHelper
 	notifier  notification.NotificationService
+	storage   storage.StorageService
+	txns      transaction.TransactionService
+	suggest   autocomplete.AutocompleteService
+	alerts    savedsearch.SavedSearchService
+	watchers  watchlist.WatchlistService
+	valuer    valuation.ValuationService
+	events    listingEventRepo.ListingEventRepository
+	lifecycle LifecycleConfig
 }
 
 type FeedResponse struct {
@@ -53,15 +100,34 @@ func NewListingService(
 	notifSvc notification.NotificationService,
 	user user.UserService,
 	dealer dealer.DealerService,
+	store storage.StorageService,
+	txns transaction.TransactionService,
+	vins vin.VINDecoder,
+	suggest autocomplete.AutocompleteService,
+	alerts savedsearch.SavedSearchService,
+	watchers watchlist.WatchlistService,
+	valuer valuation.ValuationService,
+	events listingEventRepo.ListingEventRepository,
+	lifecycle LifecycleConfig,
 ) ListingService {
-	verifier := NewNHTSAVerifier()
+	if vins == nil {
+		vins = vin.NewLocalDecoder()
+	}
 	return &listingService{
 		repo:      repo,
 		user:      user,
-		verifier:  verifier,
-		validator: newListingValidator(verifier),
+		dealer:    dealer,
+		vins:      vins,
 		helper:    newListingHelper(repo),
 		notifier:  notifSvc,
+		storage:   store,
+		txns:      txns,
+		suggest:   suggest,
+		alerts:    alerts,
+		watchers:  watchers,
+		valuer:    valuer,
+		events:    events,
+		lifecycle: lifecycle,
 	}
 }
 
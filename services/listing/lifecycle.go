@@ -0,0 +1,229 @@
+package listing
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/models"
+	"carsawa/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const lifecycleBatch = 200
+
+// LifecycleConfig sets how long listings stay on the market and when
+// abandoned drafts are purged. A zero duration turns that part off.
+type LifecycleConfig struct {
+	DealerLifetime time.Duration
+	UserLifetime   time.Duration // Open-ended bid listings; timed auctions end on their own
+	ReminderLead   time.Duration // How long before expiry owners are reminded
+	DraftTTL       time.Duration // Drafts untouched this long are deleted
+}
+
+func (c LifecycleConfig) lifetime(lt models.ListingType) time.Duration {
+	if lt == models.ListingTypeUserBid {
+		return c.UserLifetime
+	}
+	return c.DealerLifetime
+}
+
+// expiryFrom is when a listing of type lt going live at now expires, or nil
+// when listings of that type do not expire.
+func (c LifecycleConfig) expiryFrom(lt models.ListingType, now time.Time) *time.Time {
+	lifetime := c.lifetime(lt)
+	if lifetime <= 0 {
+		return nil
+	}
+	at := now.Add(lifetime)
+	return &at
+}
+
+// SweepListingExpiry expires live listings whose lifetime is up and reminds
+// the owners of those about to expire. Dealer listings become expired and
+// can be renewed; bid listings close. Live listings with no expiry yet, such
+// as those listed before lifetimes were configured, are given a full
+// lifetime from now.
+func (s *listingService) SweepListingExpiry(ctx context.Context) error {
+	ctx = utils.WithAuditActor(ctx, models.AuditActor{Type: models.ActorSystem, Source: "expiry job"})
+	now := time.Now()
+	for _, lt := range []models.ListingType{models.ListingTypeDealer, models.ListingTypeUserBid} {
+		expiresAt := s.lifecycle.expiryFrom(lt, now)
+		if expiresAt == nil {
+			continue
+		}
+		if _, err := s.repo.StampExpiry(ctx, lt, *expiresAt); err != nil {
+			return err
+		}
+		if err := s.expireListings(ctx, lt, now); err != nil {
+			return err
+		}
+		if err := s.remindExpiring(ctx, lt, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *listingService) expireListings(ctx context.Context, lt models.ListingType, now time.Time) error {
+	expired, err := s.repo.GetExpiredListings(ctx, lt, now, lifecycleBatch)
+	if err != nil {
+		return err
+	}
+	for i := range expired {
+		if err := s.expireListing(ctx, &expired[i]); err != nil {
+			utils.GetLogger().Error("SweepListingExpiry: failed to expire listing",
+				zap.String("listingID", expired[i].ID.Hex()), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *listingService) expireListing(ctx context.Context, lst *models.Listing) error {
+	to := models.ListingStatusExpired
+	if lst.Type == models.ListingTypeUserBid {
+		to = models.ListingStatusClosed
+	}
+	if err := s.helper.checkTransition(ctx, lst, to); err != nil {
+		return err
+	}
+	if err := s.repo.TransitionStatus(ctx, lst.ID.Hex(), lst.Status, to); err != nil {
+		if errors.Is(err, listingRepo.ErrInvalidTransition) {
+			// Sold, closed or renewed meanwhile.
+			return nil
+		}
+		return err
+	}
+	after, err := s.repo.GetListingByID(ctx, lst.ID.Hex())
+	if err != nil {
+		return err
+	}
+	s.recordChange(ctx, models.ListingEventExpired, lst, after)
+	s.alertWatchers(lst, after)
+
+	car := fmt.Sprintf("%s %s", after.CarDetails.Make, after.CarDetails.Model)
+	body := fmt.Sprintf("Your %s listing has expired. Renew it to put it back on the market.", car)
+	if after.Type == models.ListingTypeUserBid {
+		body = fmt.Sprintf("Your %s listing has expired and is closed to new bids.", car)
+	}
+	s.notifyOwner(ctx, after, models.NotificationTypeListingExpired, "Listing Expired", body,
+		map[string]interface{}{"listingID": after.ID.Hex(), "status": after.Status})
+	return nil
+}
+
+func (s *listingService) remindExpiring(ctx context.Context, lt models.ListingType, now time.Time) error {
+	if s.lifecycle.ReminderLead <= 0 {
+		return nil
+	}
+	due, err := s.repo.GetExpiringListings(ctx, lt, now.Add(s.lifecycle.ReminderLead), lifecycleBatch)
+	if err != nil {
+		return err
+	}
+	for i := range due {
+		lst := &due[i]
+		// claim the reminder first so it goes out once
+		if ok, err := s.repo.MarkReminded(ctx, lst.ID.Hex(), now); err != nil {
+			utils.GetLogger().Error("SweepListingExpiry: failed to mark reminder",
+				zap.String("listingID", lst.ID.Hex()), zap.Error(err))
+			continue
+		} else if !ok {
+			continue
+		}
+
+		car := fmt.Sprintf("%s %s", lst.CarDetails.Make, lst.CarDetails.Model)
+		body := fmt.Sprintf("Your %s listing expires on %s.", car, lst.ExpiresAt.Format(time.RFC1123))
+		if lst.Type == models.ListingTypeDealer {
+			body += " Renew it to keep it on the market."
+		}
+		s.notifyOwner(ctx, lst, models.NotificationTypeListingExpiring, "Listing Expiring Soon", body,
+			map[string]interface{}{"listingID": lst.ID.Hex(), "expiresAt": lst.ExpiresAt})
+	}
+	return nil
+}
+
+// RenewListing gives a dealer listing a fresh lifetime from now. An active
+// listing keeps its place on the market; an expired one goes back on it,
+// and its VIN is checked again as on publishing.
+func (s *listingService) RenewListing(ctx context.Context, listingID, dealerHex string) (*models.Listing, error) {
+	dealerID, err := primitive.ObjectIDFromHex(dealerHex)
+	if err != nil {
+		return nil, errors.New("invalid dealer ID format")
+	}
+	lst, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.helper.authorizeOwner(lst, dealerID, true); err != nil {
+		return nil, err
+	}
+	switch lst.Status {
+	case models.ListingStatusActive:
+	case models.ListingStatusExpired:
+		if err := s.helper.checkTransition(ctx, lst, models.ListingStatusActive); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: only active or expired listings can be renewed", listingRepo.ErrInvalidTransition)
+	}
+
+	renewed, err := s.repo.RenewListing(ctx, listingID, lst.Status, s.lifecycle.expiryFrom(lst.Type, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, models.ListingEventRenewed, lst, renewed)
+
+	body := fmt.Sprintf("%s %s has been renewed.", renewed.CarDetails.Make, renewed.CarDetails.Model)
+	if renewed.ExpiresAt != nil {
+		body = fmt.Sprintf("%s %s is listed until %s.",
+			renewed.CarDetails.Make, renewed.CarDetails.Model, renewed.ExpiresAt.Format(time.RFC1123))
+	}
+	s.notifyDealer(ctx, dealerID, models.NotificationTypeListingRenewed, "Listing Renewed", body,
+		map[string]interface{}{"listingID": listingID})
+	if lst.Status == models.ListingStatusExpired {
+		s.notifyIfFlagged(ctx, renewed)
+		s.announceListing(renewed)
+	}
+	return renewed, nil
+}
+
+// PurgeStaleDrafts deletes drafts nobody has touched within the configured
+// TTL, with their photos. A draft edited since it was read is kept.
+func (s *listingService) PurgeStaleDrafts(ctx context.Context) error {
+	if s.lifecycle.DraftTTL <= 0 {
+		return nil
+	}
+	ctx = utils.WithAuditActor(ctx, models.AuditActor{Type: models.ActorSystem, Source: "draft cleanup job"})
+	before := time.Now().Add(-s.lifecycle.DraftTTL)
+
+	stale, err := s.repo.GetStaleDrafts(ctx, before, lifecycleBatch)
+	if err != nil {
+		return err
+	}
+	for i := range stale {
+		lst := &stale[i]
+		deleted, err := s.repo.DeleteStaleDraft(ctx, lst.ID.Hex(), before)
+		if err != nil {
+			utils.GetLogger().Error("PurgeStaleDrafts: failed to delete draft",
+				zap.String("listingID", lst.ID.Hex()), zap.Error(err))
+			continue
+		}
+		if !deleted {
+			continue
+		}
+		s.recordEvent(ctx, models.ListingEventPurged, lst.ID, nil)
+		s.discardMedia(ctx, lst.Media)
+	}
+	return nil
+}
+
+// RunLifecycleJobs runs the expiry sweep and the stale draft cleanup every
+// interval until ctx is cancelled. Each job runs under its own Redis lock,
+// so every replica can start them and only one does each run.
+func RunLifecycleJobs(ctx context.Context, svc ListingService, rdb *redis.Client, interval time.Duration) {
+	go utils.RunLockedJob(ctx, rdb, "listing-expiry", interval, svc.SweepListingExpiry)
+	utils.RunLockedJob(ctx, rdb, "draft-purge", interval, svc.PurgeStaleDrafts)
+}
@@ -0,0 +1,49 @@
+package listing
+
+import (
+	"carsawa/models"
+	"context"
+	"strings"
+)
+
+// inheritLocation places a car at its seller's location when the listing
+// gives none, so it shows up in geo searches. Coordinates are only borrowed
+// when the car is in the seller's city.
+func (s *listingService) inheritLocation(ctx context.Context, lst *models.Listing) {
+	cd := &lst.CarDetails
+	if cd.Location != nil && !cd.Location.GeoPoint.IsZero() {
+		return
+	}
+
+	owner := s.ownerLocation(ctx, lst)
+	if owner == nil || owner.City == "" {
+		return
+	}
+	if cd.Location == nil {
+		loc := *owner
+		cd.Location = &loc
+		return
+	}
+	if strings.EqualFold(cd.Location.City, owner.City) {
+		cd.Location.GeoPoint = owner.GeoPoint
+	}
+}
+
+// ownerLocation looks up the dealer's yard or the user's saved location.
+func (s *listingService) ownerLocation(ctx context.Context, lst *models.Listing) *models.Location {
+	switch lst.Type {
+	case models.ListingTypeDealer:
+		dealer, err := s.dealer.GetDealer(ctx, lst.DealerListing.DealerID.Hex())
+		if err != nil {
+			return nil
+		}
+		return &dealer.Profile.Location
+	case models.ListingTypeUserBid:
+		user, err := s.user.GetUserByID(lst.UserListing.UserID.Hex())
+		if err != nil {
+			return nil
+		}
+		return user.Location
+	}
+	return nil
+}
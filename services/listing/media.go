@@ -0,0 +1,296 @@
+package listing
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/models"
+	"carsawa/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	mediaFolder     = "listings"
+	thumbnailWidth  = 400
+	thumbnailHeight = 300
+	maxCaptionLen   = 200
+
+	// mediaEditAttempts bounds how often a gallery edit is replayed when a
+	// concurrent change lands first.
+	mediaEditAttempts = 3
+)
+
+var ErrMediaNotFound = errors.New("photo not found")
+
+// MediaUpload is a photo saved to local disk by the handler, ready to be stored.
+type MediaUpload struct {
+	LocalPath string
+	Caption   string
+}
+
+// UploadListingMedia stores photos for a listing and appends them to its gallery.
+// The first photo on an empty gallery becomes the cover.
+func (s *listingService) UploadListingMedia(
+	ctx context.Context,
+	listingID, ownerHex string,
+	isDealer bool,
+	uploads []MediaUpload,
+) (*models.Listing, error) {
+	lst, err := s.loadOwnedListing(ctx, listingID, ownerHex, isDealer)
+	if err != nil {
+		return nil, err
+	}
+	if len(uploads) == 0 {
+		return nil, errors.New("no photos provided")
+	}
+	if len(lst.Media)+len(uploads) > listingRepo.MaxListingMedia {
+		return nil, fmt.Errorf("%w: at most %d photos per listing", listingRepo.ErrMediaLimit, listingRepo.MaxListingMedia)
+	}
+	for _, u := range uploads {
+		if len(u.Caption) > maxCaptionLen {
+			return nil, fmt.Errorf("caption must be at most %d characters", maxCaptionLen)
+		}
+	}
+
+	folder := fmt.Sprintf("%s/%s", mediaFolder, listingID)
+	media := make([]models.ListingMedia, 0, len(uploads))
+	for i, u := range uploads {
+		m, err := s.storeMedia(ctx, u, folder)
+		if err != nil {
+			s.discardMedia(ctx, media)
+			return nil, err
+		}
+		m.Position = len(lst.Media) + i
+		media = append(media, m)
+	}
+
+	coverThumb := ""
+	if len(lst.Media) == 0 {
+		media[0].IsCover = true
+		coverThumb = media[0].ThumbURL
+	}
+
+	if err := s.repo.AddMedia(ctx, listingID, media, coverThumb); err != nil {
+		s.discardMedia(ctx, media)
+		return nil, fmt.Errorf("failed to save photos: %w", err)
+	}
+	return s.repo.GetListingByID(ctx, listingID)
+}
+
+// ReorderListingMedia sets the gallery order; order must list every photo ID exactly once.
+func (s *listingService) ReorderListingMedia(
+	ctx context.Context,
+	listingID, ownerHex string,
+	isDealer bool,
+	order []string,
+) (*models.Listing, error) {
+	positions := make(map[primitive.ObjectID]int, len(order))
+	for i, hex := range order {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid photo ID %q", hex)
+		}
+		if _, dup := positions[id]; dup {
+			return nil, errors.New("order must include every photo exactly once")
+		}
+		positions[id] = i
+	}
+
+	return s.editMedia(ctx, listingID, ownerHex, isDealer, func(lst *models.Listing) error {
+		if len(order) != len(lst.Media) {
+			return errors.New("order must include every photo exactly once")
+		}
+		for i := range lst.Media {
+			pos, ok := positions[lst.Media[i].ID]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrMediaNotFound, lst.Media[i].ID.Hex())
+			}
+			lst.Media[i].Position = pos
+		}
+		return nil
+	})
+}
+
+// SetListingCover marks one photo as the listing's cover image.
+func (s *listingService) SetListingCover(
+	ctx context.Context,
+	listingID, ownerHex string,
+	isDealer bool,
+	mediaHex string,
+) (*models.Listing, error) {
+	return s.editMedia(ctx, listingID, ownerHex, isDealer, func(lst *models.Listing) error {
+		idx, err := findMedia(lst, mediaHex)
+		if err != nil {
+			return err
+		}
+		for i := range lst.Media {
+			lst.Media[i].IsCover = i == idx
+		}
+		return nil
+	})
+}
+
+// UpdateMediaCaption changes the caption on a single photo.
+func (s *listingService) UpdateMediaCaption(
+	ctx context.Context,
+	listingID, ownerHex string,
+	isDealer bool,
+	mediaHex, caption string,
+) (*models.Listing, error) {
+	if len(caption) > maxCaptionLen {
+		return nil, fmt.Errorf("caption must be at most %d characters", maxCaptionLen)
+	}
+	return s.editMedia(ctx, listingID, ownerHex, isDealer, func(lst *models.Listing) error {
+		idx, err := findMedia(lst, mediaHex)
+		if err != nil {
+			return err
+		}
+		lst.Media[idx].Caption = caption
+		return nil
+	})
+}
+
+// DeleteListingMedia removes a photo from the gallery and from storage.
+func (s *listingService) DeleteListingMedia(
+	ctx context.Context,
+	listingID, ownerHex string,
+	isDealer bool,
+	mediaHex string,
+) (*models.Listing, error) {
+	var removed models.ListingMedia
+	updated, err := s.editMedia(ctx, listingID, ownerHex, isDealer, func(lst *models.Listing) error {
+		idx, err := findMedia(lst, mediaHex)
+		if err != nil {
+			return err
+		}
+		removed = lst.Media[idx]
+		lst.Media = append(lst.Media[:idx], lst.Media[idx+1:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.discardMedia(ctx, []models.ListingMedia{removed})
+	return updated, nil
+}
+
+// editMedia applies edit to a fresh copy of the listing's gallery and saves
+// it. Should another upload or edit land in between, the save is refused
+// and edit is replayed on the newer gallery rather than overwriting it.
+func (s *listingService) editMedia(
+	ctx context.Context,
+	listingID, ownerHex string,
+	isDealer bool,
+	edit func(lst *models.Listing) error,
+) (*models.Listing, error) {
+	for attempt := 1; ; attempt++ {
+		lst, err := s.loadOwnedListing(ctx, listingID, ownerHex, isDealer)
+		if err != nil {
+			return nil, err
+		}
+		if err := edit(lst); err != nil {
+			return nil, err
+		}
+		updated, err := s.saveMedia(ctx, lst)
+		if errors.Is(err, listingRepo.ErrMediaConflict) && attempt < mediaEditAttempts {
+			continue
+		}
+		return updated, err
+	}
+}
+
+func (s *listingService) loadOwnedListing(ctx context.Context, listingID, ownerHex string, isDealer bool) (*models.Listing, error) {
+	ownerID, err := s.helper.convertAndValidateID(ownerHex)
+	if err != nil {
+		return nil, err
+	}
+	lst, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.helper.authorizeOwner(lst, ownerID, isDealer); err != nil {
+		return nil, err
+	}
+	return lst, nil
+}
+
+func (s *listingService) storeMedia(ctx context.Context, u MediaUpload, folder string) (models.ListingMedia, error) {
+	publicID, err := s.storage.UploadFile(ctx, u.LocalPath, folder)
+	if err != nil {
+		return models.ListingMedia{}, fmt.Errorf("failed to upload photo: %w", err)
+	}
+	m := models.ListingMedia{
+		ID:        primitive.NewObjectID(),
+		PublicID:  publicID,
+		Caption:   u.Caption,
+		CreatedAt: time.Now(),
+	}
+	if m.URL, err = s.storage.GetDownloadURL(ctx, "image", publicID, 0); err != nil {
+		s.discardMedia(ctx, []models.ListingMedia{m})
+		return models.ListingMedia{}, fmt.Errorf("failed to resolve photo URL: %w", err)
+	}
+	if m.ThumbURL, err = s.storage.GetThumbnailURL(ctx, publicID, thumbnailWidth, thumbnailHeight); err != nil {
+		s.discardMedia(ctx, []models.ListingMedia{m})
+		return models.ListingMedia{}, fmt.Errorf("failed to resolve thumbnail URL: %w", err)
+	}
+	return m, nil
+}
+
+// saveMedia normalises positions and the cover flag, then persists the gallery.
+func (s *listingService) saveMedia(ctx context.Context, lst *models.Listing) (*models.Listing, error) {
+	sort.SliceStable(lst.Media, func(i, j int) bool {
+		return lst.Media[i].Position < lst.Media[j].Position
+	})
+
+	coverThumb := ""
+	hasCover := false
+	for i := range lst.Media {
+		lst.Media[i].Position = i
+		if lst.Media[i].IsCover {
+			if hasCover {
+				lst.Media[i].IsCover = false
+				continue
+			}
+			hasCover = true
+			coverThumb = lst.Media[i].ThumbURL
+		}
+	}
+	if !hasCover && len(lst.Media) > 0 {
+		lst.Media[0].IsCover = true
+		coverThumb = lst.Media[0].ThumbURL
+	}
+
+	listingID := lst.ID.Hex()
+	if err := s.repo.SetMedia(ctx, listingID, lst.MediaVersion, lst.Media, coverThumb); err != nil {
+		return nil, fmt.Errorf("failed to save photos: %w", err)
+	}
+	return s.repo.GetListingByID(ctx, listingID)
+}
+
+// discardMedia deletes stored files, logging rather than failing on errors.
+func (s *listingService) discardMedia(ctx context.Context, media []models.ListingMedia) {
+	for _, m := range media {
+		if err := s.storage.DeleteFile(ctx, m.PublicID); err != nil {
+			utils.GetLogger().Warn("failed to delete listing photo",
+				zap.String("publicID", m.PublicID), zap.Error(err))
+		}
+	}
+}
+
+func findMedia(lst *models.Listing, mediaHex string) (int, error) {
+	id, err := primitive.ObjectIDFromHex(mediaHex)
+	if err != nil {
+		return -1, fmt.Errorf("invalid photo ID %q", mediaHex)
+	}
+	for i := range lst.Media {
+		if lst.Media[i].ID == id {
+			return i, nil
+		}
+	}
+	return -1, ErrMediaNotFound
+}
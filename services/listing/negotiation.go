@@ -0,0 +1,275 @@
+package listing
+
+import (
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrBidNotFound          = errors.New("bid not found")
+	ErrNotListingOwner      = fmt.Errorf("%w: only the listing owner can do this", listingRepo.ErrUnauthorizedAction)
+	ErrNotBidOwner          = fmt.Errorf("%w: only the bidding dealer can do this", listingRepo.ErrUnauthorizedAction)
+	ErrInvalidBidTransition = errors.New("bid cannot make that move in its current state")
+)
+
+type bidTransition struct {
+	from   models.BidStatus
+	actor  models.BidActor
+	action models.BidAction
+}
+
+// bidTransitions is the negotiation state machine: pending bids wait on the owner,
+// countered bids wait on the dealer. Withdrawn, rejected and accepted are terminal.
+var bidTransitions = map[bidTransition]models.BidStatus{
+	{models.BidStatusPending, models.BidActorOwner, models.BidActionCounter}:     models.BidStatusCountered,
+	{models.BidStatusPending, models.BidActorOwner, models.BidActionReject}:      models.BidStatusRejected,
+	{models.BidStatusPending, models.BidActorOwner, models.BidActionAccept}:      models.BidStatusAccepted,
+	{models.BidStatusPending, models.BidActorDealer, models.BidActionUpdate}:     models.BidStatusPending,
+	{models.BidStatusPending, models.BidActorDealer, models.BidActionWithdraw}:   models.BidStatusWithdrawn,
+	{models.BidStatusCountered, models.BidActorDealer, models.BidActionCounter}:  models.BidStatusPending,
+	{models.BidStatusCountered, models.BidActorDealer, models.BidActionWithdraw}: models.BidStatusWithdrawn,
+	{models.BidStatusCountered, models.BidActorOwner, models.BidActionReject}:    models.BidStatusRejected,
+}
+
+func nextBidStatus(from models.BidStatus, actor models.BidActor, action models.BidAction) (models.BidStatus, error) {
+	to, ok := bidTransitions[bidTransition{from, actor, action}]
+	if !ok {
+		return "", fmt.Errorf("%w: %s cannot %s a %s bid", ErrInvalidBidTransition, actor, action, from)
+	}
+	return to, nil
+}
+
+// activeBidByDealer returns the dealer's bid that is still under negotiation, if any.
+func activeBidByDealer(lst *models.Listing, dealerID primitive.ObjectID) (*models.Bid, bool) {
+	for i := range lst.UserListing.Bids {
+		b := &lst.UserListing.Bids[i]
+		if b.DealerID == dealerID && (b.Status == models.BidStatusPending || b.Status == models.BidStatusCountered) {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+func findBid(lst *models.Listing, bidID primitive.ObjectID) (*models.Bid, bool) {
+	for i := range lst.UserListing.Bids {
+		if lst.UserListing.Bids[i].ID == bidID {
+			return &lst.UserListing.Bids[i], true
+		}
+	}
+	return nil, false
+}
+
+// CounterBid lets the listing owner answer a pending bid with a higher price.
+func (s *listingService) CounterBid(
+	ctx context.Context,
+	listingID, bidID, userHex string,
+	amount float64,
+	message string,
+) (*models.Listing, error) {
+	return s.negotiate(ctx, listingID, bidID, userHex, models.BidActorOwner, models.BidActionCounter, amount, message)
+}
+
+// RejectBid lets the listing owner end a negotiation.
+func (s *listingService) RejectBid(
+	ctx context.Context,
+	listingID, bidID, userHex, message string,
+) (*models.Listing, error) {
+	return s.negotiate(ctx, listingID, bidID, userHex, models.BidActorOwner, models.BidActionReject, 0, message)
+}
+
+// DealerCounterBid lets the bidding dealer answer the owner's counter with a new offer.
+func (s *listingService) DealerCounterBid(
+	ctx context.Context,
+	listingID, bidID, dealerHex string,
+	amount float64,
+	message string,
+) (*models.Listing, error) {
+	return s.negotiate(ctx, listingID, bidID, dealerHex, models.BidActorDealer, models.BidActionCounter, amount, message)
+}
+
+// UpdateBid lets the bidding dealer revise a bid that is still awaiting the owner.
+func (s *listingService) UpdateBid(
+	ctx context.Context,
+	listingID, bidID, dealerHex string,
+	amount float64,
+	message string,
+) (*models.Listing, error) {
+	return s.negotiate(ctx, listingID, bidID, dealerHex, models.BidActorDealer, models.BidActionUpdate, amount, message)
+}
+
+// WithdrawBid lets the bidding dealer pull out of a negotiation.
+func (s *listingService) WithdrawBid(
+	ctx context.Context,
+	listingID, bidID, dealerHex, message string,
+) (*models.Listing, error) {
+	return s.negotiate(ctx, listingID, bidID, dealerHex, models.BidActorDealer, models.BidActionWithdraw, 0, message)
+}
+
+func (s *listingService) negotiate(
+	ctx context.Context,
+	listingID, bidHex, callerHex string,
+	actor models.BidActor,
+	action models.BidAction,
+	amount float64,
+	message string,
+) (*models.Listing, error) {
+	// 1) Validate IDs & load
+	callerID, err := s.helper.convertAndValidateID(callerHex)
+	if err != nil {
+		return nil, err
+	}
+	bidID, err := s.helper.convertAndValidateID(bidHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bid ID: %w", err)
+	}
+	lst, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if lst.Type != models.ListingTypeUserBid {
+		return nil, listingRepo.ErrInvalidType
+	}
+	if lst.UserListing.Auction != nil {
+		return nil, ErrAuctionManaged
+	}
+	bid, ok := findBid(lst, bidID)
+	if !ok {
+		return nil, ErrBidNotFound
+	}
+
+	// 2) Authorise the caller for their side of the thread
+	switch actor {
+	case models.BidActorOwner:
+		if lst.UserListing.UserID != callerID {
+			return nil, ErrNotListingOwner
+		}
+	case models.BidActorDealer:
+		if bid.DealerID != callerID {
+			return nil, ErrNotBidOwner
+		}
+	}
+
+	// 3) Check the state machine & amounts
+	to, err := nextBidStatus(bid.Status, actor, action)
+	if err != nil {
+		return nil, err
+	}
+	if action == models.BidActionUpdate && amount <= 0 {
+		return nil, errors.New("offer must be positive")
+	}
+	if action == models.BidActionCounter {
+		switch {
+		case amount <= 0:
+			return nil, errors.New("counter offer must be positive")
+		case actor == models.BidActorOwner && amount <= bid.Offer:
+			return nil, fmt.Errorf("counter offer must be above the current offer of %.2f", bid.Offer)
+		case actor == models.BidActorDealer && amount > bid.CounterOffer:
+			return nil, fmt.Errorf("counter offer cannot exceed the owner's counter of %.2f", bid.CounterOffer)
+		}
+	}
+
+	round := models.BidRound{Actor: actor, Action: action, Amount: amount, Message: message}
+	if err := s.repo.ApplyBidRound(ctx, listingID, bidHex, bid.Status, bid.Version, to, round); err != nil {
+		return nil, fmt.Errorf("failed to update bid: %w", err)
+	}
+
+	// 4) Reload & notify the other party
+	updated, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("reload listing: %w", err)
+	}
+	s.recordChange(ctx, models.ListingEventBid, lst, updated)
+
+	car := fmt.Sprintf("%s %s", lst.CarDetails.Make, lst.CarDetails.Model)
+	data := map[string]interface{}{
+		"listingID": listingID,
+		"bidID":     bidHex,
+		"status":    to,
+	}
+	if amount > 0 {
+		data["amount"] = amount
+	}
+
+	switch {
+	case actor == models.BidActorOwner && action == models.BidActionCounter:
+		s.notifyDealer(ctx, bid.DealerID, models.NotificationTypeBidCountered, "Counter Offer Received",
+			fmt.Sprintf("The owner of the %s countered your bid of %.2f with %.2f.", car, bid.Offer, amount), data)
+	case actor == models.BidActorOwner && action == models.BidActionReject:
+		s.notifyDealer(ctx, bid.DealerID, models.NotificationTypeBidRejected, "Bid Rejected",
+			fmt.Sprintf("Your bid of %.2f on the %s was rejected.", bid.Offer, car), data)
+	case actor == models.BidActorDealer && action == models.BidActionCounter:
+		s.notifyUser(ctx, lst.UserListing.UserID, models.NotificationTypeBidCountered, "Dealer Countered",
+			fmt.Sprintf("A dealer answered your counter on the %s with %.2f.", car, amount), data)
+	case actor == models.BidActorDealer && action == models.BidActionUpdate:
+		s.notifyBidRevision(ctx, lst, bid.Offer, amount, data)
+	case actor == models.BidActorDealer && action == models.BidActionWithdraw:
+		s.notifyUser(ctx, lst.UserListing.UserID, models.NotificationTypeBidWithdrawn, "Bid Withdrawn",
+			fmt.Sprintf("A dealer withdrew their bid on your %s.", car), data)
+	}
+
+	return updated, nil
+}
+
+// raiseBid replaces a dealer's live bid with a higher offer placed through AddBid.
+func (s *listingService) raiseBid(
+	ctx context.Context,
+	lst *models.Listing,
+	existing *models.Bid,
+	bid models.Bid,
+) (*models.Listing, error) {
+	if bid.Offer <= existing.Offer {
+		return nil, fmt.Errorf("%w: raise above your current offer of %.2f or update the bid instead",
+			listingRepo.ErrDuplicateBid, existing.Offer)
+	}
+	to, err := nextBidStatus(existing.Status, models.BidActorDealer, models.BidActionUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("%w; answer the owner's counter offer instead", err)
+	}
+
+	listingID := lst.ID.Hex()
+	round := models.BidRound{
+		Actor:   models.BidActorDealer,
+		Action:  models.BidActionUpdate,
+		Amount:  bid.Offer,
+		Message: bid.Message,
+	}
+	if err := s.repo.ApplyBidRound(ctx, listingID, existing.ID.Hex(), existing.Status, existing.Version, to, round); err != nil {
+		return nil, fmt.Errorf("failed to raise bid: %w", s.outbidError(ctx, listingID, bid.Offer, err))
+	}
+
+	updated, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("reload listing: %w", err)
+	}
+	s.recordChange(ctx, models.ListingEventBid, lst, updated)
+
+	s.notifyBidRevision(ctx, lst, existing.Offer, bid.Offer, map[string]interface{}{
+		"listingID": listingID,
+		"bidID":     existing.ID.Hex(),
+		"status":    to,
+		"amount":    bid.Offer,
+	})
+	return updated, nil
+}
+
+// notifyBidRevision tells the owner a dealer changed their offer, flagging raises separately.
+func (s *listingService) notifyBidRevision(
+	ctx context.Context,
+	lst *models.Listing,
+	oldOffer, newOffer float64,
+	data map[string]interface{},
+) {
+	car := fmt.Sprintf("%s %s", lst.CarDetails.Make, lst.CarDetails.Model)
+	if newOffer > oldOffer {
+		s.notifyUser(ctx, lst.UserListing.UserID, models.NotificationTypeBidRaised, "Bid Raised",
+			fmt.Sprintf("A dealer raised their bid on your %s from %.2f to %.2f.", car, oldOffer, newOffer), data)
+		return
+	}
+	s.notifyUser(ctx, lst.UserListing.UserID, models.NotificationTypeBidUpdated, "Bid Updated",
+		fmt.Sprintf("A dealer changed their bid on your %s to %.2f.", car, newOffer), data)
+}
@@ -46,3 +46,76 @@ func (s *listingService) notifyDealer(
 		fmt.Printf("notifyDealer error: %v\n", err)
 	}
 }
+
+// notifyOwner notifies the dealer or user who owns lst.
+func (s *listingService) notifyOwner(
+	ctx context.Context,
+	lst *models.Listing,
+	ntype models.NotificationType,
+	title, body string,
+	data map[string]interface{},
+) {
+	if lst.Type == models.ListingTypeDealer {
+		s.notifyDealer(ctx, lst.DealerListing.DealerID, ntype, title, body, data)
+		return
+	}
+	s.notifyUser(ctx, lst.UserListing.UserID, ntype, title, body, data)
+}
+
+// announceListing runs saved-search alerts for a listing that has just come
+// on the market. Matching scans every saved search on the make, so it runs
+// in the background.
+func (s *listingService) announceListing(lst *models.Listing) {
+	if s.alerts == nil {
+		return
+	}
+	go s.alerts.MatchListing(context.Background(), lst)
+}
+
+// alertWatchers tells the users watching a listing about a price cut or
+// about it going off the market, in the background.
+func (s *listingService) alertWatchers(before, after *models.Listing) {
+	if s.watchers == nil {
+		return
+	}
+	if after.CarDetails.Price > 0 && after.CarDetails.Price < before.CarDetails.Price {
+		go s.watchers.NotifyPriceDrop(context.Background(), after, before.CarDetails.Price)
+	}
+	if before.Status != after.Status && offMarket(after.Status) {
+		go s.watchers.NotifyOffMarket(context.Background(), after)
+	}
+}
+
+// offMarket reports the statuses a listing leaves the market for good in.
+func offMarket(status models.ListingStatus) bool {
+	return status == models.ListingStatusSold || status == models.ListingStatusClosed
+}
+
+// onMarket reports the statuses buyers can find a listing in.
+func onMarket(status models.ListingStatus) bool {
+	return status == models.ListingStatusActive || status == models.ListingStatusOpen
+}
+
+// notifyTransition tells the owner a listing moved to sold or closed, each
+// with its own notification type. Other moves are announced by the flows
+// that make them: publishing, accepting a bid and deposits.
+func (s *listingService) notifyTransition(ctx context.Context, before, after *models.Listing) {
+	car := fmt.Sprintf("%s %s", after.CarDetails.Make, after.CarDetails.Model)
+	var (
+		ntype models.NotificationType
+		title string
+		body  string
+	)
+	switch after.Status {
+	case models.ListingStatusClosed:
+		ntype, title = models.NotificationTypeListingClosed, "Listing Closed"
+		body = fmt.Sprintf("Your %s listing has been closed.", car)
+	case models.ListingStatusSold:
+		ntype, title = models.NotificationTypeListingSold, "Listing Sold"
+		body = fmt.Sprintf("Your %s has been marked sold.", car)
+	default:
+		return
+	}
+	s.notifyOwner(ctx, after, ntype, title, body,
+		map[string]interface{}{"listingID": after.ID.Hex(), "from": before.Status, "to": after.Status})
+}
@@ -0,0 +1,103 @@
+package listing
+
+import (
+	"carsawa/models"
+	"carsawa/utils"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ownerListingFields are what a listing's dealer or seller may patch.
+var ownerListingFields = []string{"carDetails", "status"}
+
+// adminListingFields are what moderators may patch. They correct the
+// description and move the status, but the asking price stays the seller's.
+var adminListingFields = []string{
+	"carDetails.make",
+	"carDetails.model",
+	"carDetails.year",
+	"carDetails.mileage",
+	"carDetails.fuelType",
+	"carDetails.transmission",
+	"carDetails.bodyType",
+	"carDetails.engineSize",
+	"carDetails.driveType",
+	"carDetails.colour",
+	"carDetails.conditionGrade",
+	"carDetails.numberOfOwners",
+	"carDetails.registrationPlate",
+	"carDetails.location",
+	"status",
+}
+
+// authorizePatch checks that whoever is acting on ctx may write every field
+// the patch sets. Dealers and users must also own the listing.
+func (s *listingService) authorizePatch(ctx context.Context, lst *models.Listing, patch models.ListingPatch) error {
+	actor := utils.AuditActorFrom(ctx)
+	allowed := ownerListingFields
+	switch actor.Type {
+	case models.ActorAdmin:
+		allowed = adminListingFields
+	case models.ActorDealer, models.ActorUser:
+		ownerID, err := primitive.ObjectIDFromHex(actor.ID)
+		if err != nil {
+			return ErrNotListingOwner
+		}
+		if err := s.helper.authorizeOwner(lst, ownerID, actor.Type == models.ActorDealer); err != nil {
+			return err
+		}
+	default:
+		return ErrNotListingOwner
+	}
+	return models.CheckPatchFields(models.PatchedFields(&patch), allowed)
+}
+
+// mergeCarDetails applies a car details patch to cd. Null clears an optional
+// field; the identifying ones cannot be cleared.
+func mergeCarDetails(cd *models.CarDetails, p *models.CarDetailsPatch) error {
+	switch {
+	case p.Make.Null:
+		return models.InvalidField("carDetails.make", "is required")
+	case p.Model.Null:
+		return models.InvalidField("carDetails.model", "is required")
+	case p.Year.Null:
+		return models.InvalidField("carDetails.year", "is required")
+	}
+
+	mergeField(&cd.Make, p.Make)
+	mergeField(&cd.Model, p.Model)
+	mergeField(&cd.Year, p.Year)
+	mergeField(&cd.Price, p.Price)
+	mergeField(&cd.Mileage, p.Mileage)
+	mergeField(&cd.FuelType, p.FuelType)
+	mergeField(&cd.Transmission, p.Transmission)
+	mergeField(&cd.BodyType, p.BodyType)
+	mergeField(&cd.EngineSize, p.EngineSize)
+	mergeField(&cd.DriveType, p.DriveType)
+	mergeField(&cd.Colour, p.Colour)
+	mergeField(&cd.ConditionGrade, p.ConditionGrade)
+	mergeField(&cd.NumberOfOwners, p.NumberOfOwners)
+	mergeField(&cd.RegistrationPlate, p.RegistrationPlate)
+	if p.Location.Set {
+		cd.Location = nil
+		if !p.Location.Null {
+			loc := p.Location.Value
+			cd.Location = &loc
+		}
+	}
+	return nil
+}
+
+// mergeField applies one patch field: a value replaces dst and null resets
+// it to its zero value.
+func mergeField[T any](dst *T, p models.Patch[T]) {
+	if !p.Set {
+		return
+	}
+	var zero T
+	*dst = zero
+	if !p.Null {
+		*dst = p.Value
+	}
+}
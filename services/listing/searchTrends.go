@@ -0,0 +1,91 @@
+package listing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"carsawa/models"
+	"carsawa/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	searchEventTTL    = 90 * 24 * time.Hour
+	searchTrendWindow = 7 * 24 * time.Hour
+	defaultTrendLimit = 20
+	maxTrendLimit     = 100
+)
+
+// searchQueryText is what a search is counted under in the trends: its
+// lower-cased text, or the make and model it filtered on when it had none.
+func searchQueryText(query string, filter models.ListingFilter) string {
+	text := strings.Join(strings.Fields(query), " ")
+	if text == "" {
+		text = strings.TrimSpace(filter.Make + " " + filter.Model)
+	}
+	return strings.ToLower(text)
+}
+
+func trendCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+// recordSearch logs a search in the background and gives the result the ID
+// that clicks on it are recorded against.
+func (s *listingService) recordSearch(result *models.SearchResult, query string, filter models.ListingFilter) {
+	now := time.Now()
+	event := &models.SearchEvent{
+		ID:        primitive.NewObjectID(),
+		Kind:      models.SearchEventSearch,
+		Query:     searchQueryText(query, filter),
+		Make:      strings.TrimSpace(filter.Make),
+		Model:     strings.TrimSpace(filter.Model),
+		City:      trendCity(filter.City),
+		Results:   result.Total,
+		CreatedAt: now,
+		ExpiresAt: now.Add(searchEventTTL),
+	}
+	event.SearchID = event.ID
+	result.SearchID = event.ID.Hex()
+
+	go func() {
+		ctx := context.Background()
+		if err := s.repo.RecordSearchQuery(ctx, event); err != nil {
+			utils.GetLogger().Warn("failed to record search", zap.Error(err))
+		}
+		if s.suggest != nil {
+			s.suggest.RecordSearch(ctx, query, filter)
+		}
+	}()
+}
+
+// RecordSearchClick counts a buyer opening a listing from a search's results.
+func (s *listingService) RecordSearchClick(ctx context.Context, searchID, listingID string) error {
+	return s.repo.RecordListingView(ctx, listingID, searchID)
+}
+
+// GetSearchTrends reports what buyers in a city, or anywhere when city is
+// empty, searched for most over the last week, and what found nothing.
+func (s *listingService) GetSearchTrends(ctx context.Context, city string, limit int) (*models.SearchTrends, error) {
+	if limit <= 0 {
+		limit = defaultTrendLimit
+	}
+	if limit > maxTrendLimit {
+		limit = maxTrendLimit
+	}
+	return s.repo.GetSearchTrends(ctx, trendCity(city), limit)
+}
+
+func (s *listingService) ComputeSearchTrends(ctx context.Context) error {
+	return s.repo.ComputeSearchTrends(ctx, time.Now().Add(-searchTrendWindow))
+}
+
+// RunSearchTrendsJob recomputes the search trends straight away and then
+// every interval until ctx is cancelled, on one replica at a time.
+func RunSearchTrendsJob(ctx context.Context, svc ListingService, rdb *redis.Client, interval time.Duration) {
+	utils.RunLockedJob(ctx, rdb, "search-trends", interval, svc.ComputeSearchTrends)
+}
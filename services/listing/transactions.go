@@ -0,0 +1,32 @@
+package listing
+
+import (
+	"carsawa/models"
+	"carsawa/utils"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// openBidSale records the sale behind an accepted bid or a won auction.
+// Failures are logged; the listing change has already been made.
+func (s *listingService) openBidSale(ctx context.Context, lst *models.Listing, bid models.Bid) {
+	if s.txns == nil {
+		return
+	}
+	if _, err := s.txns.OpenForAcceptedBid(ctx, lst, bid); err != nil {
+		utils.GetLogger().Error("failed to open transaction for accepted bid",
+			zap.String("listingID", lst.ID.Hex()), zap.String("bidID", bid.ID.Hex()), zap.Error(err))
+	}
+}
+
+// openDealerSale records a dealer listing that was marked sold.
+func (s *listingService) openDealerSale(ctx context.Context, lst *models.Listing) {
+	if s.txns == nil {
+		return
+	}
+	if _, err := s.txns.OpenForDealerSale(ctx, lst); err != nil {
+		utils.GetLogger().Error("failed to open transaction for dealer sale",
+			zap.String("listingID", lst.ID.Hex()), zap.Error(err))
+	}
+}
@@ -3,6 +3,7 @@ package listing
 import (
 	"carsawa/models"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -34,10 +35,23 @@ func (s *listingService) CreateUserBidListing(
 		CarDetails: listing.CarDetails,
 	}
 
+	NormalizeCarDetails(&toCreate.CarDetails)
+	s.inheritLocation(ctx, toCreate)
 	if err := s.validateCarDetails(ctx, *toCreate); err != nil {
 		return nil, err
 	}
 
+	// optional timed auction
+	if listing.UserListing.Auction != nil {
+		auction := *listing.UserListing.Auction
+		if err := validateAuction(&auction, toCreate.CreatedAt); err != nil {
+			return nil, err
+		}
+		toCreate.UserListing.Auction = &auction
+	} else {
+		toCreate.ExpiresAt = s.lifecycle.expiryFrom(toCreate.Type, toCreate.CreatedAt)
+	}
+
 	// 2) Persist & reload
 	newID, err := s.repo.CreateListing(ctx, toCreate)
 	if err != nil {
@@ -47,17 +61,26 @@ func (s *listingService) CreateUserBidListing(
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch new listing: %w", err)
 	}
+	s.recordChange(ctx, models.ListingEventCreated, nil, lst)
 
 	// 3) Notify the user that their listing is live
+	body := fmt.Sprintf("Your listing for %s %s is now open for dealer bids.",
+		lst.CarDetails.Make, lst.CarDetails.Model)
+	if a := lst.UserListing.Auction; a != nil {
+		body = fmt.Sprintf("Your auction for %s %s runs until %s.",
+			lst.CarDetails.Make, lst.CarDetails.Model, a.EndAt.Format(time.RFC1123))
+	}
 	s.notifyUser(
 		ctx,
 		userID,
 		models.NotificationTypeListingCreated,
 		"Your Bid Listing Is Live",
-		fmt.Sprintf("Your listing for %s %s is now open for dealer bids.",
-			lst.CarDetails.Make, lst.CarDetails.Model),
+		body,
 		map[string]interface{}{"listingID": lst.ID.Hex()},
 	)
+	s.notifyIfFlagged(ctx, lst)
+	s.indexSuggestions(ctx, lst)
+	s.announceListing(lst)
 
 	return lst, nil
 }
@@ -71,8 +94,29 @@ func (s *listingService) AddBid(
 	if _, err := primitive.ObjectIDFromHex(listingID); err != nil {
 		return nil, fmt.Errorf("invalid listing ID: %w", err)
 	}
+	if bid.DealerID.IsZero() {
+		return nil, errors.New("dealer ID is required")
+	}
+	if bid.Offer <= 0 {
+		return nil, errors.New("offer must be positive")
+	}
+	current, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if current.UserListing.Auction != nil {
+		if err := checkAuctionBid(current, bid.Offer, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// A dealer who already has a live bid raises it instead of stacking another.
+	if existing, ok := activeBidByDealer(current, bid.DealerID); ok {
+		return s.raiseBid(ctx, current, existing, bid)
+	}
+
 	if err := s.repo.AddBid(ctx, listingID, bid); err != nil {
-		return nil, fmt.Errorf("failed to add bid: %w", err)
+		return nil, fmt.Errorf("failed to add bid: %w", s.outbidError(ctx, listingID, bid.Offer, err))
 	}
 
 	// 2) Reload the updated listing
@@ -80,6 +124,7 @@ func (s *listingService) AddBid(
 	if err != nil {
 		return nil, fmt.Errorf("reload listing: %w", err)
 	}
+	s.recordChange(ctx, models.ListingEventBid, current, lst)
 
 	// 3) Fetch dealer info (for friendly message)
 	dealer, err := s.dealer.GetDealer(ctx, bid.DealerID.Hex())
@@ -108,13 +153,34 @@ func (s *listingService) AcceptBid(
 	ctx context.Context,
 	listingID, bidID, userHex string,
 ) (*models.Listing, error) {
-	// 1) Validate & accept bid in repo
+	// 1) Validate, authorise & accept bid in repo
 	if _, err := primitive.ObjectIDFromHex(listingID); err != nil {
 		return nil, fmt.Errorf("invalid listing ID: %w", err)
 	}
-	if _, err := primitive.ObjectIDFromHex(bidID); err != nil {
+	bidOID, err := primitive.ObjectIDFromHex(bidID)
+	if err != nil {
 		return nil, fmt.Errorf("invalid bid ID: %w", err)
 	}
+	current, err := s.repo.GetListingByID(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if current.UserListing.Auction != nil {
+		return nil, ErrAuctionManaged
+	}
+	if current.UserListing.UserID.Hex() != userHex {
+		return nil, ErrNotListingOwner
+	}
+	bid, ok := findBid(current, bidOID)
+	if !ok {
+		return nil, ErrBidNotFound
+	}
+	if _, err := nextBidStatus(bid.Status, models.BidActorOwner, models.BidActionAccept); err != nil {
+		return nil, err
+	}
+	if err := s.helper.checkTransition(ctx, current, models.ListingStatusAccepted); err != nil {
+		return nil, err
+	}
 	if err := s.repo.AcceptBid(ctx, listingID, bidID); err != nil {
 		return nil, fmt.Errorf("failed to accept bid: %w", err)
 	}
@@ -124,15 +190,12 @@ func (s *listingService) AcceptBid(
 	if err != nil {
 		return nil, fmt.Errorf("reload listing: %w", err)
 	}
+	s.recordChange(ctx, models.ListingEventBidAccepted, current, lst)
 
 	// 3) Find the accepted bid object
-	var accepted models.Bid
-	bidOID, _ := primitive.ObjectIDFromHex(bidID)
-	for _, b := range lst.UserListing.Bids {
-		if b.ID == bidOID {
-			accepted = b
-			break
-		}
+	accepted := *bid
+	if b, ok := findBid(lst, bidOID); ok {
+		accepted = *b
 	}
 
 	// 4) Fetch user info for friendly message
@@ -153,6 +216,18 @@ func (s *listingService) AcceptBid(
 	}
 
 	s.notifyDealer(ctx, accepted.DealerID, models.NotificationTypeBidAccepted, title, body, data)
+	s.openBidSale(ctx, lst, accepted)
+
+	// 6) Let the other dealers still negotiating know they missed out
+	for _, b := range current.UserListing.Bids {
+		if b.ID == bidOID || (b.Status != models.BidStatusPending && b.Status != models.BidStatusCountered) {
+			continue
+		}
+		s.notifyDealer(ctx, b.DealerID, models.NotificationTypeBidRejected, "Bid Not Accepted",
+			fmt.Sprintf("The owner of the %s %s accepted another offer.", lst.CarDetails.Make, lst.CarDetails.Model),
+			map[string]interface{}{"listingID": listingID, "bidID": b.ID.Hex()},
+		)
+	}
 
 	return lst, nil
 }
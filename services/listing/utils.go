@@ -3,6 +3,8 @@ package listing
 import (
 	listingRepo "carsawa/database/repository/listing"
 	"carsawa/models"
+	"carsawa/utils"
+	"context"
 	"errors"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -24,23 +26,22 @@ func (h *listingHelper) convertAndValidateID(id string) (primitive.ObjectID, err
 	return objID, nil
 }
 
-func (h *listingHelper) isValidStatusTransition(oldStatus, newStatus models.ListingStatus) bool {
-	transitions := map[models.ListingStatus][]models.ListingStatus{
-		models.ListingStatusDraft:    {models.ListingStatusActive, models.ListingStatusClosed},
-		models.ListingStatusActive:   {models.ListingStatusSold, models.ListingStatusClosed},
-		models.ListingStatusOpen:     {models.ListingStatusAccepted, models.ListingStatusClosed},
-		models.ListingStatusAccepted: {models.ListingStatusClosed},
+// authorizeOwner checks that ownerID is the dealer or user that owns the listing.
+func (h *listingHelper) authorizeOwner(lst *models.Listing, ownerID primitive.ObjectID, isDealer bool) error {
+	if isDealer {
+		if lst.Type != models.ListingTypeDealer || lst.DealerListing.DealerID != ownerID {
+			return ErrNotListingOwner
+		}
+		return nil
 	}
-
-	allowed, exists := transitions[oldStatus]
-	if !exists {
-		return false
+	if lst.Type != models.ListingTypeUserBid || lst.UserListing.UserID != ownerID {
+		return ErrNotListingOwner
 	}
+	return nil
+}
 
-	for _, status := range allowed {
-		if status == newStatus {
-			return true
-		}
-	}
-	return false
+// checkTransition checks a status change against the listing state machine
+// for the role of whoever is acting on ctx.
+func (h *listingHelper) checkTransition(ctx context.Context, lst *models.Listing, to models.ListingStatus) error {
+	return listingRepo.CheckTransition(lst.Type, lst.Status, to, utils.AuditActorFrom(ctx).Type)
 }
@@ -2,22 +2,15 @@ package listing
 
 import (
 	"carsawa/models"
+	"carsawa/services/vin"
 	"context"
 	"errors"
-	"fmt"
-	"strconv"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
 
-type listingValidator struct {
-	verifier *NHTSAVerifier
-}
-
-func newListingValidator(verifier *NHTSAVerifier) *listingValidator {
-	return &listingValidator{verifier: verifier}
-}
-
 func (s *listingService) validateCarDetails(
 	ctx context.Context,
 	listing models.Listing,
@@ -25,83 +18,181 @@ func (s *listingService) validateCarDetails(
 	cd := listing.CarDetails
 
 	if cd.VIN == "" {
-		return errors.New("vIN is required")
+		return models.InvalidField("carDetails.vin", "is required")
 	}
 	if cd.Make == "" {
-		return errors.New("make is required")
+		return models.InvalidField("carDetails.make", "is required")
 	}
 	if cd.Model == "" {
-		return errors.New("model is required")
+		return models.InvalidField("carDetails.model", "is required")
 	}
 
 	currentYear := time.Now().Year()
 	if cd.Year < 1886 || cd.Year > currentYear+1 {
-		return errors.New("invalid manufacturing year")
+		return models.InvalidField("carDetails.year", "must be between 1886 and %d", currentYear+1)
+	}
+	if err := ValidateVehicleAttributes(cd); err != nil {
+		return err
 	}
 
 	switch listing.Type {
 	case models.ListingTypeDealer:
 		if cd.Price <= 0 {
-			return errors.New("price must be positive for dealer listings")
+			return models.InvalidField("carDetails.price", "must be positive for dealer listings")
 		}
 	case models.ListingTypeUserBid:
 		if cd.Price != 0 {
-			return errors.New("user bid listings cannot have fixed price")
+			return models.InvalidField("carDetails.price", "user bid listings cannot have a fixed price")
 		}
 	default:
 		return errors.New("invalid listing type")
 	}
 
-	// verify VIN and decode official details
-	res, valid, err := s.verifier.VerifyVIN(ctx, cd.VIN)
+	return s.verifyVIN(ctx, cd)
+}
+
+// verifyVIN decodes the VIN or chassis number and checks the make, model and
+// year the seller entered against it. The decoder works offline first and
+// only consults registries for numbers that pass, so an unreachable registry
+// no longer blocks the listing.
+func (s *listingService) verifyVIN(ctx context.Context, cd models.CarDetails) error {
+	decoded, err := s.vins.Decode(ctx, cd.VIN)
 	if err != nil {
-		return fmt.Errorf("VIN verification failed: %w", err)
+		return err
 	}
-	if !valid {
-		return errors.New("invalid VIN")
+	source := string(decoded.Kind)
+	if decoded.Verified {
+		source = "registry"
 	}
 
 	// compare Make
-	if !strings.EqualFold(res.Make, cd.Make) {
-		return fmt.Errorf("provided make %q does not match registry %q", cd.Make, res.Make)
+	if decoded.Make != "" && !vin.SameMake(decoded.Make, cd.Make) {
+		return models.InvalidField("carDetails.make", "%q does not match %s %q", cd.Make, source, decoded.Make)
 	}
-	// compare Model
-	if !strings.EqualFold(res.Model, cd.Model) {
-		return fmt.Errorf("provided model %q does not match registry %q", cd.Model, res.Model)
+	// compare Model; the offline chassis table only knows the common name
+	if decoded.Verified && decoded.Model != "" && !strings.EqualFold(decoded.Model, cd.Model) {
+		return models.InvalidField("carDetails.model", "%q does not match registry %q", cd.Model, decoded.Model)
 	}
 	// compare Year
-	regYear, err := strconv.Atoi(res.ModelYear)
-	if err != nil {
-		return fmt.Errorf("invalid year from registry: %w", err)
-	}
-	if regYear != cd.Year {
-		return fmt.Errorf("provided year %d does not match registry %d", cd.Year, regYear)
+	if !vin.MatchesYear(decoded, cd.Year) {
+		return models.InvalidField("carDetails.year", "%d does not match %s model year %d", cd.Year, source, decoded.ModelYears[0])
 	}
-
 	return nil
 }
 
-func (s *listingService) validateAndUpdateCarDetails(existing *models.Listing, carDetailsRaw interface{}) error {
+// validateAndUpdateCarDetails merges a car details patch onto the existing
+// details, validates the result and returns the full set to persist.
+func (s *listingService) validateAndUpdateCarDetails(
+	ctx context.Context,
+	existing *models.Listing,
+	patch *models.CarDetailsPatch,
+) (models.CarDetails, error) {
 	updatedCarDetails := existing.CarDetails
-	if carDetailsMap, ok := carDetailsRaw.(map[string]interface{}); ok {
-		if makeVal, ok := carDetailsMap["make"].(string); ok {
-			updatedCarDetails.Make = makeVal
-		}
-		if modelVal, ok := carDetailsMap["model"].(string); ok {
-			updatedCarDetails.Model = modelVal
-		}
-		if yearVal, ok := carDetailsMap["year"].(int); ok {
-			updatedCarDetails.Year = yearVal
-		}
-		if vinVal, ok := carDetailsMap["vin"].(string); ok {
-			updatedCarDetails.VIN = vinVal
-		}
-		if priceVal, ok := carDetailsMap["price"].(float64); ok {
-			updatedCarDetails.Price = priceVal
-		}
+	if existing.CarDetails.Location != nil {
+		loc := *existing.CarDetails.Location
+		updatedCarDetails.Location = &loc
+	}
+	if err := mergeCarDetails(&updatedCarDetails, patch); err != nil {
+		return models.CarDetails{}, err
 	}
+	NormalizeCarDetails(&updatedCarDetails)
 
 	tempListing := *existing
 	tempListing.CarDetails = updatedCarDetails
-	return s.validateCarDetails(context.Background(), tempListing)
+	if err := s.validateCarDetails(ctx, tempListing); err != nil {
+		return models.CarDetails{}, err
+	}
+	return updatedCarDetails, nil
+}
+
+const (
+	maxMileage    = 2_000_000
+	minEngineSize = 50
+	maxEngineSize = 10_000
+	maxOwners     = 20
+	maxColourLen  = 30
+)
+
+// kenyanPlatePattern matches standard Kenyan plates once spaces are removed, e.g. KDA123A.
+var kenyanPlatePattern = regexp.MustCompile(`^K[A-Z]{2}[0-9]{3}[A-Z]?$`)
+
+// NormalizeCarDetails canonicalises free-text and enum fields so filters match exactly.
+func NormalizeCarDetails(cd *models.CarDetails) {
+	cd.VIN = vin.Canonical(cd.VIN)
+	cd.FuelType = models.FuelType(strings.ToLower(strings.TrimSpace(string(cd.FuelType))))
+	cd.Transmission = models.Transmission(strings.ToLower(strings.TrimSpace(string(cd.Transmission))))
+	cd.BodyType = models.BodyType(strings.ToLower(strings.TrimSpace(string(cd.BodyType))))
+	cd.DriveType = models.DriveType(strings.ToLower(strings.TrimSpace(string(cd.DriveType))))
+	cd.ConditionGrade = models.ConditionGrade(strings.ToLower(strings.TrimSpace(string(cd.ConditionGrade))))
+	cd.Colour = strings.ToLower(strings.TrimSpace(cd.Colour))
+	cd.RegistrationPlate = strings.ToUpper(strings.ReplaceAll(cd.RegistrationPlate, " ", ""))
+	if cd.Location != nil {
+		cd.Location.City = strings.TrimSpace(cd.Location.City)
+		cd.Location.Address = strings.TrimSpace(cd.Location.Address)
+		if !cd.Location.GeoPoint.IsZero() {
+			cd.Location.GeoPoint.Type = "Point"
+		}
+	}
+}
+
+// NormalizeListingFilter cases a search filter the way NormalizeCarDetails
+// stores the car, so ?fuelType=Petrol matches a stored "petrol".
+func NormalizeListingFilter(f *models.ListingFilter) {
+	f.Make = strings.TrimSpace(f.Make)
+	f.Model = strings.TrimSpace(f.Model)
+	f.FuelType = models.FuelType(strings.ToLower(strings.TrimSpace(string(f.FuelType))))
+	f.Transmission = models.Transmission(strings.ToLower(strings.TrimSpace(string(f.Transmission))))
+	f.BodyType = models.BodyType(strings.ToLower(strings.TrimSpace(string(f.BodyType))))
+	f.DriveType = models.DriveType(strings.ToLower(strings.TrimSpace(string(f.DriveType))))
+	f.ConditionGrade = models.ConditionGrade(strings.ToLower(strings.TrimSpace(string(f.ConditionGrade))))
+	f.Colour = strings.ToLower(strings.TrimSpace(f.Colour))
+	f.RegistrationPlate = strings.ToUpper(strings.ReplaceAll(f.RegistrationPlate, " ", ""))
+	f.City = strings.TrimSpace(f.City)
+}
+
+// ValidateVehicleAttributes checks the optional descriptive attributes of a car.
+func ValidateVehicleAttributes(cd models.CarDetails) error {
+	if cd.Mileage < 0 || cd.Mileage > maxMileage {
+		return models.InvalidField("carDetails.mileage", "must be between 0 and %d km", maxMileage)
+	}
+	if cd.FuelType != "" && !slices.Contains(models.FuelTypes, cd.FuelType) {
+		return models.InvalidField("carDetails.fuelType", "%q is not one of %v", cd.FuelType, models.FuelTypes)
+	}
+	if cd.Transmission != "" && !slices.Contains(models.Transmissions, cd.Transmission) {
+		return models.InvalidField("carDetails.transmission", "%q is not one of %v", cd.Transmission, models.Transmissions)
+	}
+	if cd.BodyType != "" && !slices.Contains(models.BodyTypes, cd.BodyType) {
+		return models.InvalidField("carDetails.bodyType", "%q is not one of %v", cd.BodyType, models.BodyTypes)
+	}
+	if cd.DriveType != "" && !slices.Contains(models.DriveTypes, cd.DriveType) {
+		return models.InvalidField("carDetails.driveType", "%q is not one of %v", cd.DriveType, models.DriveTypes)
+	}
+	if cd.ConditionGrade != "" && !slices.Contains(models.ConditionGrades, cd.ConditionGrade) {
+		return models.InvalidField("carDetails.conditionGrade", "%q is not one of %v", cd.ConditionGrade, models.ConditionGrades)
+	}
+	if cd.EngineSize != 0 && (cd.EngineSize < minEngineSize || cd.EngineSize > maxEngineSize) {
+		return models.InvalidField("carDetails.engineSize", "must be between %d and %d cc", minEngineSize, maxEngineSize)
+	}
+	if cd.FuelType == models.FuelTypeElectric && cd.EngineSize != 0 {
+		return models.InvalidField("carDetails.engineSize", "electric vehicles cannot have an engine size")
+	}
+	if cd.NumberOfOwners < 0 || cd.NumberOfOwners > maxOwners {
+		return models.InvalidField("carDetails.numberOfOwners", "must be between 0 and %d", maxOwners)
+	}
+	if cd.ConditionGrade == models.ConditionNew && cd.NumberOfOwners > 0 {
+		return models.InvalidField("carDetails.numberOfOwners", "new vehicles cannot have previous owners")
+	}
+	if len(cd.Colour) > maxColourLen {
+		return models.InvalidField("carDetails.colour", "must be at most %d characters", maxColourLen)
+	}
+	if cd.RegistrationPlate != "" && !kenyanPlatePattern.MatchString(cd.RegistrationPlate) {
+		return models.InvalidField("carDetails.registrationPlate", "%q is not a Kenyan plate", cd.RegistrationPlate)
+	}
+	if cd.Location != nil && cd.Location.City == "" {
+		return models.InvalidField("carDetails.location.city", "is required")
+	}
+	if cd.Location != nil && !cd.Location.GeoPoint.IsZero() && !cd.Location.GeoPoint.Valid() {
+		return models.InvalidField("carDetails.location.geoPoint.coordinates", "must be [longitude, latitude]")
+	}
+	return nil
 }
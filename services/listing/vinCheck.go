@@ -0,0 +1,107 @@
+package listing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/models"
+)
+
+// Thresholds for calling a VIN cluster suspicious.
+const (
+	suspiciousPriceGapPct = 30.0 // Asking prices for the same car this far apart
+	mileageRollbackKm     = 1000 // Odometer readings that fall by more than this
+)
+
+// notifyIfFlagged explains a moderation flag to the listing's owner.
+func (s *listingService) notifyIfFlagged(ctx context.Context, lst *models.Listing) {
+	m := lst.Moderation
+	if m == nil || m.Status != models.ModerationStatusFlagged || len(m.Flags) == 0 {
+		return
+	}
+	flag := m.Flags[len(m.Flags)-1]
+	title := fmt.Sprintf("%s %s Flagged for Review", lst.CarDetails.Make, lst.CarDetails.Model)
+	data := map[string]interface{}{"listingID": lst.ID.Hex(), "reason": flag.Reason}
+
+	if lst.Type == models.ListingTypeDealer {
+		s.notifyDealer(ctx, lst.DealerListing.DealerID, models.NotificationTypeListingFlagged, title, flag.Message, data)
+	} else {
+		s.notifyUser(ctx, lst.UserListing.UserID, models.NotificationTypeListingFlagged, title, flag.Message, data)
+	}
+}
+
+// GetSuspiciousVINClusters lists VINs carried by more than one listing that
+// show signs of fraud: several live at once, the same car offered both by a
+// user and a dealer, a large price gap, an odometer rollback or conflicting
+// model years. The repository picks the clusters; each is given its reasons
+// here.
+func (s *listingService) GetSuspiciousVINClusters(ctx context.Context, pagination models.Pagination) ([]models.VINCluster, error) {
+	clusters, err := s.repo.GetSuspiciousVINClusters(ctx, listingRepo.VINSuspicion{
+		PriceGapPct: suspiciousPriceGapPct,
+		RollbackKm:  mileageRollbackKm,
+	}, pagination)
+	if err != nil {
+		return nil, err
+	}
+	for i := range clusters {
+		analyseVINCluster(&clusters[i])
+	}
+	return clusters, nil
+}
+
+// analyseVINCluster fills in the reasons a cluster looks suspicious. Listings
+// arrive oldest first.
+func analyseVINCluster(c *models.VINCluster) {
+	if c.LiveCount > 1 {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("%d listings live at once", c.LiveCount))
+	}
+
+	var hasDealer, hasUser bool
+	minPrice, maxPrice := math.MaxFloat64, 0.0
+	years := map[int]bool{}
+	for i, l := range c.Listings {
+		switch l.Type {
+		case models.ListingTypeDealer:
+			hasDealer = true
+		case models.ListingTypeUserBid:
+			hasUser = true
+		}
+		if l.Price > 0 {
+			minPrice = math.Min(minPrice, l.Price)
+			maxPrice = math.Max(maxPrice, l.Price)
+		}
+		years[l.Year] = true
+
+		// Compare against the highest reading seen before this listing.
+		for _, prev := range c.Listings[:i] {
+			if drop := prev.Mileage - l.Mileage; drop > c.MileageRollbackKm {
+				c.MileageRollbackKm = drop
+			}
+		}
+	}
+
+	if hasDealer && hasUser {
+		c.Reasons = append(c.Reasons, "listed by both a private seller and a dealer")
+	}
+	if maxPrice > 0 && minPrice < maxPrice {
+		c.PriceGapPercent = math.Round((maxPrice-minPrice)/minPrice*1000) / 10
+		if c.PriceGapPercent >= suspiciousPriceGapPct {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("asking prices differ by %.1f%%", c.PriceGapPercent))
+		}
+	}
+	if c.MileageRollbackKm > mileageRollbackKm {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("mileage rolled back by %d km", c.MileageRollbackKm))
+	} else {
+		c.MileageRollbackKm = 0
+	}
+	if len(years) > 1 {
+		for y := range years {
+			c.Years = append(c.Years, y)
+		}
+		sort.Ints(c.Years)
+		c.Reasons = append(c.Reasons, fmt.Sprintf("conflicting years %v", c.Years))
+	}
+}
@@ -67,44 +67,64 @@ func (s *notificationService) sendNotification(
 }
 
 func (s *notificationService) GetUserNotifications(
-	ctx context.Context, userID string, page, limit int,
-) ([]models.Notification, error) {
-	return s.fetchNotifications(ctx, models.NotificationTargetUser, userID, page, limit)
+	ctx context.Context, userID string, pagination models.Pagination,
+) ([]models.Notification, models.PageCursors, error) {
+	return s.fetchNotifications(ctx, models.NotificationTargetUser, userID, pagination)
 }
 
 func (s *notificationService) GetDealerNotifications(
-	ctx context.Context, dealerID string, page, limit int,
-) ([]models.Notification, error) {
-	return s.fetchNotifications(ctx, models.NotificationTargetDealer, dealerID, page, limit)
+	ctx context.Context, dealerID string, pagination models.Pagination,
+) ([]models.Notification, models.PageCursors, error) {
+	return s.fetchNotifications(ctx, models.NotificationTargetDealer, dealerID, pagination)
 }
 
 func (s *notificationService) fetchNotifications(
 	ctx context.Context,
 	target models.NotificationTarget,
 	recipientHex string,
-	page, limit int,
-) ([]models.Notification, error) {
+	pagination models.Pagination,
+) ([]models.Notification, models.PageCursors, error) {
 	recipientID, err := primitive.ObjectIDFromHex(recipientHex)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidRecipientID, recipientHex)
+		return nil, models.PageCursors{}, fmt.Errorf("%w: %s", ErrInvalidRecipientID, recipientHex)
 	}
-	if page < 1 {
-		page = 1
+	if pagination.Offset < 0 {
+		pagination.Offset = 0
 	}
-	if limit < 1 || limit > 100 {
-		limit = 20
+	if pagination.Limit < 1 || pagination.Limit > 100 {
+		pagination.Limit = 20
 	}
-	return s.repo.FindByRecipient(ctx, recipientID, target, page, limit)
+	return s.repo.FindByRecipient(ctx, recipientID, target, pagination)
 }
 
-func (s *notificationService) MarkNotificationRead(
-	ctx context.Context, notificationHex string,
+func (s *notificationService) MarkUserNotificationRead(
+	ctx context.Context, userID, notificationHex string,
 ) error {
+	return s.markRead(ctx, models.NotificationTargetUser, userID, notificationHex)
+}
+
+func (s *notificationService) MarkDealerNotificationRead(
+	ctx context.Context, dealerID, notificationHex string,
+) error {
+	return s.markRead(ctx, models.NotificationTargetDealer, dealerID, notificationHex)
+}
+
+// markRead marks one of the recipient's notifications read. Someone else's
+// notification is reported as not found.
+func (s *notificationService) markRead(
+	ctx context.Context,
+	target models.NotificationTarget,
+	recipientHex, notificationHex string,
+) error {
+	recipientID, err := primitive.ObjectIDFromHex(recipientHex)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRecipientID, recipientHex)
+	}
 	notifID, err := primitive.ObjectIDFromHex(notificationHex)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrInvalidRecipientID, notificationHex)
 	}
-	err = s.repo.MarkOneRead(ctx, notifID)
+	err = s.repo.MarkOneRead(ctx, notifID, recipientID, target)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return ErrNotificationNotFound
 	}
@@ -18,10 +18,11 @@ type NotificationService interface {
 	CreateUserNotification(ctx context.Context, userID string, nt models.NotificationType, title, body string, data map[string]interface{}) error
 	CreateDealerNotification(ctx context.Context, dealerID string, nt models.NotificationType, title, body string, data map[string]interface{}) error
 
-	GetUserNotifications(ctx context.Context, userID string, page, limit int) ([]models.Notification, error)
-	GetDealerNotifications(ctx context.Context, dealerID string, page, limit int) ([]models.Notification, error)
+	GetUserNotifications(ctx context.Context, userID string, pagination models.Pagination) ([]models.Notification, models.PageCursors, error)
+	GetDealerNotifications(ctx context.Context, dealerID string, pagination models.Pagination) ([]models.Notification, models.PageCursors, error)
 
-	MarkNotificationRead(ctx context.Context, notificationID string) error
+	MarkUserNotificationRead(ctx context.Context, userID, notificationID string) error
+	MarkDealerNotificationRead(ctx context.Context, dealerID, notificationID string) error
 	MarkAllUserNotificationsRead(ctx context.Context, userID string) error
 	MarkAllDealerNotificationsRead(ctx context.Context, dealerID string) error
 }
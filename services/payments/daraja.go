@@ -0,0 +1,325 @@
+package payments
+
+import (
+	"bytes"
+	"carsawa/models"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DarajaSandboxURL    = "https://sandbox.safaricom.co.ke"
+	DarajaProductionURL = "https://api.safaricom.co.ke"
+
+	darajaTimestampLayout = "20060102150405"
+	// Daraja answers status queries with this error code until the customer
+	// has responded to the prompt.
+	darajaStillProcessing = "500.001.1001"
+	maxAccountRefLen      = 12
+	maxDescriptionLen     = 13
+)
+
+// darajaResultSuccess is the only successful result code; 1032 (cancelled by
+// the customer), 1037 (phone unreachable) and the rest are failures.
+const darajaResultSuccess = 0
+
+var eatLocation = time.FixedZone("EAT", 3*60*60)
+
+type DarajaConfig struct {
+	BaseURL        string // DarajaSandboxURL or DarajaProductionURL
+	ConsumerKey    string
+	ConsumerSecret string
+	ShortCode      string // Paybill or till number
+	Passkey        string
+	TillNumber     bool // Use CustomerBuyGoodsOnline instead of CustomerPayBillOnline
+}
+
+// DarajaGateway sends M-Pesa Express (STK push) requests through Safaricom's
+// Daraja API.
+type DarajaGateway struct {
+	cfg    DarajaConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func NewDarajaGateway(cfg DarajaConfig) (*DarajaGateway, error) {
+	if cfg.ConsumerKey == "" || cfg.ConsumerSecret == "" || cfg.ShortCode == "" || cfg.Passkey == "" {
+		return nil, errors.New("DarajaGateway: consumer key, consumer secret, short code and passkey are required")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DarajaSandboxURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &DarajaGateway{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (g *DarajaGateway) Name() string { return "mpesa" }
+
+func (g *DarajaGateway) InitiateSTKPush(ctx context.Context, req STKPushRequest) (*STKPushResponse, error) {
+	if req.Amount < 1 {
+		return nil, errors.New("DarajaGateway: amount must be at least 1")
+	}
+	password, timestamp := g.password()
+	txType := "CustomerPayBillOnline"
+	if g.cfg.TillNumber {
+		txType = "CustomerBuyGoodsOnline"
+	}
+
+	payload := map[string]interface{}{
+		"BusinessShortCode": g.cfg.ShortCode,
+		"Password":          password,
+		"Timestamp":         timestamp,
+		"TransactionType":   txType,
+		"Amount":            req.Amount,
+		"PartyA":            req.PhoneNumber,
+		"PartyB":            g.cfg.ShortCode,
+		"PhoneNumber":       req.PhoneNumber,
+		"CallBackURL":       req.CallbackURL,
+		"AccountReference":  truncate(req.AccountReference, maxAccountRefLen),
+		"TransactionDesc":   truncate(req.Description, maxDescriptionLen),
+	}
+
+	var resp struct {
+		MerchantRequestID   string `json:"MerchantRequestID"`
+		CheckoutRequestID   string `json:"CheckoutRequestID"`
+		ResponseCode        string `json:"ResponseCode"`
+		ResponseDescription string `json:"ResponseDescription"`
+		CustomerMessage     string `json:"CustomerMessage"`
+	}
+	if err := g.post(ctx, "/mpesa/stkpush/v1/processrequest", payload, &resp); err != nil {
+		return nil, err
+	}
+	if resp.ResponseCode != "0" {
+		return nil, fmt.Errorf("DarajaGateway: STK push rejected: %s", resp.ResponseDescription)
+	}
+	return &STKPushResponse{
+		MerchantRequestID: resp.MerchantRequestID,
+		CheckoutRequestID: resp.CheckoutRequestID,
+		CustomerMessage:   resp.CustomerMessage,
+	}, nil
+}
+
+func (g *DarajaGateway) QueryStatus(ctx context.Context, checkoutRequestID string) (*GatewayResult, error) {
+	password, timestamp := g.password()
+	payload := map[string]interface{}{
+		"BusinessShortCode": g.cfg.ShortCode,
+		"Password":          password,
+		"Timestamp":         timestamp,
+		"CheckoutRequestID": checkoutRequestID,
+	}
+
+	var resp struct {
+		ResponseCode string `json:"ResponseCode"`
+		ResultCode   string `json:"ResultCode"`
+		ResultDesc   string `json:"ResultDesc"`
+	}
+	err := g.post(ctx, "/mpesa/stkpushquery/v1/query", payload, &resp)
+	var apiErr *darajaError
+	if errors.As(err, &apiErr) && apiErr.Code == darajaStillProcessing {
+		return &GatewayResult{CheckoutRequestID: checkoutRequestID, Status: models.PaymentStatusPending}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := strconv.Atoi(resp.ResultCode)
+	if err != nil {
+		return nil, fmt.Errorf("DarajaGateway: unexpected result code %q", resp.ResultCode)
+	}
+	// The query does not return the receipt; it arrives on the callback, so a
+	// success found here is recorded without one.
+	return &GatewayResult{
+		CheckoutRequestID: checkoutRequestID,
+		Status:            resultStatus(code),
+		ResultCode:        code,
+		ResultDesc:        resp.ResultDesc,
+	}, nil
+}
+
+func (g *DarajaGateway) ParseCallback(body []byte) (*GatewayResult, error) {
+	return parseSTKCallback(body)
+}
+
+// stkCallback is the body M-Pesa posts to the callback URL.
+type stkCallback struct {
+	Body struct {
+		StkCallback struct {
+			MerchantRequestID string `json:"MerchantRequestID"`
+			CheckoutRequestID string `json:"CheckoutRequestID"`
+			ResultCode        int    `json:"ResultCode"`
+			ResultDesc        string `json:"ResultDesc"`
+			CallbackMetadata  struct {
+				Item []struct {
+					Name  string          `json:"Name"`
+					Value json.RawMessage `json:"Value"`
+				} `json:"Item"`
+			} `json:"CallbackMetadata"`
+		} `json:"stkCallback"`
+	} `json:"Body"`
+}
+
+func parseSTKCallback(body []byte) (*GatewayResult, error) {
+	var cb stkCallback
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return nil, fmt.Errorf("invalid STK callback: %w", err)
+	}
+	stk := cb.Body.StkCallback
+	if stk.CheckoutRequestID == "" {
+		return nil, errors.New("invalid STK callback: missing CheckoutRequestID")
+	}
+
+	res := &GatewayResult{
+		CheckoutRequestID: stk.CheckoutRequestID,
+		Status:            resultStatus(stk.ResultCode),
+		ResultCode:        stk.ResultCode,
+		ResultDesc:        stk.ResultDesc,
+	}
+	for _, item := range stk.CallbackMetadata.Item {
+		raw := strings.Trim(string(item.Value), `"`)
+		switch item.Name {
+		case "Amount":
+			res.Amount, _ = strconv.ParseFloat(raw, 64)
+		case "MpesaReceiptNumber":
+			res.Receipt = raw
+		case "PhoneNumber":
+			res.PhoneNumber = raw
+		case "TransactionDate":
+			if t, err := time.ParseInLocation(darajaTimestampLayout, raw, eatLocation); err == nil {
+				res.PaidAt = t
+			}
+		}
+	}
+	return res, nil
+}
+
+func resultStatus(code int) models.PaymentStatus {
+	if code == darajaResultSuccess {
+		return models.PaymentStatusSucceeded
+	}
+	return models.PaymentStatusFailed
+}
+
+// password is base64(shortcode + passkey + timestamp), with the timestamp in
+// Nairobi time as Daraja requires.
+func (g *DarajaGateway) password() (string, string) {
+	timestamp := time.Now().In(eatLocation).Format(darajaTimestampLayout)
+	return base64.StdEncoding.EncodeToString([]byte(g.cfg.ShortCode + g.cfg.Passkey + timestamp)), timestamp
+}
+
+// accessToken returns a cached OAuth token, fetching a new one shortly before
+// the old one expires.
+func (g *DarajaGateway) accessToken(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && time.Now().Before(g.tokenExpiry) {
+		return g.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		g.cfg.BaseURL+"/oauth/v1/generate?grant_type=client_credentials", nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(g.cfg.ConsumerKey, g.cfg.ConsumerSecret)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("DarajaGateway: token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("DarajaGateway: token request returned %s", resp.Status)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("DarajaGateway: invalid token response: %w", err)
+	}
+	ttl, _ := strconv.Atoi(tok.ExpiresIn)
+	if ttl <= 0 {
+		ttl = 3599
+	}
+	g.token = tok.AccessToken
+	g.tokenExpiry = time.Now().Add(time.Duration(ttl)*time.Second - time.Minute)
+	return g.token, nil
+}
+
+type darajaError struct {
+	Status  int
+	Code    string `json:"errorCode"`
+	Message string `json:"errorMessage"`
+}
+
+func (e *darajaError) Error() string {
+	return fmt.Sprintf("DarajaGateway: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+func (g *DarajaGateway) post(ctx context.Context, path string, payload, out interface{}) error {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.cfg.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("DarajaGateway: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &darajaError{Status: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Code == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			g.mu.Lock()
+			g.token = ""
+			g.mu.Unlock()
+		}
+		return apiErr
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("DarajaGateway: invalid response: %w", err)
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,122 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	paymentRepo "carsawa/database/repository/payment"
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InitiateDeposit sends an M-Pesa prompt for the deposit on an active dealer
+// listing. Retrying with the same idempotency key returns the first payment
+// instead of prompting the customer again.
+func (s *paymentService) InitiateDeposit(
+	ctx context.Context,
+	userHex string,
+	req DepositRequest,
+	idempotencyKey string,
+) (*models.Payment, error) {
+	// 1) Validate the payer and replay earlier attempts
+	userID, err := primitive.ObjectIDFromHex(userHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	phone, err := NormalizeMSISDN(req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if idempotencyKey != "" {
+		if p, err := s.repo.GetPaymentByIdempotencyKey(ctx, userID, idempotencyKey); err == nil {
+			return p, nil
+		} else if !errors.Is(err, paymentRepo.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	// 2) Only cars still on sale can be held
+	lst, err := s.listings.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return nil, err
+	}
+	if lst.Type != models.ListingTypeDealer || lst.Status != models.ListingStatusActive || lst.CarDetails.Price <= 0 {
+		return nil, ErrNotReservable
+	}
+	amount := s.depositFor(lst.CarDetails.Price)
+
+	// 3) Persist before prompting so the callback always finds the payment
+	p := &models.Payment{
+		ListingID:      lst.ID,
+		Payer:          models.TransactionParty{ID: userID, Type: models.PartyTypeUser},
+		Purpose:        models.PaymentPurposeDeposit,
+		Amount:         amount,
+		Currency:       models.CurrencyKES,
+		PhoneNumber:    phone,
+		Gateway:        s.gateway.Name(),
+		IdempotencyKey: idempotencyKey,
+		Status:         models.PaymentStatusPending,
+	}
+	newID, err := s.repo.CreatePayment(ctx, p)
+	if errors.Is(err, paymentRepo.ErrDuplicatePayment) {
+		// A concurrent retry won the insert.
+		return s.repo.GetPaymentByIdempotencyKey(ctx, userID, idempotencyKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 4) Prompt the customer's phone
+	resp, err := s.gateway.InitiateSTKPush(ctx, STKPushRequest{
+		PhoneNumber:      phone,
+		Amount:           int(amount),
+		AccountReference: lst.ID.Hex()[16:],
+		Description:      "Car deposit",
+		CallbackURL:      s.signer.CallbackURL(newID),
+	})
+	if err != nil {
+		_ = s.repo.Complete(ctx, newID, paymentRepo.PaymentResult{
+			Status:     models.PaymentStatusFailed,
+			ResultDesc: err.Error(),
+		})
+		return nil, fmt.Errorf("%w: %v", ErrGatewayFailed, err)
+	}
+	if err := s.repo.SetCheckout(ctx, newID, resp.MerchantRequestID, resp.CheckoutRequestID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPaymentByID(ctx, newID)
+}
+
+// depositFor rounds the deposit up to whole shillings, as M-Pesa requires.
+func (s *paymentService) depositFor(price float64) float64 {
+	amount := math.Ceil(price * s.cfg.DepositPercent / 100)
+	if amount < s.cfg.MinDeposit {
+		amount = math.Ceil(s.cfg.MinDeposit)
+	}
+	if amount > price {
+		amount = math.Ceil(price)
+	}
+	return amount
+}
+
+func (s *paymentService) GetPayment(ctx context.Context, id, userHex string) (*models.Payment, error) {
+	p, err := s.repo.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Payer.ID.Hex() != userHex {
+		return nil, ErrNotPayer
+	}
+	return p, nil
+}
+
+func (s *paymentService) GetUserPayments(ctx context.Context, userHex string, pagination models.Pagination) ([]models.Payment, error) {
+	userID, err := primitive.ObjectIDFromHex(userHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+	return s.repo.GetPaymentsByPayer(ctx, userID, pagination)
+}
@@ -0,0 +1,149 @@
+package payments
+
+import (
+	"bytes"
+	"carsawa/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrUnknownCheckout = errors.New("unknown checkout request")
+
+// FakeGateway is an in-process stand-in for M-Pesa used in development and
+// tests. Pushes stay pending until Complete is called or, when autoComplete
+// is set, succeed on their own and post a Daraja-shaped callback.
+type FakeGateway struct {
+	autoComplete time.Duration
+	client       *http.Client
+	prefix       string // Keeps IDs unique across restarts
+
+	mu     sync.Mutex
+	seq    int
+	pushes map[string]*fakePush
+}
+
+type fakePush struct {
+	seq    int
+	req    STKPushRequest
+	result GatewayResult
+}
+
+func NewFakeGateway(autoComplete time.Duration) *FakeGateway {
+	return &FakeGateway{
+		autoComplete: autoComplete,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		prefix:       strings.ToUpper(strconv.FormatInt(time.Now().Unix(), 36)),
+		pushes:       make(map[string]*fakePush),
+	}
+}
+
+func (g *FakeGateway) Name() string { return "fake" }
+
+func (g *FakeGateway) InitiateSTKPush(ctx context.Context, req STKPushRequest) (*STKPushResponse, error) {
+	if req.Amount < 1 {
+		return nil, errors.New("FakeGateway: amount must be at least 1")
+	}
+
+	g.mu.Lock()
+	g.seq++
+	checkoutID := fmt.Sprintf("ws_CO_FAKE_%s_%06d", g.prefix, g.seq)
+	merchantID := fmt.Sprintf("FAKE-%s-%06d", g.prefix, g.seq)
+	g.pushes[checkoutID] = &fakePush{
+		seq: g.seq,
+		req: req,
+		result: GatewayResult{
+			CheckoutRequestID: checkoutID,
+			Status:            models.PaymentStatusPending,
+			PhoneNumber:       req.PhoneNumber,
+		},
+	}
+	g.mu.Unlock()
+
+	if g.autoComplete > 0 {
+		go func() {
+			time.Sleep(g.autoComplete)
+			body, err := g.Complete(checkoutID, true)
+			if err != nil || req.CallbackURL == "" {
+				return
+			}
+			resp, err := g.client.Post(req.CallbackURL, "application/json", bytes.NewReader(body))
+			if err != nil {
+				fmt.Printf("FakeGateway callback error: %v\n", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+
+	return &STKPushResponse{
+		MerchantRequestID: merchantID,
+		CheckoutRequestID: checkoutID,
+		CustomerMessage:   "Success. Request accepted for processing",
+	}, nil
+}
+
+func (g *FakeGateway) QueryStatus(ctx context.Context, checkoutRequestID string) (*GatewayResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.pushes[checkoutRequestID]
+	if !ok {
+		return nil, ErrUnknownCheckout
+	}
+	res := p.result
+	return &res, nil
+}
+
+func (g *FakeGateway) ParseCallback(body []byte) (*GatewayResult, error) {
+	return parseSTKCallback(body)
+}
+
+// Complete settles a pending push as paid or cancelled by the customer and
+// returns the callback body M-Pesa would post for it.
+func (g *FakeGateway) Complete(checkoutRequestID string, success bool) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.pushes[checkoutRequestID]
+	if !ok {
+		return nil, ErrUnknownCheckout
+	}
+
+	now := time.Now().In(eatLocation)
+	if p.result.Status == models.PaymentStatusPending {
+		if success {
+			p.result.Status = models.PaymentStatusSucceeded
+			p.result.ResultDesc = "The service request is processed successfully."
+			p.result.Receipt = fmt.Sprintf("FK%s%04d", g.prefix, p.seq)
+			p.result.Amount = float64(p.req.Amount)
+			p.result.PaidAt = now
+		} else {
+			p.result.Status = models.PaymentStatusFailed
+			p.result.ResultCode = 1032
+			p.result.ResultDesc = "Request cancelled by user"
+		}
+	}
+
+	stk := map[string]interface{}{
+		"MerchantRequestID": "FAKE",
+		"CheckoutRequestID": checkoutRequestID,
+		"ResultCode":        p.result.ResultCode,
+		"ResultDesc":        p.result.ResultDesc,
+	}
+	if p.result.Status == models.PaymentStatusSucceeded {
+		stk["CallbackMetadata"] = map[string]interface{}{
+			"Item": []map[string]interface{}{
+				{"Name": "Amount", "Value": p.result.Amount},
+				{"Name": "MpesaReceiptNumber", "Value": p.result.Receipt},
+				{"Name": "TransactionDate", "Value": p.result.PaidAt.Format(darajaTimestampLayout)},
+				{"Name": "PhoneNumber", "Value": p.req.PhoneNumber},
+			},
+		}
+	}
+	return json.Marshal(map[string]interface{}{"Body": map[string]interface{}{"stkCallback": stk}})
+}
@@ -0,0 +1,62 @@
+package payments
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Gateway is a mobile-money provider able to prompt a customer's phone for
+// payment. Daraja talks to Safaricom M-Pesa; FakeGateway runs in process.
+type Gateway interface {
+	Name() string
+	// InitiateSTKPush sends the payment prompt. Acceptance only means the
+	// prompt was sent; the outcome arrives on the callback.
+	InitiateSTKPush(ctx context.Context, req STKPushRequest) (*STKPushResponse, error)
+	// QueryStatus asks the provider for the outcome of an earlier push.
+	QueryStatus(ctx context.Context, checkoutRequestID string) (*GatewayResult, error)
+	// ParseCallback decodes the body the provider posts to the callback URL.
+	ParseCallback(body []byte) (*GatewayResult, error)
+}
+
+type STKPushRequest struct {
+	PhoneNumber      string // MSISDN in 2547XXXXXXXX form
+	Amount           int    // Whole shillings; M-Pesa does not take cents
+	AccountReference string
+	Description      string
+	CallbackURL      string
+}
+
+type STKPushResponse struct {
+	MerchantRequestID string
+	CheckoutRequestID string
+	CustomerMessage   string
+}
+
+// GatewayResult is the provider's view of a payment. Status is pending while
+// the customer has not yet answered the prompt.
+type GatewayResult struct {
+	CheckoutRequestID string
+	Status            models.PaymentStatus
+	ResultCode        int
+	ResultDesc        string
+	Receipt           string
+	Amount            float64
+	PhoneNumber       string
+	PaidAt            time.Time
+}
+
+// NewGateway builds the gateway named by kind: "mpesa" for Daraja, or "fake"
+// for the in-process gateway that settles pushes after fakeDelay.
+func NewGateway(kind string, daraja DarajaConfig, fakeDelay time.Duration) (Gateway, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "", "fake":
+		return NewFakeGateway(fakeDelay), nil
+	case "mpesa", "daraja":
+		return NewDarajaGateway(daraja)
+	default:
+		return nil, fmt.Errorf("payments: unknown gateway %q", kind)
+	}
+}
@@ -0,0 +1,85 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	listingRepo "carsawa/database/repository/listing"
+	paymentRepo "carsawa/database/repository/payment"
+	"carsawa/models"
+	"carsawa/services/ledger"
+	"carsawa/services/notification"
+	"carsawa/services/transaction"
+)
+
+var (
+	ErrNotPayer         = fmt.Errorf("%w: only the payer can view this payment", listingRepo.ErrUnauthorizedAction)
+	ErrNotReservable    = errors.New("listing is not available for a deposit")
+	ErrCheckoutMismatch = errors.New("callback does not belong to this payment")
+	ErrGatewayFailed    = errors.New("payment prompt could not be sent")
+)
+
+const defaultPendingExpiry = 15 * time.Minute
+
+type PaymentService interface {
+	InitiateDeposit(ctx context.Context, userID string, req DepositRequest, idempotencyKey string) (*models.Payment, error)
+	GetPayment(ctx context.Context, id, userID string) (*models.Payment, error)
+	GetUserPayments(ctx context.Context, userID string, pagination models.Pagination) ([]models.Payment, error)
+
+	// HandleCallback applies a gateway result posted to a signed callback URL.
+	// Repeated callbacks for a settled payment are accepted and ignored.
+	HandleCallback(ctx context.Context, paymentID, signature string, body []byte) error
+	// ReconcilePending asks the gateway about payments whose callback never came
+	// and finishes cleared deposits a failed settlement left unapplied.
+	ReconcilePending(ctx context.Context) error
+}
+
+// DepositRequest asks for an STK push to hold a dealer's car.
+type DepositRequest struct {
+	ListingID   string `json:"listingId" binding:"required"`
+	PhoneNumber string `json:"phoneNumber" binding:"required"`
+}
+
+type Config struct {
+	DepositPercent float64       // Share of the listing price taken as a deposit
+	MinDeposit     float64       // Floor in shillings for cheap cars
+	PendingExpiry  time.Duration // Pending payments older than this are failed by reconciliation
+}
+
+type paymentService struct {
+	repo     paymentRepo.PaymentRepository
+	listings listingRepo.ListingRepository
+	txns     transaction.TransactionService
+	ledger   ledger.LedgerService
+	gateway  Gateway
+	signer   *CallbackSigner
+	notifier notification.NotificationService
+	cfg      Config
+}
+
+func NewPaymentService(
+	repo paymentRepo.PaymentRepository,
+	listings listingRepo.ListingRepository,
+	txns transaction.TransactionService,
+	ledgerSvc ledger.LedgerService,
+	gateway Gateway,
+	signer *CallbackSigner,
+	notifSvc notification.NotificationService,
+	cfg Config,
+) PaymentService {
+	if cfg.PendingExpiry <= 0 {
+		cfg.PendingExpiry = defaultPendingExpiry
+	}
+	return &paymentService{
+		repo:     repo,
+		listings: listings,
+		txns:     txns,
+		ledger:   ledgerSvc,
+		gateway:  gateway,
+		signer:   signer,
+		notifier: notifSvc,
+		cfg:      cfg,
+	}
+}
@@ -0,0 +1,36 @@
+package payments
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+)
+
+func (s *paymentService) notifyPayer(
+	ctx context.Context,
+	p *models.Payment,
+	ntype models.NotificationType,
+	title, body string,
+) {
+	data := map[string]interface{}{
+		"paymentID": p.ID.Hex(),
+		"listingID": p.ListingID.Hex(),
+		"amount":    p.Amount,
+	}
+	if err := s.notifier.CreateUserNotification(ctx, p.Payer.ID.Hex(), ntype, title, body, data); err != nil {
+		// log error, but don’t fail business logic
+		fmt.Printf("notifyPayer error: %v\n", err)
+	}
+}
+
+func (s *paymentService) notifyDealer(
+	ctx context.Context,
+	dealerID string,
+	ntype models.NotificationType,
+	title, body string,
+	data map[string]interface{},
+) {
+	if err := s.notifier.CreateDealerNotification(ctx, dealerID, ntype, title, body, data); err != nil {
+		fmt.Printf("notifyDealer error: %v\n", err)
+	}
+}
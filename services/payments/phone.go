@@ -0,0 +1,33 @@
+package payments
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidPhone = errors.New("phone number must be a Kenyan mobile number")
+
+// NormalizeMSISDN turns 07XX, 01XX, +2547XX and 2547XX style numbers into
+// the 254XXXXXXXXX form M-Pesa expects.
+func NormalizeMSISDN(phone string) (string, error) {
+	p := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+	p = strings.TrimPrefix(p, "+")
+
+	switch {
+	case strings.HasPrefix(p, "254"):
+	case strings.HasPrefix(p, "0"):
+		p = "254" + p[1:]
+	case len(p) == 9:
+		p = "254" + p
+	}
+
+	if len(p) != 12 || (p[3] != '7' && p[3] != '1') {
+		return "", ErrInvalidPhone
+	}
+	for _, r := range p {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidPhone
+		}
+	}
+	return p, nil
+}
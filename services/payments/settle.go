@@ -0,0 +1,255 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	listingRepo "carsawa/database/repository/listing"
+	paymentRepo "carsawa/database/repository/payment"
+	"carsawa/models"
+	"carsawa/services/transaction"
+	"carsawa/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	// Callbacks normally arrive within a minute of the push; only chase
+	// payments that have been quiet for longer.
+	reconcileAfter = 2 * time.Minute
+	reconcileBatch = 50
+)
+
+func (s *paymentService) HandleCallback(ctx context.Context, paymentID, signature string, body []byte) error {
+	if err := s.signer.Verify(paymentID, signature); err != nil {
+		return err
+	}
+	p, err := s.repo.GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+	result, err := s.gateway.ParseCallback(body)
+	if err != nil {
+		return err
+	}
+	if result.CheckoutRequestID != p.CheckoutRequestID {
+		return ErrCheckoutMismatch
+	}
+	if p.Status != models.PaymentStatusPending {
+		// Duplicate delivery; the first one already settled the payment.
+		return nil
+	}
+	return s.settle(ctx, p, result)
+}
+
+func (s *paymentService) ReconcilePending(ctx context.Context) error {
+	now := time.Now()
+	stale, err := s.repo.GetStalePending(ctx, now.Add(-reconcileAfter), reconcileBatch)
+	if err != nil {
+		return err
+	}
+
+	for i := range stale {
+		p := &stale[i]
+		expired := now.Sub(p.CreatedAt) > s.cfg.PendingExpiry
+
+		if p.CheckoutRequestID == "" {
+			// The push never reached the gateway.
+			if expired {
+				s.expire(ctx, p, "payment prompt was never sent")
+			}
+			continue
+		}
+
+		result, err := s.gateway.QueryStatus(ctx, p.CheckoutRequestID)
+		_ = s.repo.IncrementAttempts(ctx, p.ID.Hex())
+		if err != nil {
+			utils.GetLogger().Warn("ReconcilePending: status query failed",
+				zap.String("paymentID", p.ID.Hex()), zap.Error(err))
+			continue
+		}
+		if result.Status == models.PaymentStatusPending {
+			if expired {
+				s.expire(ctx, p, "payment prompt timed out")
+			}
+			continue
+		}
+		if err := s.settle(ctx, p, result); err != nil {
+			utils.GetLogger().Error("ReconcilePending: failed to settle payment",
+				zap.String("paymentID", p.ID.Hex()), zap.Error(err))
+		}
+	}
+	return s.retryUnapplied(ctx, now)
+}
+
+// retryUnapplied finishes deposits that cleared but were never applied to a
+// sale because a step after the payment was completed failed.
+func (s *paymentService) retryUnapplied(ctx context.Context, now time.Time) error {
+	unapplied, err := s.repo.GetUnapplied(ctx, now.Add(-reconcileAfter), reconcileBatch)
+	if err != nil {
+		return err
+	}
+	for i := range unapplied {
+		p := &unapplied[i]
+		s.recordFunds(ctx, p, &GatewayResult{Receipt: p.Receipt})
+		if err := s.applyDeposit(ctx, p, p.Receipt); err != nil {
+			utils.GetLogger().Error("ReconcilePending: failed to apply deposit",
+				zap.String("paymentID", p.ID.Hex()), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *paymentService) expire(ctx context.Context, p *models.Payment, reason string) {
+	_ = s.settle(ctx, p, &GatewayResult{
+		CheckoutRequestID: p.CheckoutRequestID,
+		Status:            models.PaymentStatusFailed,
+		ResultDesc:        reason,
+	})
+}
+
+// settle records the gateway's verdict and, for a cleared deposit, reserves
+// the car for the payer.
+func (s *paymentService) settle(ctx context.Context, p *models.Payment, result *GatewayResult) error {
+	if result.Status == models.PaymentStatusPending {
+		return nil
+	}
+
+	err := s.repo.Complete(ctx, p.ID.Hex(), paymentRepo.PaymentResult{
+		Status:     result.Status,
+		Receipt:    result.Receipt,
+		ResultCode: result.ResultCode,
+		ResultDesc: result.ResultDesc,
+	})
+	if errors.Is(err, paymentRepo.ErrAlreadyProcessed) {
+		// A callback and reconciliation raced; the other one applies it.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Status != models.PaymentStatusSucceeded {
+		s.notifyPayer(ctx, p, models.NotificationTypePaymentFailed, "Deposit Not Received",
+			fmt.Sprintf("Your M-Pesa deposit of KES %.0f did not go through: %s", p.Amount, result.ResultDesc))
+		return nil
+	}
+	s.recordFunds(ctx, p, result)
+	if result.Amount > 0 && result.Amount < p.Amount {
+		return s.refund(ctx, p, fmt.Sprintf("paid KES %.0f of a KES %.0f deposit", result.Amount, p.Amount))
+	}
+	return s.applyDeposit(ctx, p, result.Receipt)
+}
+
+// applyDeposit takes the car off the market, records the deposit against the
+// sale and holds it in escrow. Every step can be repeated, so a run that fails
+// part way leaves the payment succeeded but unapplied for ReconcilePending to
+// finish. A car taken by someone else first leaves the payment due for refund.
+func (s *paymentService) applyDeposit(ctx context.Context, p *models.Payment, receipt string) error {
+	listingID := p.ListingID.Hex()
+	if err := s.reserve(ctx, p); err != nil {
+		if errors.Is(err, ErrNotReservable) {
+			return s.refund(ctx, p, "the car is no longer available")
+		}
+		return err
+	}
+	lst, err := s.listings.GetListingByID(ctx, listingID)
+	if err != nil {
+		return err
+	}
+
+	note := "M-Pesa deposit"
+	if receipt != "" {
+		note += " " + receipt
+	}
+	tx, err := s.txns.RecordDeposit(ctx, lst, p.Payer, p.Amount, note)
+	if errors.Is(err, transaction.ErrBuyerConflict) || errors.Is(err, transaction.ErrInvalidTransition) {
+		// Another buyer is already on the sale; give the car back to the market.
+		if rerr := s.listings.TransitionStatus(ctx, listingID, models.ListingStatusReserved, models.ListingStatusActive); rerr != nil {
+			utils.GetLogger().Error("applyDeposit: failed to release listing",
+				zap.String("listingID", listingID), zap.Error(rerr))
+		}
+		return s.refund(ctx, p, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	// The payment is linked last: until then it is picked up again by the sweep.
+	if err := s.ledger.HoldInEscrow(ctx, p, tx); err != nil {
+		return fmt.Errorf("hold deposit in escrow: %w", err)
+	}
+	if err := s.repo.MarkApplied(ctx, p.ID.Hex(), tx.ID); err != nil {
+		return err
+	}
+
+	car := fmt.Sprintf("%d %s %s", lst.CarDetails.Year, lst.CarDetails.Make, lst.CarDetails.Model)
+	s.notifyPayer(ctx, p, models.NotificationTypePaymentReceived, "Deposit Received",
+		fmt.Sprintf("Your deposit of KES %.0f was received. The %s is reserved for you.", p.Amount, car))
+	s.notifyDealer(ctx, lst.DealerListing.DealerID.Hex(), models.NotificationTypeListingReserved, "Car Reserved",
+		fmt.Sprintf("A buyer paid a KES %.0f deposit on your %s. It has been taken off the market.", p.Amount, car),
+		map[string]interface{}{"listingID": listingID, "transactionID": tx.ID.Hex(), "paymentID": p.ID.Hex()})
+	return nil
+}
+
+// reserve moves the payment's car from active to reserved, returning
+// ErrNotReservable when it is no longer on the market. The payment is flagged
+// before the move, so a retry that finds the car already reserved knows the
+// reservation is its own.
+func (s *paymentService) reserve(ctx context.Context, p *models.Payment) error {
+	listingID := p.ListingID.Hex()
+	if err := listingRepo.CheckTransition(models.ListingTypeDealer, models.ListingStatusActive, models.ListingStatusReserved, models.ActorSystem); err != nil {
+		return err
+	}
+	held := p.ListingHeld
+	if !held {
+		if err := s.repo.MarkListingHeld(ctx, p.ID.Hex()); err != nil {
+			return err
+		}
+	}
+
+	err := s.listings.TransitionStatus(ctx, listingID, models.ListingStatusActive, models.ListingStatusReserved)
+	if errors.Is(err, listingRepo.ErrInvalidTransition) || errors.Is(err, listingRepo.ErrNotFound) {
+		if held {
+			lst, gerr := s.listings.GetListingByID(ctx, listingID)
+			if gerr != nil {
+				return gerr
+			}
+			if lst.Status == models.ListingStatusReserved {
+				return nil
+			}
+		}
+		return ErrNotReservable
+	}
+	return err
+}
+
+// recordFunds books what the gateway actually collected in the ledger.
+func (s *paymentService) recordFunds(ctx context.Context, p *models.Payment, result *GatewayResult) {
+	received := *p
+	received.Receipt = result.Receipt
+	if result.Amount > 0 {
+		received.Amount = result.Amount
+	}
+	if err := s.ledger.RecordFundsReceived(ctx, &received); err != nil {
+		utils.GetLogger().Error("settle: failed to record funds in ledger",
+			zap.String("paymentID", p.ID.Hex()), zap.Error(err))
+	}
+}
+
+func (s *paymentService) refund(ctx context.Context, p *models.Payment, reason string) error {
+	if err := s.repo.MarkRefundDue(ctx, p.ID.Hex(), reason); err != nil {
+		return err
+	}
+	s.notifyPayer(ctx, p, models.NotificationTypePaymentFailed, "Deposit Will Be Refunded",
+		fmt.Sprintf("We received your KES %.0f deposit but could not apply it (%s). It will be refunded.", p.Amount, reason))
+	return nil
+}
+
+// RunReconciler chases pending and unapplied payments every interval until
+// ctx is cancelled, on one replica at a time.
+func RunReconciler(ctx context.Context, svc PaymentService, rdb *redis.Client, interval time.Duration) {
+	utils.RunLockedJob(ctx, rdb, "payment-reconciler", interval, svc.ReconcilePending)
+}
@@ -0,0 +1,59 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// CallbackPath is the route the gateway posts payment results to; the
+// payment ID and its signature are embedded in each push's callback URL.
+const CallbackPath = "/api/payments/callback/"
+
+var ErrInvalidSignature = errors.New("invalid payment callback signature")
+
+// CallbackSigner signs the callback URL handed to the gateway so that only
+// the provider, which received the URL, can report on a payment.
+type CallbackSigner struct {
+	baseURL string
+	secret  []byte
+}
+
+func NewCallbackSigner(baseURL, secret string) (*CallbackSigner, error) {
+	if secret == "" {
+		return nil, errors.New("CallbackSigner: signing secret is required")
+	}
+	return &CallbackSigner{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  []byte(secret),
+	}, nil
+}
+
+// CallbackURL returns the signed URL results for paymentID are posted to.
+func (s *CallbackSigner) CallbackURL(paymentID string) string {
+	q := url.Values{}
+	q.Set("signature", s.sign(paymentID))
+	return s.baseURL + CallbackPath + url.PathEscape(paymentID) + "?" + q.Encode()
+}
+
+func (s *CallbackSigner) Verify(paymentID, signature string) error {
+	want, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(paymentID))
+	if !hmac.Equal(mac.Sum(nil), want) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s *CallbackSigner) sign(paymentID string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(paymentID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package savedsearch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"carsawa/models"
+	"carsawa/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func (s *savedSearchService) MatchListing(ctx context.Context, lst *models.Listing) {
+	makeKey := strings.ToLower(strings.TrimSpace(lst.CarDetails.Make))
+	var after primitive.ObjectID
+	for {
+		batch, err := s.repo.GetAlertCandidates(ctx, makeKey, after, candidateBatch)
+		if err != nil {
+			utils.GetLogger().Error("MatchListing: failed to load saved searches",
+				zap.String("listingID", lst.ID.Hex()), zap.Error(err))
+			return
+		}
+		for i := range batch {
+			ss := &batch[i]
+			// A user's own car is no news to them.
+			if ss.UserID == lst.UserListing.UserID || !matches(ss, lst) {
+				continue
+			}
+			s.alert(ctx, ss, lst)
+		}
+		if len(batch) < candidateBatch {
+			return
+		}
+		after = batch[len(batch)-1].ID
+	}
+}
+
+func (s *savedSearchService) alert(ctx context.Context, ss *models.SavedSearch, lst *models.Listing) {
+	if ss.Frequency == models.AlertDaily {
+		if err := s.repo.AddPending(ctx, ss.ID, lst.ID, maxPending); err != nil {
+			utils.GetLogger().Error("MatchListing: failed to queue match",
+				zap.String("savedSearchID", ss.ID.Hex()), zap.Error(err))
+		}
+		return
+	}
+
+	cd := lst.CarDetails
+	car := strings.TrimSpace(fmt.Sprintf("%d %s %s", cd.Year, cd.Make, cd.Model))
+	body := fmt.Sprintf("A %s matching \"%s\" has just been listed.", car, ss.Name)
+	if cd.Price > 0 {
+		body = fmt.Sprintf("A %s matching \"%s\" has just been listed at %.2f.", car, ss.Name, cd.Price)
+	}
+	s.notifyUser(ctx, ss.UserID, models.NotificationTypeSavedSearchMatch, "New Match For Your Search", body,
+		map[string]interface{}{"savedSearchID": ss.ID.Hex(), "listingID": lst.ID.Hex()})
+	if err := s.repo.MarkNotified(ctx, ss.ID, time.Now()); err != nil {
+		utils.GetLogger().Warn("MatchListing: failed to mark notified", zap.Error(err))
+	}
+}
+
+func (s *savedSearchService) SendDigests(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.repo.GetDueDigests(ctx, now.Add(-24*time.Hour), digestBatch)
+	if err != nil {
+		return err
+	}
+	for _, ss := range due {
+		ids := make([]string, len(ss.Pending))
+		for i, id := range ss.Pending {
+			ids[i] = id.Hex()
+		}
+		body := fmt.Sprintf("%d new cars match \"%s\".", len(ids), ss.Name)
+		if len(ids) == 1 {
+			body = fmt.Sprintf("1 new car matches \"%s\".", ss.Name)
+		}
+		s.notifyUser(ctx, ss.UserID, models.NotificationTypeSavedSearchDigest, "Your Daily Matches", body,
+			map[string]interface{}{"savedSearchID": ss.ID.Hex(), "listingIDs": ids})
+
+		if err := s.repo.CompleteDigest(ctx, ss.ID, ss.Pending, now); err != nil {
+			utils.GetLogger().Error("SendDigests: failed to clear digest",
+				zap.String("savedSearchID", ss.ID.Hex()), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *savedSearchService) notifyUser(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	ntype models.NotificationType,
+	title, body string,
+	data map[string]interface{},
+) {
+	if err := s.notifier.CreateUserNotification(ctx, userID.Hex(), ntype, title, body, data); err != nil {
+		fmt.Printf("notifyUser error: %v\n", err)
+	}
+}
+
+// RunDigestScheduler sends due digests every interval until ctx is
+// cancelled, on one replica at a time so no digest goes out twice.
+func RunDigestScheduler(ctx context.Context, svc SavedSearchService, rdb *redis.Client, interval time.Duration) {
+	utils.RunLockedJob(ctx, rdb, "saved-search-digest", interval, svc.SendDigests)
+}
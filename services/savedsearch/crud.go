@@ -0,0 +1,150 @@
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func invalid(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidSavedSearch, reason)
+}
+
+func parseUserID(userHex string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(userHex)
+	if err != nil {
+		return primitive.NilObjectID, errors.New("invalid user ID format")
+	}
+	return id, nil
+}
+
+// apply copies the fields req sets onto ss and checks the result.
+func apply(ss *models.SavedSearch, req models.SavedSearchRequest) error {
+	if req.Name != nil {
+		ss.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Query != nil {
+		ss.Query = strings.Join(strings.Fields(*req.Query), " ")
+	}
+	if req.Filter != nil {
+		ss.Filter = *req.Filter
+	}
+	if req.Frequency != nil {
+		ss.Frequency = *req.Frequency
+	}
+	ss.MakeKey = strings.ToLower(strings.TrimSpace(ss.Filter.Make))
+
+	if ss.Name == "" {
+		ss.Name = defaultName(ss)
+	}
+	if len(ss.Name) > maxNameLength {
+		return invalid(fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+	if !ss.Frequency.Valid() {
+		return invalid("frequency must be instant, daily or off")
+	}
+	if ss.Query == "" && ss.Filter == (models.ListingFilter{}) {
+		return invalid("set a query or at least one filter")
+	}
+	if near := ss.Filter.Near; near != nil {
+		if !near.Point().Valid() {
+			return invalid("near is not a valid location")
+		}
+		if near.RadiusKm <= 0 {
+			return invalid("near needs a radiusKm")
+		}
+	}
+	return nil
+}
+
+func defaultName(ss *models.SavedSearch) string {
+	if ss.Query != "" {
+		return ss.Query
+	}
+	if name := strings.TrimSpace(ss.Filter.Make + " " + ss.Filter.Model); name != "" {
+		return name
+	}
+	return "Saved search"
+}
+
+func (s *savedSearchService) CreateSavedSearch(ctx context.Context, userHex string, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	userID, err := parseUserID(userHex)
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.repo.CountUserSavedSearches(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= maxSavedSearches {
+		return nil, fmt.Errorf("%w: at most %d per user", ErrSavedSearchLimit, maxSavedSearches)
+	}
+
+	now := time.Now()
+	ss := &models.SavedSearch{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Frequency: models.AlertInstant,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := apply(ss, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateSavedSearch(ctx, ss); err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+func (s *savedSearchService) GetSavedSearches(ctx context.Context, userHex string) ([]models.SavedSearch, error) {
+	userID, err := parseUserID(userHex)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserSavedSearches(ctx, userID)
+}
+
+// UpdateSavedSearch changes the fields req sets. Turning alerts off drops
+// any matches waiting for a digest.
+func (s *savedSearchService) UpdateSavedSearch(ctx context.Context, userHex, id string, req models.SavedSearchRequest) (*models.SavedSearch, error) {
+	userID, err := parseUserID(userHex)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := s.repo.GetSavedSearch(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(ss, req); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{
+		"name":      ss.Name,
+		"query":     ss.Query,
+		"filter":    ss.Filter,
+		"makeKey":   ss.MakeKey,
+		"frequency": ss.Frequency,
+		"updatedAt": time.Now(),
+	}
+	if ss.Frequency != models.AlertDaily {
+		set["pending"] = []primitive.ObjectID{}
+	}
+	return s.repo.UpdateSavedSearch(ctx, id, userID, set)
+}
+
+func (s *savedSearchService) DeleteSavedSearch(ctx context.Context, userHex, id string) error {
+	userID, err := parseUserID(userHex)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteSavedSearch(ctx, id, userID)
+}
@@ -0,0 +1,52 @@
+package savedsearch
+
+import (
+	"context"
+	"errors"
+
+	savedSearchRepo "carsawa/database/repository/savedsearch"
+	"carsawa/models"
+	"carsawa/services/notification"
+)
+
+var (
+	ErrInvalidSavedSearch = errors.New("invalid saved search")
+	ErrSavedSearchLimit   = errors.New("saved search limit reached")
+)
+
+const (
+	maxSavedSearches = 20
+	maxNameLength    = 80
+	// maxPending caps the matches a daily digest carries.
+	maxPending     = 50
+	candidateBatch = 500
+	digestBatch    = 200
+)
+
+type SavedSearchService interface {
+	CreateSavedSearch(ctx context.Context, userID string, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	GetSavedSearches(ctx context.Context, userID string) ([]models.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, userID, id string, req models.SavedSearchRequest) (*models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userID, id string) error
+
+	// MatchListing alerts the owners of saved searches a newly live listing
+	// matches, straight away or in their next digest.
+	MatchListing(ctx context.Context, lst *models.Listing)
+	// SendDigests sends the daily digests that are due.
+	SendDigests(ctx context.Context) error
+}
+
+type savedSearchService struct {
+	repo     savedSearchRepo.SavedSearchRepository
+	notifier notification.NotificationService
+}
+
+func NewSavedSearchService(
+	repo savedSearchRepo.SavedSearchRepository,
+	notifSvc notification.NotificationService,
+) SavedSearchService {
+	return &savedSearchService{
+		repo:     repo,
+		notifier: notifSvc,
+	}
+}
@@ -0,0 +1,69 @@
+package savedsearch
+
+import (
+	"strings"
+
+	"carsawa/models"
+)
+
+// matches applies a saved search to one listing the way the search itself
+// filters listings. Every word of the query has to appear in the make or
+// model, so "land cruiser" does not alert on every Land Rover.
+func matches(ss *models.SavedSearch, lst *models.Listing) bool {
+	f, cd := ss.Filter, lst.CarDetails
+
+	if f.Type != "" && f.Type != lst.Type {
+		return false
+	}
+	if !sameText(f.Make, cd.Make) || !sameText(f.Model, cd.Model) ||
+		!sameText(string(f.FuelType), string(cd.FuelType)) ||
+		!sameText(string(f.Transmission), string(cd.Transmission)) ||
+		!sameText(string(f.BodyType), string(cd.BodyType)) ||
+		!sameText(string(f.DriveType), string(cd.DriveType)) ||
+		!sameText(string(f.ConditionGrade), string(cd.ConditionGrade)) ||
+		!sameText(f.Colour, cd.Colour) {
+		return false
+	}
+	if f.RegistrationPlate != "" &&
+		strings.ToUpper(strings.ReplaceAll(f.RegistrationPlate, " ", "")) != cd.RegistrationPlate {
+		return false
+	}
+	if f.MaxOwners > 0 && cd.NumberOfOwners > f.MaxOwners {
+		return false
+	}
+	if !inRange(float64(cd.Year), float64(f.MinYear), float64(f.MaxYear)) ||
+		!inRange(cd.Price, f.MinPrice, f.MaxPrice) ||
+		!inRange(float64(cd.Mileage), float64(f.MinMileage), float64(f.MaxMileage)) ||
+		!inRange(float64(cd.EngineSize), float64(f.MinEngineSize), float64(f.MaxEngineSize)) {
+		return false
+	}
+
+	if f.City != "" && (cd.Location == nil || !sameText(f.City, cd.Location.City)) {
+		return false
+	}
+	if f.Near != nil {
+		if cd.Location == nil || !cd.Location.GeoPoint.Valid() || f.Near.DistanceKm(cd.Location.GeoPoint) > f.Near.RadiusKm {
+			return false
+		}
+	}
+
+	if ss.Query != "" {
+		text := strings.ToLower(cd.Make + " " + cd.Model)
+		for _, word := range strings.Fields(strings.ToLower(ss.Query)) {
+			if !strings.Contains(text, word) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sameText reports whether an unset filter value or a case-insensitive
+// match allows v.
+func sameText(want, v string) bool {
+	return want == "" || strings.EqualFold(strings.TrimSpace(want), strings.TrimSpace(v))
+}
+
+func inRange(v, min, max float64) bool {
+	return (min <= 0 || v >= min) && (max <= 0 || v <= max)
+}
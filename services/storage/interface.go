@@ -12,6 +12,7 @@ type StorageService interface {
 	DeleteFile(ctx context.Context, publicID string) error
 	GetDownloadURL(ctx context.Context, resourceType, publicID string, expires time.Duration) (string, error)
 	GetSecureDownloadURL(ctx context.Context, resourceType, publicID string, expires time.Duration) (string, error)
+	GetThumbnailURL(ctx context.Context, publicID string, width, height int) (string, error)
 	UploadKYPFile(ctx context.Context, localFilePath, destFolder, adminKey string) (string, error)
 }
 
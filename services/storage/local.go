@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// LocalStorageService keeps files on the local disk and delivers them through
+// the server's /api/files routes. It is meant for development and tests.
+type LocalStorageService struct {
+	root   string
+	signer *URLSigner
+}
+
+func NewLocalStorageService(root string, signer *URLSigner) (StorageService, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("LocalStorageService: invalid root %q: %w", root, err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("LocalStorageService: failed to create root: %w", err)
+	}
+	return &LocalStorageService{root: abs, signer: signer}, nil
+}
+
+func (s *LocalStorageService) filePath(publicID string) (string, error) {
+	cleaned, err := CleanPublicID(publicID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+func (s *LocalStorageService) UploadFile(ctx context.Context, localFilePath, destFolder string) (string, error) {
+	publicID, err := objectKey(destFolder, newObjectName(localFilePath))
+	if err != nil {
+		return "", fmt.Errorf("LocalStorageService: %w", err)
+	}
+	dst, err := s.filePath(publicID)
+	if err != nil {
+		return "", fmt.Errorf("LocalStorageService: %w", err)
+	}
+
+	src, err := os.Open(localFilePath)
+	if err != nil {
+		return "", fmt.Errorf("LocalStorageService: failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", fmt.Errorf("LocalStorageService: failed to create folder: %w", err)
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("LocalStorageService: failed to create file: %w", err)
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return "", fmt.Errorf("LocalStorageService: failed to write file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return "", fmt.Errorf("LocalStorageService: failed to write file: %w", err)
+	}
+	return publicID, nil
+}
+
+func (s *LocalStorageService) DeleteFile(ctx context.Context, publicID string) error {
+	p, err := s.filePath(publicID)
+	if err != nil {
+		return fmt.Errorf("LocalStorageService: %w", err)
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("LocalStorageService: failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// GetDownloadURL returns the public delivery URL; private objects always get a signed URL.
+func (s *LocalStorageService) GetDownloadURL(ctx context.Context, resourceType, publicID string, expires time.Duration) (string, error) {
+	if _, err := CleanPublicID(publicID); err != nil {
+		return "", fmt.Errorf("LocalStorageService: %w", err)
+	}
+	if IsPrivate(publicID) {
+		return s.GetSecureDownloadURL(ctx, resourceType, publicID, expires)
+	}
+	return s.signer.PublicURL(publicID), nil
+}
+
+func (s *LocalStorageService) GetSecureDownloadURL(ctx context.Context, resourceType, publicID string, expires time.Duration) (string, error) {
+	if _, err := CleanPublicID(publicID); err != nil {
+		return "", fmt.Errorf("LocalStorageService: %w", err)
+	}
+	return s.signer.SignedURL(publicID, expires), nil
+}
+
+// GetThumbnailURL returns the original image; the local backend does not resize.
+func (s *LocalStorageService) GetThumbnailURL(ctx context.Context, publicID string, width, height int) (string, error) {
+	return s.GetDownloadURL(ctx, "image", publicID, 0)
+}
+
+// UploadKYPFile encrypts the file and stores it where only signed URLs can reach it.
+func (s *LocalStorageService) UploadKYPFile(ctx context.Context, localFilePath, destFolder, adminKey string) (string, error) {
+	encryptedFilePath, err := encryptFile(localFilePath, adminKey)
+	if err != nil {
+		return "", fmt.Errorf("LocalStorageService: failed to encrypt file: %w", err)
+	}
+	defer os.Remove(encryptedFilePath)
+
+	publicID, err := s.UploadFile(ctx, encryptedFilePath, path.Join(privatePrefix, destFolder))
+	if err != nil {
+		return "", fmt.Errorf("LocalStorageService: failed to upload encrypted KYP file: %w", err)
+	}
+	return publicID, nil
+}
+
+func (s *LocalStorageService) Open(ctx context.Context, publicID string) (io.ReadCloser, string, error) {
+	p, err := s.filePath(publicID)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, "", ErrObjectNotFound
+		}
+		return nil, "", fmt.Errorf("LocalStorageService: failed to open file: %w", err)
+	}
+	return f, contentTypeFor(publicID), nil
+}
+
+func (s *LocalStorageService) VerifyURL(publicID, expires, signature string) error {
+	return s.signer.Verify(publicID, expires, signature)
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// privatePrefix marks objects that are only reachable through a signed URL.
+const privatePrefix = "private/"
+
+var (
+	ErrInvalidPublicID = errors.New("invalid public ID")
+	ErrObjectNotFound  = errors.New("object not found")
+)
+
+// FileServer is implemented by backends whose files are delivered by this
+// server rather than by a CDN.
+type FileServer interface {
+	// Open streams an object's contents; callers must close the reader.
+	Open(ctx context.Context, publicID string) (io.ReadCloser, string, error)
+	// VerifyURL checks the expiry and signature carried by a secure URL.
+	VerifyURL(publicID, expires, signature string) error
+}
+
+// CleanPublicID checks that an object key is already in canonical form and
+// rejects anything else: leading or doubled slashes, "." and ".." segments
+// and backslashes. Keys are never rewritten, so the key checked by IsPrivate
+// is the key that gets served.
+func CleanPublicID(publicID string) (string, error) {
+	if publicID == "" || strings.Contains(publicID, "\\") {
+		return "", ErrInvalidPublicID
+	}
+	cleaned := path.Clean("/" + publicID)[1:]
+	if cleaned == "" || cleaned != publicID {
+		return "", ErrInvalidPublicID
+	}
+	return cleaned, nil
+}
+
+// IsPrivate reports whether an object may only be served through a signed URL.
+func IsPrivate(publicID string) bool {
+	return strings.HasPrefix(publicID, privatePrefix)
+}
+
+func escapePublicID(publicID string) string {
+	parts := strings.Split(publicID, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// objectKey joins a destination folder and file name into an object key.
+func objectKey(destFolder, name string) (string, error) {
+	return CleanPublicID(path.Join(strings.Trim(destFolder, "/"), name))
+}
+
+// contentTypeFor guesses a MIME type from the object key's extension.
+func contentTypeFor(publicID string) string {
+	switch strings.ToLower(path.Ext(publicID)) {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	case ".gif":
+		return "image/gif"
+	case ".pdf":
+		return "application/pdf"
+	case ".mp4":
+		return "video/mp4"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// newObjectName returns a collision-resistant file name that keeps the upload's extension.
+func newObjectName(localFilePath string) string {
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	return fmt.Sprintf("%d-%s%s", time.Now().UnixNano(), hex.EncodeToString(suffix), strings.ToLower(filepath.Ext(localFilePath)))
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanPublicID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		private bool
+		err     error
+	}{
+		{name: "public key", id: "listings/abc/photo.jpg", want: "listings/abc/photo.jpg"},
+		{name: "private key", id: "private/kyp/doc.pdf", want: "private/kyp/doc.pdf", private: true},
+		{name: "empty", id: "", err: ErrInvalidPublicID},
+		{name: "leading slash hides private prefix", id: "/private/kyp/doc.pdf", err: ErrInvalidPublicID},
+		{name: "doubled slash", id: "private//kyp/doc.pdf", err: ErrInvalidPublicID},
+		{name: "dot segment", id: "./private/kyp/doc.pdf", err: ErrInvalidPublicID},
+		{name: "parent segment", id: "listings/../private/kyp/doc.pdf", err: ErrInvalidPublicID},
+		{name: "escapes root", id: "../etc/passwd", err: ErrInvalidPublicID},
+		{name: "trailing slash", id: "listings/", err: ErrInvalidPublicID},
+		{name: "backslash", id: `private\kyp\doc.pdf`, err: ErrInvalidPublicID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CleanPublicID(tt.id)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CleanPublicID(%q) error = %v, want %v", tt.id, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CleanPublicID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+			if IsPrivate(got) != tt.private {
+				t.Errorf("IsPrivate(%q) = %v, want %v", got, !tt.private, tt.private)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config describes an S3-compatible bucket such as AWS S3 or MinIO.
+type S3Config struct {
+	Endpoint  string // host[:port], e.g. "localhost:9000" or "s3.eu-west-1.amazonaws.com"
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+	PathStyle bool // MinIO and most self-hosted deployments need path-style addressing
+}
+
+// S3StorageService stores objects in an S3-compatible bucket. Requests are
+// signed with AWS Signature V4; downloads are proxied through the server's
+// /api/files routes so the bucket itself can stay private.
+type S3StorageService struct {
+	cfg    S3Config
+	client *http.Client
+	signer *URLSigner
+}
+
+func NewS3StorageService(cfg S3Config, signer *URLSigner) (StorageService, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3StorageService: endpoint and bucket are required")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3StorageService: access key and secret key are required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	return &S3StorageService{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 60 * time.Second},
+		signer: signer,
+	}, nil
+}
+
+func (s *S3StorageService) UploadFile(ctx context.Context, localFilePath, destFolder string) (string, error) {
+	publicID, err := objectKey(destFolder, newObjectName(localFilePath))
+	if err != nil {
+		return "", fmt.Errorf("S3StorageService: %w", err)
+	}
+
+	f, err := os.Open(localFilePath)
+	if err != nil {
+		return "", fmt.Errorf("S3StorageService: failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("S3StorageService: failed to stat file: %w", err)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("S3StorageService: failed to hash file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("S3StorageService: failed to rewind file: %w", err)
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, publicID, f, hex.EncodeToString(h.Sum(nil)))
+	if err != nil {
+		return "", fmt.Errorf("S3StorageService: %w", err)
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", contentTypeFor(publicID))
+	s.sign(req, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("S3StorageService: failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("S3StorageService: failed to upload file: %s", s3Error(resp))
+	}
+	return publicID, nil
+}
+
+func (s *S3StorageService) DeleteFile(ctx context.Context, publicID string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, publicID, nil, s3EmptyPayloadHash)
+	if err != nil {
+		return fmt.Errorf("S3StorageService: %w", err)
+	}
+	s.sign(req, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("S3StorageService: failed to delete file: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("S3StorageService: failed to delete file: %s", s3Error(resp))
+	}
+}
+
+// GetDownloadURL returns the public delivery URL; private objects always get a signed URL.
+func (s *S3StorageService) GetDownloadURL(ctx context.Context, resourceType, publicID string, expires time.Duration) (string, error) {
+	if _, err := CleanPublicID(publicID); err != nil {
+		return "", fmt.Errorf("S3StorageService: %w", err)
+	}
+	if IsPrivate(publicID) {
+		return s.GetSecureDownloadURL(ctx, resourceType, publicID, expires)
+	}
+	return s.signer.PublicURL(publicID), nil
+}
+
+func (s *S3StorageService) GetSecureDownloadURL(ctx context.Context, resourceType, publicID string, expires time.Duration) (string, error) {
+	if _, err := CleanPublicID(publicID); err != nil {
+		return "", fmt.Errorf("S3StorageService: %w", err)
+	}
+	return s.signer.SignedURL(publicID, expires), nil
+}
+
+// GetThumbnailURL returns the original image; S3 has no on-the-fly resizing.
+func (s *S3StorageService) GetThumbnailURL(ctx context.Context, publicID string, width, height int) (string, error) {
+	return s.GetDownloadURL(ctx, "image", publicID, 0)
+}
+
+// UploadKYPFile encrypts the file and stores it where only signed URLs can reach it.
+func (s *S3StorageService) UploadKYPFile(ctx context.Context, localFilePath, destFolder, adminKey string) (string, error) {
+	encryptedFilePath, err := encryptFile(localFilePath, adminKey)
+	if err != nil {
+		return "", fmt.Errorf("S3StorageService: failed to encrypt file: %w", err)
+	}
+	defer os.Remove(encryptedFilePath)
+
+	publicID, err := s.UploadFile(ctx, encryptedFilePath, path.Join(privatePrefix, destFolder))
+	if err != nil {
+		return "", fmt.Errorf("S3StorageService: failed to upload encrypted KYP file: %w", err)
+	}
+	return publicID, nil
+}
+
+func (s *S3StorageService) Open(ctx context.Context, publicID string) (io.ReadCloser, string, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, publicID, nil, s3EmptyPayloadHash)
+	if err != nil {
+		return nil, "", err
+	}
+	s.sign(req, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("S3StorageService: failed to fetch file: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		contentType := resp.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = contentTypeFor(publicID)
+		}
+		return resp.Body, contentType, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, "", ErrObjectNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, "", fmt.Errorf("S3StorageService: failed to fetch file: %s", s3Error(resp))
+	}
+}
+
+func (s *S3StorageService) VerifyURL(publicID, expires, signature string) error {
+	return s.signer.Verify(publicID, expires, signature)
+}
+
+// newRequest builds an unsigned request for an object key.
+func (s *S3StorageService) newRequest(ctx context.Context, method, publicID string, body io.Reader, payloadHash string) (*http.Request, error) {
+	key, err := CleanPublicID(publicID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if s.cfg.UseSSL {
+		scheme = "https"
+	}
+	host := s.cfg.Bucket + "." + s.cfg.Endpoint
+	objectPath := "/" + awsEscapePath(key)
+	if s.cfg.PathStyle {
+		host = s.cfg.Endpoint
+		objectPath = "/" + awsEscapePath(s.cfg.Bucket) + objectPath
+	}
+
+	u, err := url.Parse(scheme + "://" + host + objectPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object URL: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	return req, nil
+}
+
+// sign adds an AWS Signature V4 Authorization header to req.
+func (s *S3StorageService) sign(req *http.Request, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if lk == "content-type" || strings.HasPrefix(lk, "x-amz-") {
+			headers[lk] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("x-amz-content-sha256"),
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsEscapePath URI-encodes each path segment the way SigV4 expects:
+// everything except unreserved characters is percent-encoded.
+func awsEscapePath(p string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0x0f])
+		}
+	}
+	return b.String()
+}
+
+// s3Error summarises a failed S3 response for error messages.
+func s3Error(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Sprintf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
@@ -66,6 +66,20 @@ func (s *StorageServiceImpl) GetDownloadURL(ctx context.Context, resourceType, p
 	return url, nil
 }
 
+// GetThumbnailURL returns a delivery URL for an image cropped to fill width x height.
+func (s *StorageServiceImpl) GetThumbnailURL(ctx context.Context, publicID string, width, height int) (string, error) {
+	img, err := s.cld.Image(publicID)
+	if err != nil {
+		return "", fmt.Errorf("StorageServiceImpl: failed to get asset: %w", err)
+	}
+	img.Transformation = fmt.Sprintf("c_fill,w_%d,h_%d,q_auto,f_auto", width, height)
+	url, err := img.String()
+	if err != nil {
+		return "", fmt.Errorf("StorageServiceImpl: failed to get URL string: %w", err)
+	}
+	return url, nil
+}
+
 func (s *StorageServiceImpl) GetSecureDownloadURL(ctx context.Context, resourceType, publicID string, expires time.Duration) (string, error) {
 	expiresAt := time.Now().Add(expires).Unix()
 	stringToSign := fmt.Sprintf("expires_at=%d&public_id=%s%s", expiresAt, publicID, s.apiSecret)
@@ -0,0 +1,196 @@
+package tradein
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"carsawa/models"
+	"carsawa/services/listing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxNotesLen = 1000
+
+// CreateTradeIn submits the user's current car against an active dealer listing
+// and notifies that listing's dealer of the new lead.
+func (s *tradeInService) CreateTradeIn(
+	ctx context.Context,
+	userHex string,
+	req CreateTradeInRequest,
+) (*models.TradeIn, error) {
+	// 1) Validate the user and the target listing
+	userID, err := primitive.ObjectIDFromHex(userHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID", ErrInvalidTradeIn)
+	}
+	target, err := s.listings.GetListingByID(ctx, req.TargetListingID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Type != models.ListingTypeDealer || target.Status != models.ListingStatusActive {
+		return nil, ErrListingNotActive
+	}
+
+	// 2) Validate the trade-in car
+	cd := req.CarDetails
+	listing.NormalizeCarDetails(&cd)
+	if err := validateTradeInCar(cd); err != nil {
+		return nil, err
+	}
+	if len(req.Notes) > maxNotesLen {
+		return nil, fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidTradeIn, maxNotesLen)
+	}
+
+	// 3) Persist & reload
+	newID, err := s.repo.CreateTradeIn(ctx, &models.TradeIn{
+		UserID:          userID,
+		TargetListingID: target.ID,
+		DealerID:        target.DealerListing.DealerID,
+		CarDetails:      cd,
+		Notes:           req.Notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.repo.GetTradeInByID(ctx, newID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch new trade-in: %w", err)
+	}
+
+	// 4) Tell the dealer about the lead
+	s.notifyDealer(ctx, created.DealerID, models.NotificationTypeTradeInCreated, "New Trade-In Lead",
+		fmt.Sprintf("A buyer wants to trade in a %d %s %s against your %s %s.",
+			cd.Year, cd.Make, cd.Model, target.CarDetails.Make, target.CarDetails.Model),
+		map[string]interface{}{"tradeInID": newID, "listingID": target.ID.Hex()})
+
+	return created, nil
+}
+
+func validateTradeInCar(cd models.CarDetails) error {
+	if cd.Make == "" {
+		return fmt.Errorf("%w: make is required", ErrInvalidTradeIn)
+	}
+	if cd.Model == "" {
+		return fmt.Errorf("%w: model is required", ErrInvalidTradeIn)
+	}
+	if cd.Year < 1886 || cd.Year > time.Now().Year()+1 {
+		return fmt.Errorf("%w: invalid manufacturing year", ErrInvalidTradeIn)
+	}
+	if cd.Price != 0 {
+		return fmt.Errorf("%w: trade-in cars are valued by the dealer and cannot carry a price", ErrInvalidTradeIn)
+	}
+	return listing.ValidateVehicleAttributes(cd)
+}
+
+// GetUserTradeIns lists the user's trade-ins, newest first.
+func (s *tradeInService) GetUserTradeIns(ctx context.Context, userHex string, pagination models.Pagination) ([]models.TradeIn, error) {
+	if _, err := primitive.ObjectIDFromHex(userHex); err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID", ErrInvalidTradeIn)
+	}
+	return s.repo.GetTradeIns(ctx, models.TradeInFilter{UserID: userHex}, pagination)
+}
+
+// DeleteTradeIn withdraws an open trade-in; accepted deals cannot be withdrawn.
+func (s *tradeInService) DeleteTradeIn(ctx context.Context, tradeInID, userHex string) error {
+	t, err := s.loadOwnTradeIn(ctx, tradeInID, userHex)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CancelTradeIn(ctx, tradeInID, t.UserID); err != nil {
+		return err
+	}
+
+	if len(t.Offers) > 0 || len(t.Contacts) > 0 {
+		s.notifyDealer(ctx, t.DealerID, models.NotificationTypeTradeInCancelled, "Trade-In Withdrawn",
+			fmt.Sprintf("The buyer withdrew their %d %s %s trade-in.", t.CarDetails.Year, t.CarDetails.Make, t.CarDetails.Model),
+			map[string]interface{}{"tradeInID": tradeInID})
+	}
+	return nil
+}
+
+// GetDealerLeads lists trade-ins made against the dealer's listings.
+func (s *tradeInService) GetDealerLeads(
+	ctx context.Context,
+	dealerHex string,
+	filter models.TradeInFilter,
+	pagination models.Pagination,
+) ([]models.TradeIn, error) {
+	if _, err := primitive.ObjectIDFromHex(dealerHex); err != nil {
+		return nil, fmt.Errorf("%w: invalid dealer ID", ErrInvalidTradeIn)
+	}
+	filter.DealerID = dealerHex
+	filter.UserID = ""
+	return s.repo.GetTradeIns(ctx, filter, pagination)
+}
+
+// GetPublicTradeIns lists open trade-ins without anything that identifies the user or the car.
+func (s *tradeInService) GetPublicTradeIns(
+	ctx context.Context,
+	filter models.TradeInFilter,
+	pagination models.Pagination,
+) ([]PublicTradeIn, error) {
+	filter.UserID = ""
+	filter.Status = string(models.TradeInStatusOpen)
+
+	tradeIns, err := s.repo.GetTradeIns(ctx, filter, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PublicTradeIn, 0, len(tradeIns))
+	for _, t := range tradeIns {
+		cd := t.CarDetails
+		cd.VIN = ""
+		cd.RegistrationPlate = ""
+		if cd.Location != nil {
+			cd.Location = &models.Location{City: cd.Location.City}
+		}
+
+		offers := 0
+		for _, o := range t.Offers {
+			if o.Status == models.TradeInOfferPending {
+				offers++
+			}
+		}
+		out = append(out, PublicTradeIn{
+			ID:              t.ID.Hex(),
+			TargetListingID: t.TargetListingID.Hex(),
+			CarDetails:      cd,
+			OfferCount:      offers,
+			CreatedAt:       t.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (s *tradeInService) loadOwnTradeIn(ctx context.Context, tradeInID, userHex string) (*models.TradeIn, error) {
+	userID, err := primitive.ObjectIDFromHex(userHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID", ErrInvalidTradeIn)
+	}
+	t, err := s.repo.GetTradeInByID(ctx, tradeInID)
+	if err != nil {
+		return nil, err
+	}
+	if t.UserID != userID {
+		return nil, ErrNotTradeInOwner
+	}
+	return t, nil
+}
+
+func (s *tradeInService) loadLead(ctx context.Context, tradeInID, dealerHex string) (*models.TradeIn, error) {
+	dealerID, err := primitive.ObjectIDFromHex(dealerHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid dealer ID", ErrInvalidTradeIn)
+	}
+	t, err := s.repo.GetTradeInByID(ctx, tradeInID)
+	if err != nil {
+		return nil, err
+	}
+	if t.DealerID != dealerID {
+		return nil, ErrNotTradeInDealer
+	}
+	return t, nil
+}
@@ -0,0 +1,84 @@
+package tradein
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	listingRepo "carsawa/database/repository/listing"
+	tradeInRepo "carsawa/database/repository/tradein"
+	"carsawa/models"
+	"carsawa/services/notification"
+	"carsawa/services/user"
+)
+
+var (
+	ErrNotTradeInOwner  = fmt.Errorf("%w: only the user who submitted the trade-in can do this", listingRepo.ErrUnauthorizedAction)
+	ErrNotTradeInDealer = fmt.Errorf("%w: only the dealer of the target listing can do this", listingRepo.ErrUnauthorizedAction)
+	ErrListingNotActive = errors.New("target listing is not available for trade-in")
+	ErrOfferNotFound    = errors.New("trade-in offer not found")
+	ErrInvalidTradeIn   = errors.New("invalid trade-in request")
+)
+
+type TradeInService interface {
+	// User side
+	CreateTradeIn(ctx context.Context, userID string, req CreateTradeInRequest) (*models.TradeIn, error)
+	GetUserTradeIns(ctx context.Context, userID string, pagination models.Pagination) ([]models.TradeIn, error)
+	GetTradeInOffers(ctx context.Context, tradeInID, userID string) ([]models.TradeInOffer, error)
+	AcceptOffer(ctx context.Context, tradeInID, offerID, userID string) (*models.TradeIn, error)
+	DeleteTradeIn(ctx context.Context, tradeInID, userID string) error
+
+	// Dealer side
+	GetDealerLeads(ctx context.Context, dealerID string, filter models.TradeInFilter, pagination models.Pagination) ([]models.TradeIn, error)
+	MakeOffer(ctx context.Context, tradeInID, dealerID string, amount float64, message string) (*models.TradeIn, error)
+	ContactUser(ctx context.Context, tradeInID, dealerID, channel, message string) (*LeadContact, error)
+
+	// Public
+	GetPublicTradeIns(ctx context.Context, filter models.TradeInFilter, pagination models.Pagination) ([]PublicTradeIn, error)
+}
+
+// CreateTradeInRequest is what a user submits to trade their car in against a dealer listing.
+type CreateTradeInRequest struct {
+	TargetListingID string            `json:"targetListingId" binding:"required"`
+	CarDetails      models.CarDetails `json:"carDetails" binding:"required"`
+	Notes           string            `json:"notes"`
+}
+
+// LeadContact is the user's contact information released to the target dealer.
+type LeadContact struct {
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phoneNumber"`
+	Channel     string `json:"channel"`
+}
+
+// PublicTradeIn is the anonymised view of an open trade-in.
+type PublicTradeIn struct {
+	ID              string            `json:"id"`
+	TargetListingID string            `json:"targetListingId"`
+	CarDetails      models.CarDetails `json:"carDetails"`
+	OfferCount      int               `json:"offerCount"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+type tradeInService struct {
+	repo     tradeInRepo.TradeInRepository
+	listings listingRepo.ListingRepository
+	user     user.UserService
+	notifier notification.NotificationService
+}
+
+func NewTradeInService(
+	repo tradeInRepo.TradeInRepository,
+	listings listingRepo.ListingRepository,
+	user user.UserService,
+	notifSvc notification.NotificationService,
+) TradeInService {
+	return &tradeInService{
+		repo:     repo,
+		listings: listings,
+		user:     user,
+		notifier: notifSvc,
+	}
+}
@@ -0,0 +1,185 @@
+package tradein
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tradeInRepo "carsawa/database/repository/tradein"
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxOfferMessageLen = 500
+
+var contactChannels = map[string]bool{"whatsapp": true, "call": true, "email": true}
+
+// MakeOffer records the dealer's valuation of a lead; a new valuation replaces
+// the dealer's earlier pending one.
+func (s *tradeInService) MakeOffer(
+	ctx context.Context,
+	tradeInID, dealerHex string,
+	amount float64,
+	message string,
+) (*models.TradeIn, error) {
+	t, err := s.loadLead(ctx, tradeInID, dealerHex)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status != models.TradeInStatusOpen {
+		return nil, tradeInRepo.ErrNotOpen
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: offer must be positive", ErrInvalidTradeIn)
+	}
+	if len(message) > maxOfferMessageLen {
+		return nil, fmt.Errorf("%w: message must be at most %d characters", ErrInvalidTradeIn, maxOfferMessageLen)
+	}
+
+	if err := s.repo.AddOffer(ctx, tradeInID, models.TradeInOffer{
+		DealerID: t.DealerID,
+		Amount:   amount,
+		Message:  message,
+	}); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetTradeInByID(ctx, tradeInID)
+	if err != nil {
+		return nil, fmt.Errorf("reload trade-in: %w", err)
+	}
+
+	s.notifyUser(ctx, t.UserID, models.NotificationTypeTradeInOffer, "Trade-In Offer Received",
+		fmt.Sprintf("A dealer valued your %d %s %s at %.2f.", t.CarDetails.Year, t.CarDetails.Make, t.CarDetails.Model, amount),
+		map[string]interface{}{"tradeInID": tradeInID, "amount": amount})
+
+	return updated, nil
+}
+
+// GetTradeInOffers returns the valuations the user can still see; superseded
+// offers are hidden.
+func (s *tradeInService) GetTradeInOffers(ctx context.Context, tradeInID, userHex string) ([]models.TradeInOffer, error) {
+	t, err := s.loadOwnTradeIn(ctx, tradeInID, userHex)
+	if err != nil {
+		return nil, err
+	}
+	offers := make([]models.TradeInOffer, 0, len(t.Offers))
+	for _, o := range t.Offers {
+		if o.Status != models.TradeInOfferSuperseded {
+			offers = append(offers, o)
+		}
+	}
+	return offers, nil
+}
+
+// AcceptOffer takes a dealer's valuation and applies it as credit toward the
+// target listing's current price. The credit is carried onto the sale once
+// the user is recorded as its buyer.
+func (s *tradeInService) AcceptOffer(ctx context.Context, tradeInID, offerHex, userHex string) (*models.TradeIn, error) {
+	// 1) Load & authorise
+	t, err := s.loadOwnTradeIn(ctx, tradeInID, userHex)
+	if err != nil {
+		return nil, err
+	}
+	offerID, err := primitive.ObjectIDFromHex(offerHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid offer ID", ErrInvalidTradeIn)
+	}
+	var offer *models.TradeInOffer
+	for i := range t.Offers {
+		if t.Offers[i].ID == offerID {
+			offer = &t.Offers[i]
+			break
+		}
+	}
+	if offer == nil {
+		return nil, ErrOfferNotFound
+	}
+	if t.Status != models.TradeInStatusOpen || offer.Status != models.TradeInOfferPending {
+		return nil, tradeInRepo.ErrOfferConflict
+	}
+
+	// 2) The credit only makes sense while the target listing is still for sale
+	target, err := s.listings.GetListingByID(ctx, t.TargetListingID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if target.Status != models.ListingStatusActive {
+		return nil, ErrListingNotActive
+	}
+
+	price := target.CarDetails.Price
+	deal := models.TradeInDeal{
+		ListingPrice: price,
+		Credit:       offer.Amount,
+		Balance:      math.Max(price-offer.Amount, 0),
+		AcceptedAt:   time.Now(),
+	}
+	if err := s.repo.AcceptOffer(ctx, tradeInID, offerHex, deal); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetTradeInByID(ctx, tradeInID)
+	if err != nil {
+		return nil, fmt.Errorf("reload trade-in: %w", err)
+	}
+
+	// 3) Tell the dealer the deal is on
+	s.notifyDealer(ctx, t.DealerID, models.NotificationTypeTradeInAccepted, "Trade-In Offer Accepted",
+		fmt.Sprintf("Your %.2f offer for the %d %s %s was accepted. Balance due on your %s %s: %.2f.",
+			offer.Amount, t.CarDetails.Year, t.CarDetails.Make, t.CarDetails.Model,
+			target.CarDetails.Make, target.CarDetails.Model, deal.Balance),
+		map[string]interface{}{
+			"tradeInID": tradeInID,
+			"listingID": target.ID.Hex(),
+			"credit":    deal.Credit,
+			"balance":   deal.Balance,
+		})
+
+	return updated, nil
+}
+
+// ContactUser logs the dealer reaching out and releases the user's contact details.
+func (s *tradeInService) ContactUser(ctx context.Context, tradeInID, dealerHex, channel, message string) (*LeadContact, error) {
+	t, err := s.loadLead(ctx, tradeInID, dealerHex)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status == models.TradeInStatusCancelled {
+		return nil, tradeInRepo.ErrNotOpen
+	}
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if !contactChannels[channel] {
+		return nil, fmt.Errorf("%w: invalid contact channel %q, expected whatsapp, call or email", ErrInvalidTradeIn, channel)
+	}
+	if len(message) > maxOfferMessageLen {
+		return nil, fmt.Errorf("%w: message must be at most %d characters", ErrInvalidTradeIn, maxOfferMessageLen)
+	}
+
+	u, err := s.user.GetUserByID(t.UserID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if err := s.repo.AddContact(ctx, tradeInID, models.TradeInContact{
+		DealerID: t.DealerID,
+		Channel:  channel,
+		Message:  message,
+	}); err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf("A dealer will reach out by %s about your %s %s trade-in.", channel, t.CarDetails.Make, t.CarDetails.Model)
+	if message != "" {
+		body += " " + message
+	}
+	s.notifyUser(ctx, t.UserID, models.NotificationTypeTradeInContact, "Dealer Contact", body,
+		map[string]interface{}{"tradeInID": tradeInID, "channel": channel})
+
+	return &LeadContact{
+		Username:    u.Username,
+		Email:       u.Email,
+		PhoneNumber: u.PhoneNumber,
+		Channel:     channel,
+	}, nil
+}
@@ -0,0 +1,295 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	listingRepo "carsawa/database/repository/listing"
+	tradeInRepo "carsawa/database/repository/tradein"
+	transactionRepo "carsawa/database/repository/transaction"
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OpenForAcceptedBid records the sale of a user's car to the dealer whose bid
+// was accepted. Opening twice for the same listing returns the open transaction.
+func (s *transactionService) OpenForAcceptedBid(
+	ctx context.Context,
+	lst *models.Listing,
+	bid models.Bid,
+) (*models.Transaction, error) {
+	bidID := bid.ID
+	tx := &models.Transaction{
+		ListingID:   lst.ID,
+		ListingType: lst.Type,
+		CarDetails:  lst.CarDetails,
+		Buyer:       &models.TransactionParty{ID: bid.DealerID, Type: models.PartyTypeDealer},
+		Seller:      models.TransactionParty{ID: lst.UserListing.UserID, Type: models.PartyTypeUser},
+		BidID:       &bidID,
+		Price:       bid.Offer,
+	}
+	return s.open(ctx, tx)
+}
+
+// OpenForDealerSale records a dealer listing marked sold; the buyer is filled
+// in later by RecordSale or a cleared deposit.
+func (s *transactionService) OpenForDealerSale(ctx context.Context, lst *models.Listing) (*models.Transaction, error) {
+	tx := &models.Transaction{
+		ListingID:   lst.ID,
+		ListingType: lst.Type,
+		CarDetails:  lst.CarDetails,
+		Seller:      models.TransactionParty{ID: lst.DealerListing.DealerID, Type: models.PartyTypeDealer},
+		Price:       lst.CarDetails.Price,
+	}
+	return s.open(ctx, tx)
+}
+
+func (s *transactionService) open(ctx context.Context, tx *models.Transaction) (*models.Transaction, error) {
+	tx.Status = models.TransactionStatusPending
+	tx.Fees = s.fees(tx.Price, tx.Seller.Type)
+	tradeIn, err := s.tradeInCredit(ctx, tx.Buyer, tx.ListingID, tx.Price)
+	if err != nil {
+		return nil, err
+	}
+	tx.TradeIn = tradeIn
+
+	newID, err := s.repo.CreateTransaction(ctx, tx)
+	if errors.Is(err, transactionRepo.ErrDuplicateTransaction) {
+		return s.repo.GetOpenTransactionForListing(ctx, tx.ListingID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.repo.GetTransactionByID(ctx, newID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch new transaction: %w", err)
+	}
+
+	s.notifyParties(ctx, created, models.NotificationTypeTransactionOpened, "Sale Recorded",
+		fmt.Sprintf("A sale of the %d %s %s for %.2f has been recorded.",
+			created.CarDetails.Year, created.CarDetails.Make, created.CarDetails.Model, created.Price))
+	return created, nil
+}
+
+// tradeInCredit credits the buyer's accepted trade-in on the listing against
+// price, so the sale records what the buyer still pays. Sales without a user
+// buyer or a trade-in get nil.
+func (s *transactionService) tradeInCredit(
+	ctx context.Context,
+	buyer *models.TransactionParty,
+	listingID primitive.ObjectID,
+	price float64,
+) (*models.TransactionTradeIn, error) {
+	if buyer == nil || buyer.Type != models.PartyTypeUser {
+		return nil, nil
+	}
+	t, err := s.tradeIns.GetAcceptedTradeIn(ctx, buyer.ID, listingID)
+	if errors.Is(err, tradeInRepo.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("look up trade-in: %w", err)
+	}
+	if t.Deal == nil {
+		return nil, nil
+	}
+	return &models.TransactionTradeIn{
+		TradeInID: t.ID,
+		Credit:    t.Deal.Credit,
+		Balance:   math.Max(price-t.Deal.Credit, 0),
+	}, nil
+}
+
+// fees charges the platform percentage to the seller.
+func (s *transactionService) fees(price float64, payer models.PartyType) []models.TransactionFee {
+	if s.feePct <= 0 || price <= 0 {
+		return nil
+	}
+	amount := math.Round(price*s.feePct) / 100
+	return []models.TransactionFee{{Name: "platform", Amount: amount, Payer: payer}}
+}
+
+// RecordPurchase lets a user tell a dealer they bought one of their cars and
+// asks the dealer to confirm it. It does not make the user the buyer: only
+// the dealer's RecordSale or a cleared deposit does, or anyone could take
+// the buyer's place on a sale and block the real buyer.
+func (s *transactionService) RecordPurchase(ctx context.Context, userHex, listingID string) error {
+	if _, err := primitive.ObjectIDFromHex(userHex); err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+	lst, err := s.listings.GetListingByID(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if lst.Type != models.ListingTypeDealer || !sellable(lst.Status) {
+		return ErrNotForSale
+	}
+
+	seller := models.TransactionParty{ID: lst.DealerListing.DealerID, Type: models.PartyTypeDealer}
+	s.notifyParty(ctx, seller, models.NotificationTypePurchaseClaimed, "Purchase To Confirm",
+		fmt.Sprintf("A buyer says they bought your %d %s %s. Record the sale with them as the buyer to confirm it.",
+			lst.CarDetails.Year, lst.CarDetails.Make, lst.CarDetails.Model),
+		map[string]interface{}{"listingID": listingID, "buyerUserId": userHex})
+	return nil
+}
+
+// RecordSale marks the dealer's listing sold and records who bought it.
+func (s *transactionService) RecordSale(ctx context.Context, dealerHex string, req RecordSaleRequest) (*models.Transaction, error) {
+	// 1) Validate & authorise
+	dealerID, err := primitive.ObjectIDFromHex(dealerHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dealer ID: %w", err)
+	}
+	var buyer *models.TransactionParty
+	if req.BuyerUserID != "" {
+		buyerID, err := primitive.ObjectIDFromHex(req.BuyerUserID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid buyer ID: %w", err)
+		}
+		buyer = &models.TransactionParty{ID: buyerID, Type: models.PartyTypeUser}
+	}
+	if req.Price < 0 {
+		return nil, errors.New("price cannot be negative")
+	}
+	lst, err := s.listings.GetListingByID(ctx, req.ListingID)
+	if err != nil {
+		return nil, err
+	}
+	if lst.Type != models.ListingTypeDealer || lst.DealerListing.DealerID != dealerID {
+		return nil, ErrSellerOnly
+	}
+	if !sellable(lst.Status) {
+		return nil, ErrNotForSale
+	}
+
+	// 2) Claim the open transaction for the buyer before the car goes off
+	// the market, so a conflicting buyer leaves the listing untouched
+	tx, err := s.repo.GetOpenTransactionForListing(ctx, lst.ID)
+	found := err == nil
+	if err != nil && !errors.Is(err, transactionRepo.ErrNotFound) {
+		return nil, err
+	}
+	if found && buyer != nil {
+		if tx, err = s.attachBuyer(ctx, tx, *buyer); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3) Take the listing off the market
+	if lst.Status != models.ListingStatusSold {
+		if err := listingRepo.CheckTransition(lst.Type, lst.Status, models.ListingStatusSold, models.ActorDealer); err != nil {
+			return nil, err
+		}
+		if err := s.listings.TransitionStatus(ctx, req.ListingID, lst.Status, models.ListingStatusSold); err != nil {
+			return nil, fmt.Errorf("failed to mark listing sold: %w", err)
+		}
+		s.notifyListingStatus(ctx, lst, models.ListingStatusSold)
+		s.announceSold(lst)
+	}
+	if found {
+		return tx, nil
+	}
+
+	// 4) Otherwise start a transaction at the agreed price
+	price := lst.CarDetails.Price
+	if req.Price > 0 {
+		price = req.Price
+	}
+	return s.open(ctx, &models.Transaction{
+		ListingID:   lst.ID,
+		ListingType: lst.Type,
+		CarDetails:  lst.CarDetails,
+		Buyer:       buyer,
+		Seller:      models.TransactionParty{ID: dealerID, Type: models.PartyTypeDealer},
+		Price:       price,
+	})
+}
+
+// sellable reports whether a dealer listing can still be recorded as sold.
+func sellable(status models.ListingStatus) bool {
+	return status == models.ListingStatusActive || status == models.ListingStatusReserved || status == models.ListingStatusSold
+}
+
+func (s *transactionService) attachBuyer(
+	ctx context.Context,
+	tx *models.Transaction,
+	buyer models.TransactionParty,
+) (*models.Transaction, error) {
+	if tx.Buyer != nil {
+		if *tx.Buyer == buyer {
+			return tx, nil
+		}
+		return nil, ErrBuyerConflict
+	}
+	tradeIn, err := s.tradeInCredit(ctx, &buyer, tx.ListingID, tx.Price)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetBuyer(ctx, tx.ID.Hex(), buyer, tradeIn); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetTransactionByID(ctx, tx.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("reload transaction: %w", err)
+	}
+
+	s.notifyParties(ctx, updated, models.NotificationTypeTransactionUpdated, "Buyer Confirmed",
+		fmt.Sprintf("The buyer of the %d %s %s has been recorded.",
+			updated.CarDetails.Year, updated.CarDetails.Make, updated.CarDetails.Model))
+	return updated, nil
+}
+
+// GetTransaction returns a transaction to either of its parties.
+func (s *transactionService) GetTransaction(
+	ctx context.Context,
+	id, callerHex string,
+	callerType models.PartyType,
+) (*models.Transaction, error) {
+	tx, _, err := s.loadAsParty(ctx, id, callerHex, callerType)
+	return tx, err
+}
+
+// GetPurchases lists the transactions where the party is the buyer.
+func (s *transactionService) GetPurchases(ctx context.Context, partyHex string, pagination models.Pagination) ([]models.Transaction, error) {
+	partyID, err := primitive.ObjectIDFromHex(partyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid party ID: %w", err)
+	}
+	return s.repo.GetTransactionsByParty(ctx, partyID, transactionRepo.RoleBuyer, pagination)
+}
+
+// GetSales lists the transactions where the party is the seller.
+func (s *transactionService) GetSales(ctx context.Context, partyHex string, pagination models.Pagination) ([]models.Transaction, error) {
+	partyID, err := primitive.ObjectIDFromHex(partyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid party ID: %w", err)
+	}
+	return s.repo.GetTransactionsByParty(ctx, partyID, transactionRepo.RoleSeller, pagination)
+}
+
+// loadAsParty loads a transaction and reports which side the caller is on.
+func (s *transactionService) loadAsParty(
+	ctx context.Context,
+	id, callerHex string,
+	callerType models.PartyType,
+) (*models.Transaction, transactionRepo.PartyRole, error) {
+	callerID, err := primitive.ObjectIDFromHex(callerHex)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid caller ID: %w", err)
+	}
+	tx, err := s.repo.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	caller := models.TransactionParty{ID: callerID, Type: callerType}
+	switch {
+	case tx.Seller == caller:
+		return tx, transactionRepo.RoleSeller, nil
+	case tx.Buyer != nil && *tx.Buyer == caller:
+		return tx, transactionRepo.RoleBuyer, nil
+	}
+	return nil, "", ErrNotParty
+}
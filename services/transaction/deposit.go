@@ -0,0 +1,72 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	transactionRepo "carsawa/database/repository/transaction"
+	"carsawa/models"
+)
+
+// RecordDeposit applies a cleared deposit to the listing's open transaction,
+// opening one for the buyer if the seller has not yet, and moves it to
+// deposit_paid. The change is recorded as a system transition. Recording the
+// same buyer's deposit again returns the sale unchanged, so a failed
+// settlement can be retried.
+func (s *transactionService) RecordDeposit(
+	ctx context.Context,
+	lst *models.Listing,
+	buyer models.TransactionParty,
+	amount float64,
+	note string,
+) (*models.Transaction, error) {
+	if amount <= 0 {
+		return nil, errors.New("deposit must be positive")
+	}
+
+	tx, err := s.repo.GetOpenTransactionForListing(ctx, lst.ID)
+	switch {
+	case errors.Is(err, transactionRepo.ErrNotFound):
+		seller := models.TransactionParty{ID: lst.DealerListing.DealerID, Type: models.PartyTypeDealer}
+		if lst.Type != models.ListingTypeDealer {
+			seller = models.TransactionParty{ID: lst.UserListing.UserID, Type: models.PartyTypeUser}
+		}
+		tx, err = s.open(ctx, &models.Transaction{
+			ListingID:   lst.ID,
+			ListingType: lst.Type,
+			CarDetails:  lst.CarDetails,
+			Buyer:       &buyer,
+			Seller:      seller,
+			Price:       lst.CarDetails.Price,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		if tx, err = s.attachBuyer(ctx, tx, buyer); err != nil {
+			return nil, err
+		}
+	}
+	if tx.Status == models.TransactionStatusDepositPaid {
+		return tx, nil
+	}
+	if tx.Status != models.TransactionStatusPending {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, tx.Status, models.TransactionStatusDepositPaid)
+	}
+
+	if err := s.repo.MarkDepositPaid(ctx, tx.ID.Hex(), amount, models.TransactionEvent{Note: note}); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetTransactionByID(ctx, tx.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("reload transaction: %w", err)
+	}
+
+	s.notifyParties(ctx, updated, models.NotificationTypeTransactionUpdated, "Deposit Paid",
+		fmt.Sprintf("A deposit of %.2f has been paid on the %d %s %s.",
+			amount, updated.CarDetails.Year, updated.CarDetails.Make, updated.CarDetails.Model))
+	return updated, nil
+}
@@ -0,0 +1,81 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	transactionRepo "carsawa/database/repository/transaction"
+	"carsawa/models"
+)
+
+// ConfirmHandover records that the caller has handed over or received the
+// car. When the second side confirms, escrow is released to the dealer and
+// the sale completes.
+func (s *transactionService) ConfirmHandover(
+	ctx context.Context,
+	id, callerHex string,
+	callerType models.PartyType,
+) (*models.Transaction, error) {
+	tx, role, err := s.loadAsParty(ctx, id, callerHex, callerType)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Buyer == nil {
+		return nil, ErrBuyerUnknown
+	}
+	updated, err := s.repo.ConfirmHandover(ctx, id, role)
+	if err != nil {
+		return nil, err
+	}
+	if updated.Status == models.TransactionStatusCompleted {
+		return updated, nil
+	}
+
+	h := updated.Handover
+	if h == nil || h.BuyerConfirmedAt == nil || h.SellerConfirmedAt == nil {
+		other := updated.Seller
+		if role == transactionRepo.RoleSeller {
+			other = *updated.Buyer
+		}
+		s.notifyParty(ctx, other, models.NotificationTypeHandoverConfirmed, "Handover Confirmed",
+			fmt.Sprintf("The other party has confirmed handover of the %d %s %s. Confirm on your side to complete the sale.",
+				updated.CarDetails.Year, updated.CarDetails.Make, updated.CarDetails.Model),
+			map[string]interface{}{"transactionID": updated.ID.Hex(), "listingID": updated.ListingID.Hex()})
+		return updated, nil
+	}
+
+	return s.completeHandover(ctx, updated)
+}
+
+// completeHandover releases the escrow before completing, so a failed posting
+// leaves the sale open for the next confirmation to retry.
+func (s *transactionService) completeHandover(ctx context.Context, tx *models.Transaction) (*models.Transaction, error) {
+	id := tx.ID.Hex()
+	if tx.Status == models.TransactionStatusDepositPaid && tx.Seller.Type == models.PartyTypeDealer {
+		if err := s.ledger.ReleaseEscrow(ctx, tx); err != nil {
+			return nil, fmt.Errorf("release escrow: %w", err)
+		}
+	}
+
+	err := s.repo.UpdateStatus(ctx, id, tx.Status, models.TransactionStatusCompleted, models.TransactionEvent{
+		Note: "Handover confirmed by both parties",
+	})
+	if errors.Is(err, transactionRepo.ErrStatusConflict) {
+		// Both sides confirmed at once; the other request completed it.
+		return s.repo.GetTransactionByID(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("reload transaction: %w", err)
+	}
+	s.settleReservation(ctx, updated)
+
+	s.notifyParties(ctx, updated, models.NotificationTypeTransactionUpdated, "Sale Completed",
+		fmt.Sprintf("Handover of the %d %s %s is confirmed and the sale is complete.",
+			updated.CarDetails.Year, updated.CarDetails.Make, updated.CarDetails.Model))
+	return updated, nil
+}
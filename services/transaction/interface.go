@@ -0,0 +1,81 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	listingRepo "carsawa/database/repository/listing"
+	tradeInRepo "carsawa/database/repository/tradein"
+	transactionRepo "carsawa/database/repository/transaction"
+	"carsawa/models"
+	"carsawa/services/ledger"
+	"carsawa/services/notification"
+	"carsawa/services/watchlist"
+)
+
+var (
+	ErrNotParty          = fmt.Errorf("%w: only a party to the transaction can do this", listingRepo.ErrUnauthorizedAction)
+	ErrSellerOnly        = fmt.Errorf("%w: only the seller can do this", listingRepo.ErrUnauthorizedAction)
+	ErrInvalidTransition = errors.New("transaction status change not allowed")
+	ErrNotForSale        = errors.New("listing is not available for purchase")
+	ErrBuyerConflict     = errors.New("transaction already has a different buyer")
+	ErrBuyerUnknown      = errors.New("the buyer must be recorded before handover")
+)
+
+type TransactionService interface {
+	// Opened by the listing service when a deal is struck
+	OpenForAcceptedBid(ctx context.Context, lst *models.Listing, bid models.Bid) (*models.Transaction, error)
+	OpenForDealerSale(ctx context.Context, lst *models.Listing) (*models.Transaction, error)
+
+	// Called by the payments service once a deposit has cleared
+	RecordDeposit(ctx context.Context, lst *models.Listing, buyer models.TransactionParty, amount float64, note string) (*models.Transaction, error)
+
+	// Recorded by the parties themselves; a purchase only asks the dealer to confirm
+	RecordPurchase(ctx context.Context, userID, listingID string) error
+	RecordSale(ctx context.Context, dealerID string, req RecordSaleRequest) (*models.Transaction, error)
+
+	GetTransaction(ctx context.Context, id, callerID string, callerType models.PartyType) (*models.Transaction, error)
+	GetPurchases(ctx context.Context, partyID string, pagination models.Pagination) ([]models.Transaction, error)
+	GetSales(ctx context.Context, partyID string, pagination models.Pagination) ([]models.Transaction, error)
+	UpdateStatus(ctx context.Context, id, callerID string, callerType models.PartyType, to models.TransactionStatus, note string) (*models.Transaction, error)
+	// ConfirmHandover completes the sale, releasing any escrow, once both parties have confirmed
+	ConfirmHandover(ctx context.Context, id, callerID string, callerType models.PartyType) (*models.Transaction, error)
+}
+
+// RecordSaleRequest is what a dealer submits when a car leaves the lot.
+type RecordSaleRequest struct {
+	ListingID   string  `json:"listingId" binding:"required"`
+	BuyerUserID string  `json:"buyerUserId"` // Optional; a cleared deposit also records the buyer
+	Price       float64 `json:"price"`       // Defaults to the listing price
+}
+
+type transactionService struct {
+	repo     transactionRepo.TransactionRepository
+	listings listingRepo.ListingRepository
+	tradeIns tradeInRepo.TradeInRepository
+	notifier notification.NotificationService
+	ledger   ledger.LedgerService
+	watchers watchlist.WatchlistService
+	feePct   float64
+}
+
+func NewTransactionService(
+	repo transactionRepo.TransactionRepository,
+	listings listingRepo.ListingRepository,
+	tradeIns tradeInRepo.TradeInRepository,
+	notifSvc notification.NotificationService,
+	ledgerSvc ledger.LedgerService,
+	watchers watchlist.WatchlistService,
+	feePercent float64,
+) TransactionService {
+	return &transactionService{
+		repo:     repo,
+		listings: listings,
+		tradeIns: tradeIns,
+		notifier: notifSvc,
+		ledger:   ledgerSvc,
+		watchers: watchers,
+		feePct:   feePercent,
+	}
+}
@@ -0,0 +1,72 @@
+package transaction
+
+import (
+	"carsawa/models"
+	"context"
+	"fmt"
+)
+
+// notifyParty routes a notification to a user or dealer inbox.
+func (s *transactionService) notifyParty(
+	ctx context.Context,
+	party models.TransactionParty,
+	ntype models.NotificationType,
+	title, body string,
+	data map[string]interface{},
+) {
+	var err error
+	if party.Type == models.PartyTypeDealer {
+		err = s.notifier.CreateDealerNotification(ctx, party.ID.Hex(), ntype, title, body, data)
+	} else {
+		err = s.notifier.CreateUserNotification(ctx, party.ID.Hex(), ntype, title, body, data)
+	}
+	if err != nil {
+		// log error, but don’t fail business logic
+		fmt.Printf("notifyParty error: %v\n", err)
+	}
+}
+
+// notifyParties tells the seller and, once known, the buyer.
+func (s *transactionService) notifyParties(
+	ctx context.Context,
+	tx *models.Transaction,
+	ntype models.NotificationType,
+	title, body string,
+) {
+	data := map[string]interface{}{
+		"transactionID": tx.ID.Hex(),
+		"listingID":     tx.ListingID.Hex(),
+		"status":        tx.Status,
+	}
+	s.notifyParty(ctx, tx.Seller, ntype, title, body, data)
+	if tx.Buyer != nil {
+		s.notifyParty(ctx, *tx.Buyer, ntype, title, body, data)
+	}
+}
+
+// announceSold tells the users watching a listing that it has been sold.
+// Watchers are fanned out in the background.
+func (s *transactionService) announceSold(lst *models.Listing) {
+	if s.watchers == nil {
+		return
+	}
+	sold := *lst
+	sold.Status = models.ListingStatusSold
+	go s.watchers.NotifyOffMarket(context.Background(), &sold)
+}
+
+// notifyListingStatus tells a dealer their car has been sold or is back on
+// the market after a reservation fell through.
+func (s *transactionService) notifyListingStatus(ctx context.Context, lst *models.Listing, to models.ListingStatus) {
+	car := fmt.Sprintf("%d %s %s", lst.CarDetails.Year, lst.CarDetails.Make, lst.CarDetails.Model)
+	seller := models.TransactionParty{ID: lst.DealerListing.DealerID, Type: models.PartyTypeDealer}
+	data := map[string]interface{}{"listingID": lst.ID.Hex(), "status": to}
+	switch to {
+	case models.ListingStatusSold:
+		s.notifyParty(ctx, seller, models.NotificationTypeListingSold, "Listing Sold",
+			fmt.Sprintf("Your %s has been marked sold.", car), data)
+	case models.ListingStatusActive:
+		s.notifyParty(ctx, seller, models.NotificationTypeListingReopened, "Listing Back on the Market",
+			fmt.Sprintf("The reservation on your %s has ended and it is listed again.", car), data)
+	}
+}
@@ -0,0 +1,118 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	listingRepo "carsawa/database/repository/listing"
+	transactionRepo "carsawa/database/repository/transaction"
+	"carsawa/models"
+)
+
+const maxNoteLen = 500
+
+// transitions lists the status changes the parties may make; the value says
+// whether only the seller may make that change. A sale reaches deposit_paid
+// only through RecordDeposit once a payment clears, and with a deposit in
+// escrow completes through ConfirmHandover rather than here.
+var transitions = map[models.TransactionStatus]map[models.TransactionStatus]bool{
+	models.TransactionStatusPending: {
+		models.TransactionStatusCompleted: true,
+		models.TransactionStatusCancelled: false,
+	},
+	models.TransactionStatusDepositPaid: {
+		models.TransactionStatusRefunded: true,
+	},
+	models.TransactionStatusCompleted: {
+		models.TransactionStatusRefunded: true,
+	},
+}
+
+// UpdateStatus moves a transaction along its lifecycle on behalf of one of
+// its parties and tells both sides.
+func (s *transactionService) UpdateStatus(
+	ctx context.Context,
+	id, callerHex string,
+	callerType models.PartyType,
+	to models.TransactionStatus,
+	note string,
+) (*models.Transaction, error) {
+	tx, role, err := s.loadAsParty(ctx, id, callerHex, callerType)
+	if err != nil {
+		return nil, err
+	}
+	sellerOnly, ok := transitions[tx.Status][to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, tx.Status, to)
+	}
+	if sellerOnly && role != transactionRepo.RoleSeller {
+		return nil, ErrSellerOnly
+	}
+	if len(note) > maxNoteLen {
+		return nil, fmt.Errorf("note must be at most %d characters", maxNoteLen)
+	}
+
+	// Return the held deposit before the sale is marked refunded, so a failed
+	// posting leaves the transaction where it was and can be retried.
+	if tx.Status == models.TransactionStatusDepositPaid && to == models.TransactionStatusRefunded {
+		if err := s.ledger.RefundEscrow(ctx, tx); err != nil {
+			return nil, fmt.Errorf("refund escrow: %w", err)
+		}
+	}
+
+	actor := tx.Seller
+	if role == transactionRepo.RoleBuyer {
+		actor = *tx.Buyer
+	}
+	if err := s.repo.UpdateStatus(ctx, id, tx.Status, to, models.TransactionEvent{
+		Actor: &actor,
+		Note:  note,
+	}); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("reload transaction: %w", err)
+	}
+	s.settleReservation(ctx, updated)
+
+	s.notifyParties(ctx, updated, models.NotificationTypeTransactionUpdated, "Sale Updated",
+		fmt.Sprintf("The sale of the %d %s %s is now %s.",
+			updated.CarDetails.Year, updated.CarDetails.Make, updated.CarDetails.Model, updated.Status))
+	return updated, nil
+}
+
+// settleReservation moves a car held by a deposit on once its sale is decided:
+// sold when completed, back on the market when cancelled or refunded.
+func (s *transactionService) settleReservation(ctx context.Context, tx *models.Transaction) {
+	next := models.ListingStatusActive
+	switch tx.Status {
+	case models.TransactionStatusCompleted:
+		next = models.ListingStatusSold
+	case models.TransactionStatusCancelled, models.TransactionStatusRefunded:
+	default:
+		return
+	}
+	// The move follows from the sale whichever party settled it, so it is
+	// made as the system. Listings that were never reserved fail the
+	// transition and are left alone.
+	if err := listingRepo.CheckTransition(tx.ListingType, models.ListingStatusReserved, next, models.ActorSystem); err != nil {
+		return
+	}
+	err := s.listings.TransitionStatus(ctx, tx.ListingID.Hex(), models.ListingStatusReserved, next)
+	if err != nil {
+		if !errors.Is(err, listingRepo.ErrInvalidTransition) {
+			fmt.Printf("settleReservation error: %v\n", err)
+		}
+		return
+	}
+	lst, err := s.listings.GetListingByID(ctx, tx.ListingID.Hex())
+	if err != nil {
+		return
+	}
+	s.notifyListingStatus(ctx, lst, next)
+	if next == models.ListingStatusSold {
+		s.announceSold(lst)
+	}
+}
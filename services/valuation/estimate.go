@@ -0,0 +1,230 @@
+package valuation
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"carsawa/models"
+)
+
+const (
+	basisModel = "model"
+	basisMake  = "make"
+
+	// Comparables are drawn from this many model years either side, or a
+	// narrower span when falling back to the whole make.
+	modelYearSpan       = 4
+	makeYearSpan        = 2
+	minModelComparables = 5
+	minComparables      = 3
+
+	// Depreciation is fitted per model when the data spans enough years;
+	// otherwise defaultDepreciation is used. Either way it stays in bounds.
+	defaultDepreciation = 0.12
+	minDepreciation     = 0.04
+	maxDepreciation     = 0.30
+	minFitPoints        = 8
+	minFitYears         = 3
+
+	// Mileage moves value by mileageRate per 10,000 km away from the
+	// comparable, up to maxMileageAdjust either way. Missing mileage is
+	// assumed to be typicalKmPerYear for the car's age.
+	typicalKmPerYear = 15000
+	mileageRate      = 0.015
+	maxMileageAdjust = 0.25
+
+	// recencyDays is how quickly older prices lose weight.
+	recencyDays = 365.0
+	// minSpread keeps the range at least this fraction either side.
+	minSpread = 0.05
+)
+
+var sourceWeight = map[models.PriceSource]float64{
+	models.PriceSourceSale:        1.0,
+	models.PriceSourceAcceptedBid: 0.9,
+	models.PriceSourceClosed:      0.5, // An asking price that did not sell
+}
+
+type weighted struct {
+	price  float64
+	weight float64
+}
+
+// estimate values the car in req from its comparables.
+func estimate(req models.ValuationRequest, points []models.PricePoint, basis string, now time.Time) *models.Valuation {
+	rate := defaultDepreciation
+	if basis == basisModel {
+		rate = fitDepreciation(points, now)
+	}
+	mileage := req.Mileage
+	if mileage <= 0 {
+		mileage = expectedMileage(req.Year, now)
+	}
+
+	adjusted := make([]weighted, 0, len(points))
+	for _, p := range points {
+		// Carry the price to the target's age and mileage.
+		price := p.Price * math.Pow(1-rate, float64(p.Year-req.Year))
+		compKm := p.Mileage
+		if compKm <= 0 {
+			compKm = expectedMileage(p.Year, now)
+		}
+		adj := 1 - mileageRate*float64(mileage-compKm)/10000
+		adj = clamp(adj, 1-maxMileageAdjust, 1+maxMileageAdjust)
+
+		w := sourceWeight[p.Source]
+		w *= math.Exp(-now.Sub(p.At).Hours() / 24 / recencyDays)
+		w /= 1 + math.Abs(float64(p.Year-req.Year))
+		w /= 1 + math.Abs(float64(mileage-compKm))/50000
+		if w > 0 {
+			adjusted = append(adjusted, weighted{price: price * adj, weight: w})
+		}
+	}
+
+	est := quantile(adjusted, 0.5)
+	low, high := quantile(adjusted, 0.2), quantile(adjusted, 0.8)
+	if low > est*(1-minSpread) {
+		low = est * (1 - minSpread)
+	}
+	if high < est*(1+minSpread) {
+		high = est * (1 + minSpread)
+	}
+
+	confidence := confidenceScore(adjusted, (high-low)/est, basis)
+	return &models.Valuation{
+		Make:             req.Make,
+		Model:            req.Model,
+		Year:             req.Year,
+		Mileage:          mileage,
+		Estimate:         roundPrice(est),
+		Low:              roundPrice(low),
+		High:             roundPrice(high),
+		Confidence:       math.Round(confidence*100) / 100,
+		ConfidenceLevel:  confidenceLevel(confidence),
+		Comparables:      len(adjusted),
+		Basis:            basis,
+		DepreciationRate: math.Round(rate*1000) / 1000,
+		ComputedAt:       now,
+	}
+}
+
+// fitDepreciation fits ln(price) against model year by weighted least
+// squares and turns the slope into a yearly rate.
+func fitDepreciation(points []models.PricePoint, now time.Time) float64 {
+	years := make(map[int]bool)
+	for _, p := range points {
+		years[p.Year] = true
+	}
+	if len(points) < minFitPoints || len(years) < minFitYears {
+		return defaultDepreciation
+	}
+
+	var sw, sx, sy, sxx, sxy float64
+	for _, p := range points {
+		w := sourceWeight[p.Source] * math.Exp(-now.Sub(p.At).Hours()/24/recencyDays)
+		x, y := float64(p.Year), math.Log(p.Price)
+		sw += w
+		sx += w * x
+		sy += w * y
+		sxx += w * x * x
+		sxy += w * x * y
+	}
+	denom := sw*sxx - sx*sx
+	if sw == 0 || denom == 0 {
+		return defaultDepreciation
+	}
+	slope := (sw*sxy - sx*sy) / denom // Log value gained per newer model year
+	return clamp(1-math.Exp(-slope), minDepreciation, maxDepreciation)
+}
+
+// quantile returns the weighted q-quantile of the prices.
+func quantile(values []weighted, q float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]weighted, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].price < sorted[j].price })
+
+	var total float64
+	for _, v := range sorted {
+		total += v.weight
+	}
+	target, acc := q*total, 0.0
+	for _, v := range sorted {
+		acc += v.weight
+		if acc >= target {
+			return v.price
+		}
+	}
+	return sorted[len(sorted)-1].price
+}
+
+// confidenceScore grows with the effective number of comparables and
+// shrinks as their prices spread out or when only the make matched.
+func confidenceScore(values []weighted, spread float64, basis string) float64 {
+	var sw, sww float64
+	for _, v := range values {
+		sw += v.weight
+		sww += v.weight * v.weight
+	}
+	if sww == 0 {
+		return 0
+	}
+	effective := sw * sw / sww
+	score := effective / (effective + 4)
+	score /= 1 + 2*spread
+	if basis == basisMake {
+		score *= 0.6
+	}
+	return clamp(score, 0, 1)
+}
+
+func confidenceLevel(score float64) models.ConfidenceLevel {
+	switch {
+	case score >= 0.6:
+		return models.ConfidenceHigh
+	case score >= 0.35:
+		return models.ConfidenceMedium
+	default:
+		return models.ConfidenceLow
+	}
+}
+
+// rating places an asking price against the estimate.
+func rating(price, estimate float64) (models.PriceRating, float64) {
+	delta := (price - estimate) / estimate
+	switch {
+	case delta <= -0.10:
+		return models.PriceRatingGreat, delta
+	case delta <= -0.03:
+		return models.PriceRatingGood, delta
+	case delta <= 0.07:
+		return models.PriceRatingFair, delta
+	default:
+		return models.PriceRatingHigh, delta
+	}
+}
+
+func expectedMileage(year int, now time.Time) int {
+	age := now.Year() - year
+	if age < 1 {
+		age = 1
+	}
+	return age * typicalKmPerYear
+}
+
+func roundPrice(p float64) float64 {
+	return math.Round(p/100) * 100
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
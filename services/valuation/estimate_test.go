@@ -0,0 +1,189 @@
+package valuation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"carsawa/models"
+)
+
+// sales returns n sales of the same car at one price.
+func sales(carMake, model string, year, n int, price float64, at time.Time) []models.PricePoint {
+	points := make([]models.PricePoint, n)
+	for i := range points {
+		points[i] = models.PricePoint{
+			Make: carMake, Model: model, Year: year, Mileage: 90000,
+			Price: price, Source: models.PriceSourceSale, At: at,
+		}
+	}
+	return points
+}
+
+func TestEstimate(t *testing.T) {
+	now := time.Now()
+	axios := sales("Toyota", "Axio", 2015, 6, 1000000, now)
+
+	tests := []struct {
+		name       string
+		req        models.ValuationRequest
+		points     []models.PricePoint
+		basis      string
+		estimate   float64
+		low, high  float64
+		confidence models.ConfidenceLevel
+	}{
+		{
+			name:       "same age and mileage",
+			req:        models.ValuationRequest{Make: "Toyota", Model: "Axio", Year: 2015, Mileage: 90000},
+			points:     axios,
+			basis:      basisModel,
+			estimate:   1000000,
+			low:        950000,
+			high:       1050000,
+			confidence: models.ConfidenceMedium,
+		},
+		{
+			name:       "higher mileage",
+			req:        models.ValuationRequest{Make: "Toyota", Model: "Axio", Year: 2015, Mileage: 110000},
+			points:     axios,
+			basis:      basisModel,
+			estimate:   970000,
+			low:        921500,
+			high:       1018500,
+			confidence: models.ConfidenceMedium,
+		},
+		{
+			name:       "one year older",
+			req:        models.ValuationRequest{Make: "Toyota", Model: "Axio", Year: 2014, Mileage: 90000},
+			points:     axios,
+			basis:      basisModel,
+			estimate:   880000,
+			low:        836000,
+			high:       924000,
+			confidence: models.ConfidenceMedium,
+		},
+		{
+			name:       "make basis lowers confidence",
+			req:        models.ValuationRequest{Make: "Toyota", Model: "Fielder", Year: 2015, Mileage: 90000},
+			points:     axios,
+			basis:      basisMake,
+			estimate:   1000000,
+			low:        950000,
+			high:       1050000,
+			confidence: models.ConfidenceLow,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := estimate(tt.req, tt.points, tt.basis, now)
+			if v.Estimate != tt.estimate || v.Low != tt.low || v.High != tt.high {
+				t.Errorf("estimate() = %.0f [%.0f, %.0f], want %.0f [%.0f, %.0f]", v.Estimate, v.Low, v.High, tt.estimate, tt.low, tt.high)
+			}
+			if v.ConfidenceLevel != tt.confidence {
+				t.Errorf("estimate() confidence = %s (%.2f), want %s", v.ConfidenceLevel, v.Confidence, tt.confidence)
+			}
+			if v.Basis != tt.basis || v.Comparables != len(tt.points) {
+				t.Errorf("estimate() basis = %q from %d, want %q from %d", v.Basis, v.Comparables, tt.basis, len(tt.points))
+			}
+		})
+	}
+}
+
+func TestFitDepreciation(t *testing.T) {
+	now := time.Now()
+	// yearly loses rate of the value per model year from 2012 to 2019.
+	yearly := func(rate float64, years int) []models.PricePoint {
+		var points []models.PricePoint
+		for y := 2019; y > 2019-years; y-- {
+			points = append(points, models.PricePoint{
+				Year: y, Price: 2000000 * math.Pow(1-rate, float64(2019-y)), Source: models.PriceSourceSale, At: now,
+			})
+		}
+		return points
+	}
+
+	tests := []struct {
+		name   string
+		points []models.PricePoint
+		want   float64
+	}{
+		{name: "fitted", points: yearly(0.10, 8), want: 0.10},
+		{name: "too few points", points: yearly(0.10, 7), want: defaultDepreciation},
+		{name: "too few years", points: sales("Toyota", "Axio", 2015, 10, 1000000, now), want: defaultDepreciation},
+		{name: "steep loss is capped", points: yearly(0.50, 8), want: maxDepreciation},
+		{name: "appreciation is floored", points: yearly(-0.05, 8), want: minDepreciation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fitDepreciation(tt.points, now); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("fitDepreciation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfidenceLevel(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  models.ConfidenceLevel
+	}{
+		{score: 1, want: models.ConfidenceHigh},
+		{score: 0.6, want: models.ConfidenceHigh},
+		{score: 0.59, want: models.ConfidenceMedium},
+		{score: 0.35, want: models.ConfidenceMedium},
+		{score: 0.34, want: models.ConfidenceLow},
+		{score: 0, want: models.ConfidenceLow},
+	}
+	for _, tt := range tests {
+		if got := confidenceLevel(tt.score); got != tt.want {
+			t.Errorf("confidenceLevel(%v) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceScore(t *testing.T) {
+	even := []weighted{{price: 1, weight: 1}, {price: 1, weight: 1}, {price: 1, weight: 1}, {price: 1, weight: 1}}
+	skewed := []weighted{{price: 1, weight: 10}, {price: 1, weight: 0.1}, {price: 1, weight: 0.1}, {price: 1, weight: 0.1}}
+
+	tests := []struct {
+		name   string
+		values []weighted
+		spread float64
+		basis  string
+		want   float64
+	}{
+		{name: "four even comparables", values: even, basis: basisModel, want: 0.5},
+		{name: "spread prices", values: even, spread: 0.5, basis: basisModel, want: 0.25},
+		{name: "make basis", values: even, basis: basisMake, want: 0.3},
+		{name: "one comparable dominates", values: skewed, basis: basisModel, want: 0.2},
+		{name: "no weight", basis: basisModel, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := confidenceScore(tt.values, tt.spread, tt.basis); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("confidenceScore() = %.3f, want %.3f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRating(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  models.PriceRating
+	}{
+		{price: 800000, want: models.PriceRatingGreat},
+		{price: 900000, want: models.PriceRatingGreat},
+		{price: 910000, want: models.PriceRatingGood},
+		{price: 970000, want: models.PriceRatingGood},
+		{price: 980000, want: models.PriceRatingFair},
+		{price: 1070000, want: models.PriceRatingFair},
+		{price: 1080000, want: models.PriceRatingHigh},
+	}
+	for _, tt := range tests {
+		if got, _ := rating(tt.price, 1000000); got != tt.want {
+			t.Errorf("rating(%.0f, 1000000) = %s, want %s", tt.price, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package valuation
+
+import (
+	"strings"
+
+	"carsawa/models"
+)
+
+// index groups price points by model and by make. It is never modified once
+// built, so readers need no lock beyond fetching it.
+type index struct {
+	byModel map[string][]models.PricePoint
+	byMake  map[string][]models.PricePoint
+}
+
+func newIndex(points []models.PricePoint) *index {
+	idx := &index{
+		byModel: make(map[string][]models.PricePoint),
+		byMake:  make(map[string][]models.PricePoint),
+	}
+	for _, p := range points {
+		mk := normalize(p.Make)
+		if mk == "" {
+			continue
+		}
+		idx.byMake[mk] = append(idx.byMake[mk], p)
+		if md := normalize(p.Model); md != "" {
+			idx.byModel[modelKey(mk, md)] = append(idx.byModel[modelKey(mk, md)], p)
+		}
+	}
+	return idx
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func modelKey(carMake, model string) string {
+	return carMake + "|" + model
+}
+
+// comparables returns the points near year for the model, or for the make
+// when the model has too few, and the basis they were drawn from.
+func (idx *index) comparables(carMake, model string, year int) ([]models.PricePoint, string) {
+	mk, md := normalize(carMake), normalize(model)
+	near := func(points []models.PricePoint, span int) []models.PricePoint {
+		var out []models.PricePoint
+		for _, p := range points {
+			if d := p.Year - year; d >= -span && d <= span {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+
+	if pts := near(idx.byModel[modelKey(mk, md)], modelYearSpan); len(pts) >= minModelComparables {
+		return pts, basisModel
+	}
+	if pts := near(idx.byMake[mk], makeYearSpan); len(pts) >= minComparables {
+		return pts, basisMake
+	}
+	return nil, ""
+}
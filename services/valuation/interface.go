@@ -0,0 +1,40 @@
+package valuation
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/models"
+)
+
+var (
+	ErrInvalidValuation = errors.New("invalid valuation request")
+	ErrNoMarketData     = errors.New("not enough market data to value this car")
+)
+
+// ValuationService estimates what a car is worth from the prices similar
+// cars sold, closed or were bid at. Estimates are served from an in-memory
+// index of price points that Refresh rebuilds.
+type ValuationService interface {
+	Estimate(req models.ValuationRequest) (*models.Valuation, error)
+	// AttachBadges sets Market on the priced, on-market listings whose
+	// valuation is at least of medium confidence.
+	AttachBadges(listings []models.Listing)
+	Refresh(ctx context.Context) error
+}
+
+type valuationService struct {
+	listings listingRepo.ListingRepository
+
+	mu  sync.RWMutex
+	idx *index
+}
+
+func NewValuationService(listings listingRepo.ListingRepository) ValuationService {
+	return &valuationService{
+		listings: listings,
+		idx:      newIndex(nil),
+	}
+}
@@ -0,0 +1,99 @@
+package valuation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"carsawa/models"
+	"carsawa/utils"
+
+	"go.uber.org/zap"
+)
+
+// pricePointWindow is how far back sales count towards a valuation.
+const pricePointWindow = 2 * 365 * 24 * time.Hour
+
+const minYear = 1950
+
+func (s *valuationService) index() *index {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.idx
+}
+
+func (s *valuationService) Estimate(req models.ValuationRequest) (*models.Valuation, error) {
+	req.Make, req.Model = strings.TrimSpace(req.Make), strings.TrimSpace(req.Model)
+	now := time.Now()
+	switch {
+	case req.Make == "" || req.Model == "":
+		return nil, fmt.Errorf("%w: make and model are required", ErrInvalidValuation)
+	case req.Year < minYear || req.Year > now.Year()+1:
+		return nil, fmt.Errorf("%w: year must be between %d and %d", ErrInvalidValuation, minYear, now.Year()+1)
+	case req.Mileage < 0:
+		return nil, fmt.Errorf("%w: mileage cannot be negative", ErrInvalidValuation)
+	}
+
+	points, basis := s.index().comparables(req.Make, req.Model, req.Year)
+	if len(points) == 0 {
+		return nil, ErrNoMarketData
+	}
+	return estimate(req, points, basis, now), nil
+}
+
+func (s *valuationService) AttachBadges(listings []models.Listing) {
+	for i := range listings {
+		l := &listings[i]
+		cd := l.CarDetails
+		if cd.Price <= 0 || (l.Status != models.ListingStatusActive && l.Status != models.ListingStatusOpen) {
+			continue
+		}
+		v, err := s.Estimate(models.ValuationRequest{Make: cd.Make, Model: cd.Model, Year: cd.Year, Mileage: cd.Mileage})
+		if err != nil || v.ConfidenceLevel == models.ConfidenceLow || v.Estimate <= 0 {
+			continue
+		}
+		r, delta := rating(cd.Price, v.Estimate)
+		l.Market = &models.MarketBadge{
+			Rating:   r,
+			Estimate: v.Estimate,
+			DeltaPct: math.Round(delta*1000) / 10,
+		}
+	}
+}
+
+func (s *valuationService) Refresh(ctx context.Context) error {
+	points, err := s.listings.GetPricePoints(ctx, time.Now().Add(-pricePointWindow))
+	if err != nil {
+		return err
+	}
+	idx := newIndex(points)
+	s.mu.Lock()
+	s.idx = idx
+	s.mu.Unlock()
+	return nil
+}
+
+// RunRefresher loads the price points straight away and then reloads them
+// every interval until ctx is cancelled.
+func RunRefresher(ctx context.Context, svc ValuationService, interval time.Duration) {
+	refresh := func() {
+		if err := svc.Refresh(ctx); err != nil {
+			utils.GetLogger().Error("valuation refresh failed", zap.Error(err))
+		}
+	}
+	refresh()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package valuation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"carsawa/models"
+)
+
+func TestValuationEstimate(t *testing.T) {
+	s := &valuationService{idx: newIndex(sales("Toyota", "Axio", 2015, 6, 1000000, time.Now()))}
+
+	tests := []struct {
+		name string
+		req  models.ValuationRequest
+		err  error
+	}{
+		{name: "comparables found", req: models.ValuationRequest{Make: "Toyota", Model: "Axio", Year: 2015, Mileage: 90000}},
+		{name: "case and spacing ignored", req: models.ValuationRequest{Make: " toyota ", Model: "AXIO", Year: 2015}},
+		{name: "missing model", req: models.ValuationRequest{Make: "Toyota", Year: 2015}, err: ErrInvalidValuation},
+		{name: "year too old", req: models.ValuationRequest{Make: "Toyota", Model: "Axio", Year: 1900}, err: ErrInvalidValuation},
+		{name: "year in the future", req: models.ValuationRequest{Make: "Toyota", Model: "Axio", Year: time.Now().Year() + 2}, err: ErrInvalidValuation},
+		{name: "negative mileage", req: models.ValuationRequest{Make: "Toyota", Model: "Axio", Year: 2015, Mileage: -1}, err: ErrInvalidValuation},
+		{name: "no comparables", req: models.ValuationRequest{Make: "Subaru", Model: "Forester", Year: 2015}, err: ErrNoMarketData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := s.Estimate(tt.req)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Estimate(%+v) error = %v, want %v", tt.req, err, tt.err)
+			}
+			if err == nil && v.Estimate <= 0 {
+				t.Errorf("Estimate(%+v) = %.0f, want a positive estimate", tt.req, v.Estimate)
+			}
+		})
+	}
+}
+
+func TestAttachBadges(t *testing.T) {
+	now := time.Now()
+	points := append(sales("Toyota", "Axio", 2015, 6, 1000000, now), sales("Nissan", "Note", 2015, 3, 800000, now)...)
+	s := &valuationService{idx: newIndex(points)}
+
+	listing := func(carMake, model string, price float64, status models.ListingStatus) models.Listing {
+		return models.Listing{
+			CarDetails: models.CarDetails{Make: carMake, Model: model, Year: 2015, Mileage: 90000, Price: price},
+			Status:     status,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		listing models.Listing
+		want    *models.MarketBadge
+	}{
+		{
+			name:    "great price",
+			listing: listing("Toyota", "Axio", 850000, models.ListingStatusActive),
+			want:    &models.MarketBadge{Rating: models.PriceRatingGreat, Estimate: 1000000, DeltaPct: -15},
+		},
+		{
+			name:    "good price",
+			listing: listing("Toyota", "Axio", 950000, models.ListingStatusActive),
+			want:    &models.MarketBadge{Rating: models.PriceRatingGood, Estimate: 1000000, DeltaPct: -5},
+		},
+		{
+			name:    "fair price on an open bid listing",
+			listing: listing("Toyota", "Axio", 1050000, models.ListingStatusOpen),
+			want:    &models.MarketBadge{Rating: models.PriceRatingFair, Estimate: 1000000, DeltaPct: 5},
+		},
+		{
+			name:    "high price",
+			listing: listing("Toyota", "Axio", 1200000, models.ListingStatusActive),
+			want:    &models.MarketBadge{Rating: models.PriceRatingHigh, Estimate: 1000000, DeltaPct: 20},
+		},
+		{name: "sold listing", listing: listing("Toyota", "Axio", 850000, models.ListingStatusSold)},
+		{name: "no price", listing: listing("Toyota", "Axio", 0, models.ListingStatusActive)},
+		{name: "no market data", listing: listing("Subaru", "Forester", 850000, models.ListingStatusActive)},
+		{name: "low confidence", listing: listing("Nissan", "Tiida", 500000, models.ListingStatusActive)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listings := []models.Listing{tt.listing}
+			s.AttachBadges(listings)
+			got := listings[0].Market
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("AttachBadges() badge = %+v, want none", *got)
+			case tt.want != nil && (got == nil || *got != *tt.want):
+				t.Errorf("AttachBadges() badge = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
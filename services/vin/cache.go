@@ -0,0 +1,149 @@
+package vin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	vinRepo "carsawa/database/repository/vin"
+	"carsawa/models"
+	"carsawa/utils"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const cachePrefix = "vin:"
+
+// Error codes stored for numbers that can never decode.
+const (
+	errCodeMalformed  = "malformed"
+	errCodeCheckDigit = "check_digit"
+)
+
+// CacheConfig sets how long decode results are kept.
+type CacheConfig struct {
+	TTL         time.Duration // Results confirmed by a registry
+	NegativeTTL time.Duration // Invalid numbers and numbers no registry knows
+}
+
+type cachedDecoder struct {
+	next  VINDecoder
+	redis *redis.Client
+	repo  vinRepo.VINCacheRepository
+	cfg   CacheConfig
+}
+
+// NewCachedDecoder puts a Redis tier and a Mongo tier in front of next.
+// Either tier may be nil. Results marked Incomplete are not cached, so a
+// registry outage is retried on the next decode.
+func NewCachedDecoder(next VINDecoder, rdb *redis.Client, repo vinRepo.VINCacheRepository, cfg CacheConfig) VINDecoder {
+	return &cachedDecoder{next: next, redis: rdb, repo: repo, cfg: cfg}
+}
+
+func (c *cachedDecoder) Decode(ctx context.Context, number string) (*models.DecodedVIN, error) {
+	key := Canonical(number)
+	if hit := c.lookup(ctx, key); hit != nil {
+		return resolve(hit)
+	}
+
+	d, err := c.next.Decode(ctx, number)
+	var entry *models.VINCacheEntry
+	switch {
+	case errors.Is(err, ErrMalformed):
+		entry = &models.VINCacheEntry{ErrorCode: errCodeMalformed, Error: err.Error()}
+	case errors.Is(err, ErrBadCheckDigit):
+		entry = &models.VINCacheEntry{ErrorCode: errCodeCheckDigit, Error: err.Error()}
+	case err != nil:
+		return nil, err
+	case d.Incomplete:
+		return d, nil
+	default:
+		entry = &models.VINCacheEntry{Result: d}
+	}
+
+	ttl := c.cfg.NegativeTTL
+	if d != nil && d.Verified {
+		ttl = c.cfg.TTL
+	}
+	if ttl > 0 {
+		entry.Number = key
+		entry.ExpiresAt = time.Now().Add(ttl)
+		c.store(ctx, entry, ttl)
+	}
+	return d, err
+}
+
+// lookup tries Redis, then Mongo, warming Redis from a Mongo hit.
+func (c *cachedDecoder) lookup(ctx context.Context, key string) *models.VINCacheEntry {
+	if c.redis != nil {
+		if raw, err := c.redis.Get(ctx, cachePrefix+key).Bytes(); err == nil {
+			var entry models.VINCacheEntry
+			if json.Unmarshal(raw, &entry) == nil {
+				return &entry
+			}
+		} else if err != redis.Nil {
+			utils.GetLogger().Warn("VIN cache: redis get failed", zap.Error(err))
+		}
+	}
+	if c.repo == nil {
+		return nil
+	}
+	entry, err := c.repo.GetEntry(ctx, key)
+	if err != nil {
+		if !errors.Is(err, vinRepo.ErrNotFound) {
+			utils.GetLogger().Warn("VIN cache: mongo get failed", zap.Error(err))
+		}
+		return nil
+	}
+	if ttl := time.Until(entry.ExpiresAt); ttl > 0 {
+		c.storeRedis(ctx, entry, ttl)
+	}
+	return entry
+}
+
+func (c *cachedDecoder) store(ctx context.Context, entry *models.VINCacheEntry, ttl time.Duration) {
+	c.storeRedis(ctx, entry, ttl)
+	if c.repo != nil {
+		if err := c.repo.PutEntry(ctx, entry); err != nil {
+			utils.GetLogger().Warn("VIN cache: mongo put failed", zap.Error(err))
+		}
+	}
+}
+
+func (c *cachedDecoder) storeRedis(ctx context.Context, entry *models.VINCacheEntry, ttl time.Duration) {
+	if c.redis == nil {
+		return
+	}
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := c.redis.Set(ctx, cachePrefix+entry.Number, raw, ttl).Err(); err != nil {
+		utils.GetLogger().Warn("VIN cache: redis set failed", zap.Error(err))
+	}
+}
+
+// resolve turns a cached entry back into what the decoder originally returned.
+func resolve(entry *models.VINCacheEntry) (*models.DecodedVIN, error) {
+	switch entry.ErrorCode {
+	case errCodeMalformed:
+		return nil, &cachedError{base: ErrMalformed, msg: entry.Error}
+	case errCodeCheckDigit:
+		return nil, &cachedError{base: ErrBadCheckDigit, msg: entry.Error}
+	}
+	if entry.Result == nil {
+		return nil, ErrMalformed
+	}
+	return entry.Result, nil
+}
+
+// cachedError replays a stored decode error while still matching its sentinel.
+type cachedError struct {
+	base error
+	msg  string
+}
+
+func (e *cachedError) Error() string { return e.msg }
+func (e *cachedError) Unwrap() error { return e.base }
@@ -0,0 +1,58 @@
+package vin
+
+// transliteration gives each VIN letter its ISO 3779 numeric value.
+var transliteration = map[byte]int{
+	'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+	'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+	'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+}
+
+var positionWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// checkDigit computes the position-9 check character of a 17-character VIN.
+func checkDigit(vin string) byte {
+	sum := 0
+	for i := 0; i < 17; i++ {
+		c := vin[i]
+		v := int(c - '0')
+		if c >= 'A' && c <= 'Z' {
+			v = transliteration[c]
+		}
+		sum += v * positionWeights[i]
+	}
+	if r := sum % 11; r < 10 {
+		return byte('0' + r)
+	}
+	return 'X'
+}
+
+// requiresCheckDigit reports whether the VIN was issued where the check digit
+// is mandatory. European and Japanese makers often use position 9 for other
+// data, so their VINs are decoded without enforcing it.
+func requiresCheckDigit(vin string) bool {
+	switch c := vin[0]; {
+	case c >= '1' && c <= '5':
+		return true // North America
+	case c == 'L':
+		return true // China
+	}
+	return false
+}
+
+func regionOf(c byte) string {
+	switch {
+	case c >= 'A' && c <= 'H':
+		return "Africa"
+	case c >= 'J' && c <= 'R':
+		return "Asia"
+	case c >= 'S' && c <= 'Z':
+		return "Europe"
+	case c >= '1' && c <= '5':
+		return "North America"
+	case c == '6' || c == '7':
+		return "Oceania"
+	case c == '8' || c == '9':
+		return "South America"
+	}
+	return ""
+}
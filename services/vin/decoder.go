@@ -0,0 +1,128 @@
+package vin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"carsawa/models"
+	"carsawa/utils"
+
+	"go.uber.org/zap"
+)
+
+// ErrNoRecord is returned by a provider that has nothing on a number.
+var ErrNoRecord = errors.New("no record for this VIN")
+
+// VINDecoder turns a VIN or chassis number into what is known about the car.
+// Numbers that are malformed or fail their check digit return ErrMalformed
+// or ErrBadCheckDigit.
+type VINDecoder interface {
+	Decode(ctx context.Context, number string) (*models.DecodedVIN, error)
+}
+
+// Provider is a remote registry consulted after the offline decode passes.
+type Provider interface {
+	Name() string
+	// Lookup returns ErrNoRecord when the registry does not know the number;
+	// any other error is treated as the registry being unavailable.
+	Lookup(ctx context.Context, d *models.DecodedVIN) (*Record, error)
+}
+
+// Record is what a registry reports about a car.
+type Record struct {
+	Make      string
+	Model     string
+	ModelYear int
+	BodyClass string
+}
+
+type localDecoder struct{}
+
+// NewLocalDecoder decodes from the embedded tables only.
+func NewLocalDecoder() VINDecoder {
+	return localDecoder{}
+}
+
+func (localDecoder) Decode(_ context.Context, number string) (*models.DecodedVIN, error) {
+	return Decode(number)
+}
+
+type chainDecoder struct {
+	providers []Provider
+}
+
+// NewChain decodes offline, then asks each provider in turn until one has a
+// record. A provider that cannot be reached is skipped and the result is
+// marked Incomplete so it is not cached.
+func NewChain(providers ...Provider) VINDecoder {
+	return &chainDecoder{providers: providers}
+}
+
+func (c *chainDecoder) Decode(ctx context.Context, number string) (*models.DecodedVIN, error) {
+	d, err := Decode(number)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range c.providers {
+		rec, err := p.Lookup(ctx, d)
+		if errors.Is(err, ErrNoRecord) {
+			continue
+		}
+		if err != nil {
+			d.Incomplete = true
+			utils.GetLogger().Warn("VIN provider unavailable",
+				zap.String("provider", p.Name()), zap.String("vin", d.Number), zap.Error(err))
+			continue
+		}
+		merge(d, p.Name(), rec)
+		d.Incomplete = false
+		break
+	}
+	return d, nil
+}
+
+// merge lays a registry record over the offline decode; the registry wins
+// where they disagree.
+func merge(d *models.DecodedVIN, source string, rec *Record) {
+	if rec.Make != "" {
+		d.Make = rec.Make
+	}
+	if rec.Model != "" {
+		d.Model = rec.Model
+	}
+	if rec.ModelYear > 0 {
+		d.ModelYears = []int{rec.ModelYear}
+	}
+	if rec.BodyClass != "" {
+		d.BodyClass = rec.BodyClass
+	}
+	d.Verified = true
+	d.Sources = append(d.Sources, source)
+}
+
+// NewProviders builds the remote providers named in a comma-separated list,
+// in order. "nhtsa" is the US vPIC service and "http" a generic JSON API.
+func NewProviders(names string, httpCfg HTTPProviderConfig, timeout time.Duration) ([]Provider, error) {
+	var providers []Provider
+	for _, name := range strings.Split(names, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "", "local":
+			// The offline tables always run first.
+		case "nhtsa":
+			providers = append(providers, NewNHTSAProvider(timeout))
+		case "http":
+			p, err := NewHTTPProvider(httpCfg, timeout)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, p)
+		default:
+			return nil, fmt.Errorf("vin: unknown provider %q", name)
+		}
+	}
+	return providers, nil
+}
@@ -0,0 +1,175 @@
+package vin
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	vinRepo "carsawa/database/repository/vin"
+	"carsawa/models"
+)
+
+const testVIN = "1HGCM82633A004352"
+
+// fakeProvider answers every lookup with the same record or error.
+type fakeProvider struct {
+	name  string
+	rec   *Record
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Lookup(_ context.Context, _ *models.DecodedVIN) (*Record, error) {
+	p.calls++
+	return p.rec, p.err
+}
+
+// memCache is an in-memory durable cache tier.
+type memCache struct {
+	entries map[string]*models.VINCacheEntry
+}
+
+func (c *memCache) GetEntry(_ context.Context, number string) (*models.VINCacheEntry, error) {
+	e, ok := c.entries[number]
+	if !ok || time.Now().After(e.ExpiresAt) {
+		return nil, vinRepo.ErrNotFound
+	}
+	return e, nil
+}
+
+func (c *memCache) PutEntry(_ context.Context, entry *models.VINCacheEntry) error {
+	c.entries[entry.Number] = entry
+	return nil
+}
+
+func TestChainDecoder(t *testing.T) {
+	down := errors.New("registry unreachable")
+	accord := &Record{Make: "Honda", Model: "Accord", ModelYear: 2003}
+
+	tests := []struct {
+		name       string
+		providers  []*fakeProvider
+		calls      []int
+		verified   bool
+		incomplete bool
+		model      string
+		sources    []string
+	}{
+		{
+			name:      "first provider answers",
+			providers: []*fakeProvider{{name: "a", rec: accord}, {name: "b", rec: accord}},
+			calls:     []int{1, 0},
+			verified:  true,
+			model:     "Accord",
+			sources:   []string{LocalSource, "a"},
+		},
+		{
+			name:      "falls through unavailable provider",
+			providers: []*fakeProvider{{name: "a", err: down}, {name: "b", rec: accord}},
+			calls:     []int{1, 1},
+			verified:  true,
+			model:     "Accord",
+			sources:   []string{LocalSource, "b"},
+		},
+		{
+			name:      "falls through provider without a record",
+			providers: []*fakeProvider{{name: "a", err: ErrNoRecord}, {name: "b", rec: accord}},
+			calls:     []int{1, 1},
+			verified:  true,
+			model:     "Accord",
+			sources:   []string{LocalSource, "b"},
+		},
+		{
+			name:       "no provider reachable",
+			providers:  []*fakeProvider{{name: "a", err: ErrNoRecord}, {name: "b", err: down}},
+			calls:      []int{1, 1},
+			incomplete: true,
+			sources:    []string{LocalSource},
+		},
+		{
+			name:      "no provider knows the number",
+			providers: []*fakeProvider{{name: "a", err: ErrNoRecord}},
+			calls:     []int{1},
+			sources:   []string{LocalSource},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]Provider, len(tt.providers))
+			for i, p := range tt.providers {
+				providers[i] = p
+			}
+			d, err := NewChain(providers...).Decode(context.Background(), testVIN)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			for i, p := range tt.providers {
+				if p.calls != tt.calls[i] {
+					t.Errorf("provider %s called %d times, want %d", p.name, p.calls, tt.calls[i])
+				}
+			}
+			if d.Verified != tt.verified || d.Incomplete != tt.incomplete {
+				t.Errorf("Verified = %v Incomplete = %v, want %v %v", d.Verified, d.Incomplete, tt.verified, tt.incomplete)
+			}
+			if d.Model != tt.model {
+				t.Errorf("Model = %q, want %q", d.Model, tt.model)
+			}
+			if !reflect.DeepEqual(d.Sources, tt.sources) {
+				t.Errorf("Sources = %v, want %v", d.Sources, tt.sources)
+			}
+		})
+	}
+}
+
+func TestCachedDecoder(t *testing.T) {
+	down := errors.New("registry unreachable")
+	accord := &Record{Make: "Honda", Model: "Accord", ModelYear: 2003}
+
+	tests := []struct {
+		name    string
+		number  string
+		repeat  string // Decoded second, in another spelling of the same number
+		rec     *Record
+		err     error
+		calls   int // Provider lookups over both decodes
+		cached  bool
+		wantErr error
+	}{
+		{name: "registry result is cached", number: testVIN, repeat: "1hgcm826 33a004352", rec: accord, calls: 1, cached: true},
+		{name: "unknown number is cached", number: testVIN, repeat: testVIN, err: ErrNoRecord, calls: 1, cached: true},
+		{name: "registry outage is not cached", number: testVIN, repeat: testVIN, err: down, calls: 2},
+		{name: "malformed number is cached", number: "NOT-A-VIN", repeat: "not a vin", calls: 0, cached: true, wantErr: ErrMalformed},
+		{name: "bad check digit is cached", number: "1HGCM82643A004352", repeat: "1HGCM82643A004352", calls: 0, cached: true, wantErr: ErrBadCheckDigit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider := &fakeProvider{name: "registry", rec: tt.rec, err: tt.err}
+			repo := &memCache{entries: map[string]*models.VINCacheEntry{}}
+			dec := NewCachedDecoder(NewChain(provider), nil, repo, CacheConfig{TTL: time.Hour, NegativeTTL: time.Hour})
+
+			first, err := dec.Decode(ctx, tt.number)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("first Decode(%q) error = %v, want %v", tt.number, err, tt.wantErr)
+			}
+			if _, ok := repo.entries[Canonical(tt.number)]; ok != tt.cached {
+				t.Errorf("cached after first decode = %v, want %v", ok, tt.cached)
+			}
+
+			second, err := dec.Decode(ctx, tt.repeat)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("second Decode(%q) error = %v, want %v", tt.repeat, err, tt.wantErr)
+			}
+			if provider.calls != tt.calls {
+				t.Errorf("provider called %d times, want %d", provider.calls, tt.calls)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(first, second) {
+				t.Errorf("second decode = %+v, want %+v", second, first)
+			}
+		})
+	}
+}
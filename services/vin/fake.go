@@ -0,0 +1,64 @@
+package vin
+
+import (
+	"context"
+	"sync"
+
+	"carsawa/models"
+)
+
+// FakeDecoder is a VINDecoder for tests and local development. Numbers given
+// a result or error return it; anything else falls through to the offline
+// decode.
+type FakeDecoder struct {
+	mu      sync.Mutex
+	results map[string]*models.DecodedVIN
+	errs    map[string]error
+	calls   map[string]int
+}
+
+func NewFakeDecoder() *FakeDecoder {
+	return &FakeDecoder{
+		results: make(map[string]*models.DecodedVIN),
+		errs:    make(map[string]error),
+		calls:   make(map[string]int),
+	}
+}
+
+// Set makes number decode to d.
+func (f *FakeDecoder) Set(number string, d *models.DecodedVIN) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[Canonical(number)] = d
+}
+
+// Fail makes number fail to decode with err.
+func (f *FakeDecoder) Fail(number string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[Canonical(number)] = err
+}
+
+// Calls reports how many times number has been decoded.
+func (f *FakeDecoder) Calls(number string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[Canonical(number)]
+}
+
+func (f *FakeDecoder) Decode(_ context.Context, number string) (*models.DecodedVIN, error) {
+	key := Canonical(number)
+	f.mu.Lock()
+	f.calls[key]++
+	d, err := f.results[key], f.errs[key]
+	f.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		out := *d
+		return &out, nil
+	}
+	return Decode(number)
+}
@@ -0,0 +1,118 @@
+package vin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"carsawa/models"
+)
+
+// HTTPProviderConfig points the generic provider at any JSON decoding API.
+// Field paths use dots to reach nested values, e.g. "data.vehicle.make".
+type HTTPProviderConfig struct {
+	URL        string // Must contain {vin}, e.g. https://api.example.com/decode/{vin}
+	APIKey     string
+	KeyHeader  string // Defaults to X-API-Key
+	MakeField  string // Defaults to make
+	ModelField string // Defaults to model
+	YearField  string // Defaults to year
+	BodyField  string
+}
+
+// HTTPProvider decodes VINs and chassis numbers through a configurable JSON API.
+type HTTPProvider struct {
+	cfg    HTTPProviderConfig
+	client *http.Client
+}
+
+func NewHTTPProvider(cfg HTTPProviderConfig, timeout time.Duration) (*HTTPProvider, error) {
+	if !strings.Contains(cfg.URL, "{vin}") {
+		return nil, errors.New("vin: HTTP provider URL must contain {vin}")
+	}
+	if cfg.KeyHeader == "" {
+		cfg.KeyHeader = "X-API-Key"
+	}
+	if cfg.MakeField == "" {
+		cfg.MakeField = "make"
+	}
+	if cfg.ModelField == "" {
+		cfg.ModelField = "model"
+	}
+	if cfg.YearField == "" {
+		cfg.YearField = "year"
+	}
+	return &HTTPProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (p *HTTPProvider) Name() string { return "http" }
+
+func (p *HTTPProvider) Lookup(ctx context.Context, d *models.DecodedVIN) (*Record, error) {
+	u := strings.ReplaceAll(p.cfg.URL, "{vin}", url.PathEscape(d.Number))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.APIKey != "" {
+		req.Header.Set(p.cfg.KeyHeader, p.cfg.APIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNoRecord
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("VIN API status: %d", resp.StatusCode)
+	}
+
+	var body interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	rec := &Record{
+		Make:      field(body, p.cfg.MakeField),
+		Model:     field(body, p.cfg.ModelField),
+		BodyClass: field(body, p.cfg.BodyField),
+	}
+	rec.ModelYear, _ = strconv.Atoi(field(body, p.cfg.YearField))
+	if rec.Make == "" {
+		return nil, ErrNoRecord
+	}
+	return rec, nil
+}
+
+// field follows a dotted path into decoded JSON and renders the value as a string.
+func field(v interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = obj[key]
+	}
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package vin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"carsawa/models"
+)
+
+const nhtsaURL = "https://vpic.nhtsa.dot.gov/api/vehicles/DecodeVINValuesExtended/%s?format=json"
+
+type nhtsaResponse struct {
+	Results []nhtsaResult `json:"Results"`
+}
+
+type nhtsaResult struct {
+	ErrorCode    string `json:"ErrorCode"`
+	Make         string `json:"Make"`
+	Model        string `json:"Model"`
+	ModelYear    string `json:"ModelYear"`
+	BodyClass    string `json:"BodyClass"`
+	PlantCountry string `json:"PlantCountry"`
+}
+
+// NHTSAProvider looks VINs up in the US vPIC database, which only has full
+// data for vehicles sold in North America.
+type NHTSAProvider struct {
+	client *http.Client
+}
+
+func NewNHTSAProvider(timeout time.Duration) *NHTSAProvider {
+	return &NHTSAProvider{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *NHTSAProvider) Name() string { return "nhtsa" }
+
+func (p *NHTSAProvider) Lookup(ctx context.Context, d *models.DecodedVIN) (*Record, error) {
+	if d.Kind != models.VINKindVIN {
+		return nil, ErrNoRecord
+	}
+
+	url := fmt.Sprintf(nhtsaURL, d.Number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("NHTSA API status: %d", resp.StatusCode)
+	}
+
+	var apiResp nhtsaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, err
+	}
+	if len(apiResp.Results) == 0 {
+		return nil, fmt.Errorf("no results from NHTSA")
+	}
+
+	result := apiResp.Results[0]
+	if result.ErrorCode != "0" || result.Make == "" {
+		return nil, ErrNoRecord
+	}
+	year, _ := strconv.Atoi(result.ModelYear)
+	return &Record{
+		Make:      result.Make,
+		Model:     result.Model,
+		ModelYear: year,
+		BodyClass: result.BodyClass,
+	}, nil
+}
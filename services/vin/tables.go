@@ -0,0 +1,85 @@
+package vin
+
+import (
+	"carsawa/models"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// wmiEntry is one manufacturer in the embedded WMI table. Entries with a
+// two-character code cover every WMI that starts with it.
+type wmiEntry struct {
+	Manufacturer string
+	Make         string
+	Country      string
+}
+
+// chassisEntry is one Japanese model code in the embedded chassis table.
+type chassisEntry struct {
+	Make  string
+	Model string
+}
+
+var (
+	//go:embed wmi.csv
+	wmiCSV string
+	//go:embed chassis.csv
+	chassisCSV string
+
+	wmiTable     = loadTable(wmiCSV, func(r []string) wmiEntry { return wmiEntry{r[1], r[2], r[3]} })
+	chassisTable = loadTable(chassisCSV, func(r []string) chassisEntry { return chassisEntry{r[1], r[2]} })
+)
+
+// loadTable parses an embedded CSV keyed by its first column, skipping the header.
+func loadTable[T any](data string, row func([]string) T) map[string]T {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("vin: bad embedded table: %v", err))
+	}
+	table := make(map[string]T, len(records))
+	for _, r := range records[1:] {
+		table[r[0]] = row(r)
+	}
+	return table
+}
+
+func lookupWMI(wmi string) (wmiEntry, bool) {
+	if e, ok := wmiTable[wmi]; ok {
+		return e, true
+	}
+	e, ok := wmiTable[wmi[:2]]
+	return e, ok
+}
+
+// lookupChassis matches the longest known prefix of the model code, so
+// "NZE141" finds the NZE14 Axio rather than a generic NZE entry.
+func lookupChassis(modelCode string) (chassisEntry, bool) {
+	for n := len(modelCode); n > 0; n-- {
+		if e, ok := chassisTable[modelCode[:n]]; ok {
+			return e, true
+		}
+	}
+	return chassisEntry{}, false
+}
+
+// decodeChassis reads a chassis number's model code. Chassis numbers carry no
+// check digit, so CheckDigitOK only reports whether the model code is one the
+// table knows; an unknown code decodes unverified, with no make or model.
+func decodeChassis(number, modelCode string) *models.DecodedVIN {
+	d := &models.DecodedVIN{
+		Number:    number,
+		Kind:      models.VINKindChassis,
+		ModelCode: modelCode,
+		Country:   "Japan",
+		Region:    "Asia",
+		Sources:   []string{LocalSource},
+	}
+	if e, ok := lookupChassis(modelCode); ok {
+		d.Make = e.Make
+		d.Model = e.Model
+		d.CheckDigitOK = true
+	}
+	return d
+}
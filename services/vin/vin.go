@@ -0,0 +1,121 @@
+package vin
+
+import (
+	"carsawa/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrMalformed     = errors.New("not a valid VIN or chassis number")
+	ErrBadCheckDigit = errors.New("VIN check digit does not match")
+)
+
+// LocalSource names the offline tables in DecodedVIN.Sources.
+const LocalSource = "local"
+
+var (
+	vinPattern     = regexp.MustCompile(`^[A-HJ-NPR-Z0-9]{17}$`)
+	chassisPattern = regexp.MustCompile(`^([A-Z]{1,4}[0-9]{1,3}?[A-Z]{0,3})-?([0-9]{4,7})$`)
+)
+
+// Normalize upper-cases a number and drops the spacing people type into it.
+// Chassis numbers keep a single hyphen between model code and serial.
+func Normalize(number string) string {
+	parts := strings.FieldsFunc(strings.ToUpper(number), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '\t'
+	})
+	return strings.Join(parts, "-")
+}
+
+// Canonical is the form a number is stored and compared in: VINs without
+// separators, chassis numbers as MODELCODE-SERIAL whether or not the hyphen
+// was typed.
+func Canonical(number string) string {
+	n := Normalize(number)
+	if code, serial, ok := splitChassis(n); ok {
+		return code + "-" + serial
+	}
+	return strings.ReplaceAll(n, "-", "")
+}
+
+// splitChassis splits a normalised chassis number into model code and serial.
+// Without a hyphen the split is a guess that leaves the serial as long as
+// possible, so such numbers are only taken as chassis numbers when the
+// chassis table knows their model code.
+func splitChassis(n string) (code, serial string, ok bool) {
+	m := chassisPattern.FindStringSubmatch(n)
+	if m == nil {
+		return "", "", false
+	}
+	if !strings.Contains(n, "-") {
+		if _, known := lookupChassis(m[1]); !known {
+			return "", "", false
+		}
+	}
+	return m[1], m[2], true
+}
+
+// Decode reads everything the offline tables know about a VIN or chassis
+// number. 17-character VINs from regions that mandate a check digit are
+// rejected when it does not match.
+func Decode(number string) (*models.DecodedVIN, error) {
+	n := Canonical(number)
+	if code, _, ok := splitChassis(n); ok {
+		return decodeChassis(n, code), nil
+	}
+	if !vinPattern.MatchString(n) {
+		return nil, fmt.Errorf("%w: %q", ErrMalformed, number)
+	}
+
+	d := &models.DecodedVIN{
+		Number:       n,
+		Kind:         models.VINKindVIN,
+		WMI:          n[:3],
+		Region:       regionOf(n[0]),
+		CheckDigitOK: checkDigit(n) == n[8],
+	}
+	if m, ok := lookupWMI(d.WMI); ok {
+		d.Manufacturer = m.Manufacturer
+		d.Make = m.Make
+		d.Country = m.Country
+	}
+	if requiresCheckDigit(n) && !d.CheckDigitOK {
+		return nil, fmt.Errorf("%w: expected %q in position 9", ErrBadCheckDigit, checkDigit(n))
+	}
+	d.ModelYears = modelYears(n)
+	d.Sources = []string{LocalSource}
+	return d, nil
+}
+
+// SameMake compares makes loosely so "VW" matches "Volkswagen" and
+// "Mercedes" matches "Mercedes-Benz".
+func SameMake(a, b string) bool {
+	ka, kb := makeKey(a), makeKey(b)
+	return ka != "" && ka == kb
+}
+
+var makeAliases = map[string]string{
+	"vw":             "volkswagen",
+	"mercedes":       "mercedesbenz",
+	"benz":           "mercedesbenz",
+	"chevy":          "chevrolet",
+	"rangerover":     "landrover",
+	"mitsubishifuso": "mitsubishi",
+}
+
+func makeKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	k := b.String()
+	if alias, ok := makeAliases[k]; ok {
+		return alias
+	}
+	return k
+}
@@ -0,0 +1,104 @@
+package vin
+
+import (
+	"errors"
+	"testing"
+
+	"carsawa/models"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		vin  string
+		want byte
+	}{
+		{vin: "1M8GDM9AXKP042788", want: 'X'},
+		{vin: "11111111111111111", want: '1'},
+		{vin: "1HGCM82633A004352", want: '3'},
+		{vin: "JH4KA7561PC008269", want: '1'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vin, func(t *testing.T) {
+			if got := checkDigit(tt.vin); got != tt.want {
+				t.Errorf("checkDigit(%q) = %q, want %q", tt.vin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCheckDigit(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		digitOK bool
+		err     error
+	}{
+		{name: "north american valid", number: "1HGCM82633A004352", digitOK: true},
+		{name: "north american lower case with spaces", number: "1hgcm826 33a004352", digitOK: true},
+		{name: "north american bad digit", number: "1HGCM82643A004352", err: ErrBadCheckDigit},
+		{name: "chinese bad digit", number: "LSGPC52U06F102334", err: ErrBadCheckDigit},
+		{name: "european digit not enforced", number: "WVWZZZ1JZ3W386752"},
+		{name: "too short", number: "1HGCM82633A00435", err: ErrMalformed},
+		{name: "contains I", number: "1HGCM82633I004352", err: ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Decode(tt.number)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Decode(%q) error = %v, want %v", tt.number, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if d.Kind != models.VINKindVIN {
+				t.Errorf("Decode(%q).Kind = %q, want %q", tt.number, d.Kind, models.VINKindVIN)
+			}
+			if d.CheckDigitOK != tt.digitOK {
+				t.Errorf("Decode(%q).CheckDigitOK = %v, want %v", tt.number, d.CheckDigitOK, tt.digitOK)
+			}
+		})
+	}
+}
+
+func TestDecodeChassis(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   string
+		code   string
+		make   string
+		known  bool
+		err    error
+	}{
+		{name: "hyphenated", number: "NZE141-1234567", want: "NZE141-1234567", code: "NZE141", make: "Toyota", known: true},
+		{name: "without hyphen", number: "NZE1411234567", want: "NZE141-1234567", code: "NZE141", make: "Toyota", known: true},
+		{name: "spaced", number: "nze141 1234567", want: "NZE141-1234567", code: "NZE141", make: "Toyota", known: true},
+		{name: "short model code without hyphen", number: "ACA311234567", want: "ACA31-1234567", code: "ACA31", make: "Toyota", known: true},
+		{name: "unknown code is unverified", number: "ABC1-1234", want: "ABC1-1234", code: "ABC1"},
+		{name: "unknown code without hyphen", number: "ABC11234", err: ErrMalformed},
+		{name: "serial too long", number: "NZE141-123456789", err: ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Decode(tt.number)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Decode(%q) error = %v, want %v", tt.number, err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if d.Kind != models.VINKindChassis {
+				t.Fatalf("Decode(%q).Kind = %q, want %q", tt.number, d.Kind, models.VINKindChassis)
+			}
+			if d.Number != tt.want || Canonical(tt.number) != tt.want {
+				t.Errorf("Decode(%q).Number = %q, Canonical = %q, want %q", tt.number, d.Number, Canonical(tt.number), tt.want)
+			}
+			if d.ModelCode != tt.code {
+				t.Errorf("Decode(%q).ModelCode = %q, want %q", tt.number, d.ModelCode, tt.code)
+			}
+			if d.Make != tt.make || d.CheckDigitOK != tt.known {
+				t.Errorf("Decode(%q) make = %q known = %v, want %q %v", tt.number, d.Make, d.CheckDigitOK, tt.make, tt.known)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package vin
+
+import (
+	"carsawa/models"
+	"strings"
+	"time"
+)
+
+// yearCodes are the position-10 model-year characters, in order from 1980.
+// The cycle repeats every 30 years.
+const (
+	yearCodes     = "ABCDEFGHJKLMNPRSTVWXY123456789"
+	firstCodeYear = 1980
+)
+
+// modelYears lists the model years position 10 can stand for, newest first.
+// North American VINs settle the cycle with position 7: a digit for
+// 1980-2009, a letter from 2010 on.
+func modelYears(vin string) []int {
+	idx := strings.IndexByte(yearCodes, vin[9])
+	if idx < 0 {
+		return nil
+	}
+	year := firstCodeYear + idx
+	if vin[0] >= '1' && vin[0] <= '5' {
+		if c := vin[6]; c < '0' || c > '9' {
+			year += len(yearCodes)
+		}
+		return []int{year}
+	}
+
+	latest := time.Now().Year() + 1
+	var years []int
+	for y := year; y <= latest; y += len(yearCodes) {
+		years = append([]int{y}, years...)
+	}
+	return years
+}
+
+// MatchesYear reports whether a claimed year fits the decoded model years.
+// Model and build year can differ by one. Position 10 is only mandatory in
+// North America and China, so other VINs are held to it only once a
+// registry has confirmed the year.
+func MatchesYear(d *models.DecodedVIN, year int) bool {
+	if len(d.ModelYears) == 0 {
+		return true
+	}
+	if !d.Verified && (d.Kind != models.VINKindVIN || !requiresCheckDigit(d.Number)) {
+		return true
+	}
+	for _, y := range d.ModelYears {
+		if year >= y-1 && year <= y+1 {
+			return true
+		}
+	}
+	return false
+}
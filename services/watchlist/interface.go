@@ -0,0 +1,53 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+
+	listingRepo "carsawa/database/repository/listing"
+	watchlistRepo "carsawa/database/repository/watchlist"
+	"carsawa/models"
+	"carsawa/services/notification"
+)
+
+var ErrNotWatchable = errors.New("listing cannot be watched")
+
+const (
+	watcherBatch = 500
+	// maxInterestRows caps the dealer interest report.
+	maxInterestRows = 200
+)
+
+type WatchlistService interface {
+	// Watch and Unwatch are idempotent; the listing's watcher count only
+	// moves when the entry is actually added or removed.
+	Watch(ctx context.Context, userID, listingID string) error
+	Unwatch(ctx context.Context, userID, listingID string) error
+	GetWatchlist(ctx context.Context, userID string, pagination models.Pagination) ([]models.WatchedListing, error)
+	GetDealerInterest(ctx context.Context, dealerID string) ([]models.ListingInterest, error)
+
+	// NotifyPriceDrop tells every watcher a listing is now cheaper than oldPrice.
+	NotifyPriceDrop(ctx context.Context, lst *models.Listing, oldPrice float64)
+	// NotifyOffMarket tells every watcher a listing has been sold or closed.
+	NotifyOffMarket(ctx context.Context, lst *models.Listing)
+	// ForgetListing drops the watchlist entries of a deleted listing.
+	ForgetListing(ctx context.Context, listingID string)
+}
+
+type watchlistService struct {
+	repo     watchlistRepo.WatchlistRepository
+	listings listingRepo.ListingRepository
+	notifier notification.NotificationService
+}
+
+func NewWatchlistService(
+	repo watchlistRepo.WatchlistRepository,
+	listings listingRepo.ListingRepository,
+	notifSvc notification.NotificationService,
+) WatchlistService {
+	return &watchlistService{
+		repo:     repo,
+		listings: listings,
+		notifier: notifSvc,
+	}
+}
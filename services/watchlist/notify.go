@@ -0,0 +1,76 @@
+package watchlist
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"carsawa/models"
+	"carsawa/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func (s *watchlistService) NotifyPriceDrop(ctx context.Context, lst *models.Listing, oldPrice float64) {
+	newPrice := lst.CarDetails.Price
+	if newPrice <= 0 || newPrice >= oldPrice {
+		return
+	}
+	body := fmt.Sprintf("The %s you are watching dropped from %.2f to %.2f.", carName(lst), oldPrice, newPrice)
+	s.notifyWatchers(ctx, lst, models.NotificationTypeWatchedPriceDrop, "Price Drop", body,
+		map[string]interface{}{"listingID": lst.ID.Hex(), "oldPrice": oldPrice, "newPrice": newPrice})
+}
+
+func (s *watchlistService) NotifyOffMarket(ctx context.Context, lst *models.Listing) {
+	var (
+		ntype models.NotificationType
+		title string
+		body  string
+	)
+	switch lst.Status {
+	case models.ListingStatusSold:
+		ntype, title = models.NotificationTypeWatchedSold, "Watched Car Sold"
+		body = fmt.Sprintf("The %s you are watching has been sold.", carName(lst))
+	case models.ListingStatusClosed:
+		ntype, title = models.NotificationTypeWatchedClosed, "Watched Listing Closed"
+		body = fmt.Sprintf("The %s you are watching is no longer available.", carName(lst))
+	default:
+		return
+	}
+	s.notifyWatchers(ctx, lst, ntype, title, body,
+		map[string]interface{}{"listingID": lst.ID.Hex(), "status": lst.Status})
+}
+
+// notifyWatchers sends one notification to each user watching lst.
+func (s *watchlistService) notifyWatchers(
+	ctx context.Context,
+	lst *models.Listing,
+	ntype models.NotificationType,
+	title, body string,
+	data map[string]interface{},
+) {
+	var after primitive.ObjectID
+	for {
+		users, next, err := s.repo.GetWatcherIDs(ctx, lst.ID, after, watcherBatch)
+		if err != nil {
+			utils.GetLogger().Error("notifyWatchers: failed to load watchers",
+				zap.String("listingID", lst.ID.Hex()), zap.Error(err))
+			return
+		}
+		for _, uid := range users {
+			if err := s.notifier.CreateUserNotification(ctx, uid.Hex(), ntype, title, body, data); err != nil {
+				fmt.Printf("notifyUser error: %v\n", err)
+			}
+		}
+		if len(users) < watcherBatch {
+			return
+		}
+		after = next
+	}
+}
+
+func carName(lst *models.Listing) string {
+	cd := lst.CarDetails
+	return strings.TrimSpace(fmt.Sprintf("%d %s %s", cd.Year, cd.Make, cd.Model))
+}
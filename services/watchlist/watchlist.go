@@ -0,0 +1,145 @@
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	listingRepo "carsawa/database/repository/listing"
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func parseUserID(userHex string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(userHex)
+	if err != nil {
+		return primitive.NilObjectID, errors.New("invalid user ID format")
+	}
+	return id, nil
+}
+
+// watchable reports whether a listing in status can be added to a watchlist.
+func watchable(status models.ListingStatus) bool {
+	switch status {
+	case models.ListingStatusDraft, models.ListingStatusSold, models.ListingStatusClosed:
+		return false
+	}
+	return true
+}
+
+func (s *watchlistService) Watch(ctx context.Context, userID, listingID string) error {
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+	lst, err := s.listings.GetListingByID(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if !watchable(lst.Status) {
+		return ErrNotWatchable
+	}
+
+	added, err := s.repo.AddEntry(ctx, &models.WatchlistEntry{
+		UserID:       uid,
+		ListingID:    lst.ID,
+		PriceAtWatch: lst.CarDetails.Price,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil || !added {
+		return err
+	}
+	if err := s.listings.IncrementWatchers(ctx, listingID, 1); err != nil {
+		// Drop the entry again so a retry is counted.
+		if _, rerr := s.repo.RemoveEntry(ctx, uid, lst.ID); rerr != nil {
+			return fmt.Errorf("%w (and failed to undo watch: %v)", err, rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *watchlistService) Unwatch(ctx context.Context, userID, listingID string) error {
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return err
+	}
+	lid, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return listingRepo.ErrInvalidID
+	}
+
+	removed, err := s.repo.RemoveEntry(ctx, uid, lid)
+	if err != nil || removed == nil {
+		return err
+	}
+	// The listing may have been deleted since it was watched.
+	if err := s.listings.IncrementWatchers(ctx, listingID, -1); err != nil && !errors.Is(err, listingRepo.ErrNotFound) {
+		// Put the entry back so a retry is uncounted.
+		if _, rerr := s.repo.AddEntry(ctx, removed); rerr != nil {
+			return fmt.Errorf("%w (and failed to undo unwatch: %v)", err, rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetWatchlist returns the user's watched listings, most recently watched
+// first. Entries whose listing has since been deleted are left out.
+func (s *watchlistService) GetWatchlist(ctx context.Context, userID string, pagination models.Pagination) ([]models.WatchedListing, error) {
+	uid, err := parseUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetUserEntries(ctx, uid, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ListingID
+	}
+	listings, err := s.listings.GetListingsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]models.Listing, len(listings))
+	for _, l := range listings {
+		byID[l.ID] = l
+	}
+
+	watched := make([]models.WatchedListing, 0, len(entries))
+	for _, e := range entries {
+		l, ok := byID[e.ListingID]
+		if !ok {
+			continue
+		}
+		w := models.WatchedListing{Listing: l, WatchedAt: e.CreatedAt, PriceAtWatch: e.PriceAtWatch}
+		if e.PriceAtWatch > 0 && l.CarDetails.Price > 0 {
+			w.PriceChange = l.CarDetails.Price - e.PriceAtWatch
+		}
+		watched = append(watched, w)
+	}
+	return watched, nil
+}
+
+func (s *watchlistService) GetDealerInterest(ctx context.Context, dealerID string) ([]models.ListingInterest, error) {
+	did, err := primitive.ObjectIDFromHex(dealerID)
+	if err != nil {
+		return nil, errors.New("invalid dealer ID format")
+	}
+	return s.listings.GetDealerInterest(ctx, did, maxInterestRows)
+}
+
+func (s *watchlistService) ForgetListing(ctx context.Context, listingID string) {
+	lid, err := primitive.ObjectIDFromHex(listingID)
+	if err != nil {
+		return
+	}
+	if err := s.repo.RemoveListing(ctx, lid); err != nil {
+		fmt.Printf("ForgetListing error: %v\n", err)
+	}
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"carsawa/models"
+	"context"
+)
+
+type auditActorKey struct{}
+
+// WithAuditActor records who is acting for the rest of the request.
+func WithAuditActor(ctx context.Context, actor models.AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFrom returns the actor set by WithAuditActor, or the system
+// when there is none, as in background jobs.
+func AuditActorFrom(ctx context.Context) models.AuditActor {
+	if actor, ok := ctx.Value(auditActorKey{}).(models.AuditActor); ok {
+		return actor
+	}
+	return models.AuditActor{Type: models.ActorSystem}
+}
@@ -12,6 +12,8 @@ import (
 )
 
 var (
+	// CacheClient is the general-purpose cache client.
+	CacheClient *redis.Client
 	// AuthCacheClient is the dedicated client for authorization caching.
 	AuthCacheClient *redis.Client
 	// OTPCacheClient is the dedicated client for caching OTPs.
@@ -23,6 +25,31 @@ var (
 const AuthCachePrefix = "auth:"
 const AuthCacheTTL = 10 * time.Minute
 
+// InitCache initializes the general-purpose Redis client using the cache DB from AppConfig.
+func InitCache() {
+	log.Printf("Attempting to connect to Redis (Cache) at %s using DB %d", config.AppConfig.RedisAddr, config.AppConfig.RedisCacheDB)
+	CacheClient = redis.NewClient(&redis.Options{
+		Addr:     config.AppConfig.RedisAddr,
+		Password: config.AppConfig.RedisPassword,
+		DB:       config.AppConfig.RedisCacheDB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := CacheClient.Ping(ctx).Result()
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis (Cache): %v", err)
+	}
+	log.Println("Connected to Redis (Cache) successfully.")
+}
+
+// GetCacheClient returns the general-purpose Redis client.
+func GetCacheClient() *redis.Client {
+	if CacheClient == nil {
+		InitCache()
+	}
+	return CacheClient
+}
+
 // InitAuthCache initializes the Redis client for authorization caching using the DB from AppConfig for auth cache.
 func InitAuthCache() {
 	log.Printf("Attempting to connect to Redis (Auth Cache) at %s using DB %d", config.AppConfig.RedisAddr, config.AppConfig.RedisAuthDB)
@@ -104,6 +131,7 @@ func GetTestCacheClient() *redis.Client {
 
 // InitRedis initializes all Redis clients at once.
 func InitRedis() {
+	InitCache()
 	InitAuthCache()
 	InitOTPCache()
 	InitTestCache()
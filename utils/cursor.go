@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"carsawa/config"
+	"carsawa/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidCursor = errors.New("invalid or tampered page cursor")
+
+// cursorSecret signs page cursors, falling back to the JWT secret so a
+// deployment without CURSOR_SECRET still refuses forged cursors.
+func cursorSecret() []byte {
+	if s := config.AppConfig.CursorSecret; s != "" {
+		return []byte(s)
+	}
+	return []byte(config.AppConfig.JWTSecret)
+}
+
+// EncodeCursor turns a cursor into an opaque, signed token for clients.
+// BSON keeps the type of the sort key, such as a date, across the round trip.
+func EncodeCursor(c *models.Cursor) string {
+	if c == nil {
+		return ""
+	}
+	payload, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + signCursor(body)
+}
+
+// DecodeCursor verifies and unpacks a token made by EncodeCursor.
+func DecodeCursor(token string) (*models.Cursor, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(signCursor(body))) {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c models.Cursor
+	if err := bson.Unmarshal(payload, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// EncodePage builds the response tokens for a page's cursors.
+func EncodePage(cursors models.PageCursors) models.Page {
+	return models.Page{
+		NextCursor: EncodeCursor(cursors.Next),
+		PrevCursor: EncodeCursor(cursors.Prev),
+	}
+}
+
+func signCursor(body string) string {
+	mac := hmac.New(sha256.New, cursorSecret())
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"carsawa/config"
+	"carsawa/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	config.AppConfig.CursorSecret = "cursor-test-secret"
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   *models.Cursor
+		key  interface{}
+	}{
+		{name: "date key", in: &models.Cursor{Sort: "createdAt", Key: created, ID: primitive.NewObjectID()}, key: primitive.NewDateTimeFromTime(created)},
+		{name: "number key backwards", in: &models.Cursor{Sort: "price", Key: 1500000.0, ID: primitive.NewObjectID(), Before: true}, key: 1500000.0},
+		{name: "no key", in: &models.Cursor{Sort: "createdAt", ID: primitive.NewObjectID()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(EncodeCursor(tt.in))
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if got.Sort != tt.in.Sort || got.ID != tt.in.ID || got.Before != tt.in.Before || got.Key != tt.key {
+				t.Errorf("DecodeCursor() = %+v, want %+v with key %v", got, tt.in, tt.key)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	config.AppConfig.CursorSecret = "cursor-test-secret"
+	token := EncodeCursor(&models.Cursor{Sort: "createdAt", Key: "a", ID: primitive.NewObjectID()})
+	body, sig, _ := strings.Cut(token, ".")
+	forged := EncodeCursor(&models.Cursor{Sort: "createdAt", Key: "b", ID: primitive.NewObjectID()})
+	forgedBody, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "unsigned", token: body},
+		{name: "empty signature", token: body + "."},
+		{name: "body swapped", token: forgedBody + "." + sig},
+		{name: "signature altered", token: body + "." + strings.ToUpper(sig)},
+		{name: "signed body is not base64", token: "!!!." + signCursor("!!!")},
+		{name: "signed body is not a cursor", token: "bm90LWJzb24." + signCursor("bm90LWJzb24")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.token); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) error = %v, want %v", tt.token, err, ErrInvalidCursor)
+			}
+		})
+	}
+
+	t.Run("signed with another secret", func(t *testing.T) {
+		config.AppConfig.CursorSecret = "another-secret"
+		defer func() { config.AppConfig.CursorSecret = "cursor-test-secret" }()
+		if _, err := DecodeCursor(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const lockPrefix = "lock:"
+
+// releaseScript deletes the lock only while it still holds our token, so a
+// holder whose lock has expired cannot free one another replica now holds.
+var releaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+
+// extendScript resets the lock's TTL while it still holds our token.
+var extendScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// Lock is a Redis lock held by one process at a time across replicas.
+type Lock struct {
+	rdb   *redis.Client
+	key   string
+	token string
+}
+
+// AcquireLock takes the named lock for ttl. It returns nil without an error
+// when another holder has it.
+func AcquireLock(ctx context.Context, rdb *redis.Client, name string, ttl time.Duration) (*Lock, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	l := &Lock{rdb: rdb, key: lockPrefix + name, token: hex.EncodeToString(buf)}
+	ok, err := rdb.SetNX(ctx, l.key, l.token, ttl).Result()
+	if err != nil || !ok {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Extend keeps the lock for another ttl, reporting false if it was lost.
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) (bool, error) {
+	n, err := extendScript.Run(ctx, l.rdb, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	return n == 1, err
+}
+
+// Release frees the lock if it is still ours.
+func (l *Lock) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.rdb, []string{l.key}, l.token).Err()
+}
+
+// RunLockedJob runs job straight away and then every interval until ctx is
+// cancelled. Each run first takes the job's lock, so with several replicas
+// running the same schedule only one of them does the work per interval.
+// The lock is kept alive while a long run is in progress and, once the run
+// is over, held until the interval is up.
+func RunLockedJob(ctx context.Context, rdb *redis.Client, name string, interval time.Duration, job func(context.Context) error) {
+	run := func() {
+		lock, err := AcquireLock(ctx, rdb, "job:"+name, interval)
+		if err != nil {
+			GetLogger().Error("job lock failed", zap.String("job", name), zap.Error(err))
+			return
+		}
+		if lock == nil {
+			return // another replica has this run
+		}
+		start := time.Now()
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			keepLock(runCtx, lock, name, interval, cancel)
+		}()
+		if err := job(runCtx); err != nil && ctx.Err() == nil {
+			GetLogger().Error("job run failed", zap.String("job", name), zap.Error(err))
+		}
+		cancel()
+		<-done
+
+		// the lock gates this interval; free it early only if the run overran
+		if rest := interval - time.Since(start); rest > 0 {
+			_, err = lock.Extend(context.Background(), rest)
+		} else {
+			err = lock.Release(context.Background())
+		}
+		if err != nil {
+			GetLogger().Warn("job lock not released", zap.String("job", name), zap.Error(err))
+		}
+	}
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// keepLock extends the lock every third of ttl until ctx is done. Should
+// the lock be lost, it stops the run through cancel rather than let two
+// replicas work at once.
+func keepLock(ctx context.Context, lock *Lock, name string, ttl time.Duration, cancel context.CancelFunc) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if ok, err := lock.Extend(ctx, ttl); err != nil || !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				GetLogger().Warn("job lock lost, stopping run", zap.String("job", name), zap.Error(err))
+				cancel()
+				return
+			}
+		}
+	}
+}
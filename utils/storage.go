@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"carsawa/config"
+	"carsawa/services/storage"
+	"fmt"
+	"strings"
+)
+
+// Storage returns the StorageService selected by STORAGE_BACKEND.
+func Storage() (storage.StorageService, error) {
+	backend := strings.ToLower(strings.TrimSpace(config.AppConfig.StorageBackend))
+	if backend == "" || backend == "cloudinary" {
+		return Cloudinary()
+	}
+
+	signer, err := storage.NewURLSigner(config.AppConfig.StoragePublicURL, config.AppConfig.StorageSigningSecret)
+	if err != nil {
+		return nil, fmt.Errorf("utils.Storage: %w", err)
+	}
+
+	switch backend {
+	case "local":
+		return storage.NewLocalStorageService(config.AppConfig.StorageLocalDir, signer)
+	case "s3":
+		return storage.NewS3StorageService(storage.S3Config{
+			Endpoint:  config.AppConfig.S3Endpoint,
+			Region:    config.AppConfig.S3Region,
+			Bucket:    config.AppConfig.S3Bucket,
+			AccessKey: config.AppConfig.S3AccessKey,
+			SecretKey: config.AppConfig.S3SecretKey,
+			UseSSL:    config.AppConfig.S3UseSSL,
+			PathStyle: config.AppConfig.S3PathStyle,
+		}, signer)
+	default:
+		return nil, fmt.Errorf("utils.Storage: unknown storage backend %q", backend)
+	}
+}